@@ -0,0 +1,260 @@
+// Package appstoreconnect compares a parsed .ipa against the versions of
+// its app on App Store Connect, so a CI job can fail before an upload is
+// rejected for a duplicate or lower build number.
+package appstoreconnect
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/follyxing/appfile-info"
+)
+
+// DefaultBaseURL is the App Store Connect API.
+const DefaultBaseURL = "https://api.appstoreconnect.apple.com"
+
+var ErrNotFound = errors.New("appstoreconnect: app not found")
+
+// APIError is an error response of the API.
+type APIError struct {
+	Status int
+	Code   string
+	Detail string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("appstoreconnect: %d %s: %s", e.Status, e.Code, e.Detail)
+}
+
+// Client queries the API with an API key created under Users and Access.
+type Client struct {
+	KeyID    string
+	IssuerID string
+	Key      *ecdsa.PrivateKey
+
+	BaseURL    string       // DefaultBaseURL if empty
+	HTTPClient *http.Client // http.DefaultClient if nil
+}
+
+// NewClient returns a Client for the key keyID of issuerID, whose private
+// key p8 is the AuthKey_<keyID>.p8 file downloaded when it was created.
+func NewClient(keyID, issuerID string, p8 []byte) (*Client, error) {
+	block, _ := pem.Decode(p8)
+	if block == nil {
+		return nil, errors.New("appstoreconnect: no PEM key")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	ec, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("appstoreconnect: not an EC key")
+	}
+	return &Client{KeyID: keyID, IssuerID: issuerID, Key: ec}, nil
+}
+
+// Release is the latest state of an app on App Store Connect. Empty
+// fields mean there is no such version yet.
+type Release struct {
+	AppID string `json:"app_id"`
+	// LiveVersion is the CFBundleShortVersionString ready for sale.
+	LiveVersion string `json:"live_version,omitempty"`
+	// TestFlightVersion and TestFlightBuild are the CFBundleShortVersionString
+	// and CFBundleVersion of the last uploaded build.
+	TestFlightVersion string `json:"testflight_version,omitempty"`
+	TestFlightBuild   string `json:"testflight_build,omitempty"`
+}
+
+// Comparison relates a build to the Release of its app.
+type Comparison struct {
+	Release
+	Version string `json:"version"`
+	Build   string `json:"build"`
+	// NewerVersion is set if Version is above LiveVersion, which a build
+	// submitted for review needs.
+	NewerVersion bool `json:"newer_version"`
+	// NewerBuild is set if Version and Build are above those of the last
+	// TestFlight build.
+	NewerBuild bool `json:"newer_build"`
+	// Duplicate is set if a build with this Version and Build was already
+	// uploaded; App Store Connect rejects it.
+	Duplicate bool `json:"duplicate"`
+}
+
+// Latest returns the Release of the app bundleId.
+func (c *Client) Latest(ctx context.Context, bundleId string) (*Release, error) {
+	var apps struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := c.get(ctx, "/v1/apps", url.Values{"filter[bundleId]": {bundleId}}, &apps); err != nil {
+		return nil, err
+	}
+	if len(apps.Data) == 0 {
+		return nil, ErrNotFound
+	}
+	r := &Release{AppID: apps.Data[0].ID}
+
+	var live versionsResponse
+	q := url.Values{"filter[appStoreState]": {"READY_FOR_SALE"}, "limit": {"1"}}
+	if err := c.get(ctx, "/v1/apps/"+r.AppID+"/appStoreVersions", q, &live); err != nil {
+		return nil, err
+	}
+	if len(live.Data) > 0 {
+		r.LiveVersion = live.Data[0].Attributes.VersionString
+	}
+
+	var builds buildsResponse
+	q = url.Values{
+		"filter[app]": {r.AppID},
+		"sort":        {"-uploadedDate"},
+		"limit":       {"1"},
+		"include":     {"preReleaseVersion"},
+	}
+	if err := c.get(ctx, "/v1/builds", q, &builds); err != nil {
+		return nil, err
+	}
+	if len(builds.Data) > 0 {
+		r.TestFlightBuild = builds.Data[0].Attributes.Version
+		if len(builds.Included) > 0 {
+			r.TestFlightVersion = builds.Included[0].Attributes.Version
+		}
+	}
+	return r, nil
+}
+
+// Compare compares the version and build of info, the parsed .ipa, to the
+// Release of its app.
+func (c *Client) Compare(ctx context.Context, info *appfile.AppInfo) (*Comparison, error) {
+	r, err := c.Latest(ctx, info.BundleId)
+	if err != nil {
+		return nil, err
+	}
+	cmp := &Comparison{
+		Release:      *r,
+		Version:      info.Version,
+		Build:        info.Build,
+		NewerVersion: r.LiveVersion == "" || appfile.CompareVersions(info.Version, r.LiveVersion) > 0,
+		NewerBuild:   r.TestFlightBuild == "" || newer(info.Version, info.Build, r.TestFlightVersion, r.TestFlightBuild),
+	}
+
+	var builds buildsResponse
+	q := url.Values{
+		"filter[app]":                       {r.AppID},
+		"filter[version]":                   {info.Build},
+		"filter[preReleaseVersion.version]": {info.Version},
+		"limit":                             {"1"},
+	}
+	if err := c.get(ctx, "/v1/builds", q, &builds); err != nil {
+		return nil, err
+	}
+	cmp.Duplicate = len(builds.Data) > 0
+	return cmp, nil
+}
+
+// newer orders builds by version and then build number.
+func newer(version, build, prevVersion, prevBuild string) bool {
+	if c := appfile.CompareVersions(version, prevVersion); c != 0 {
+		return c > 0
+	}
+	return appfile.CompareVersions(build, prevBuild) > 0
+}
+
+type versionsResponse struct {
+	Data []struct {
+		Attributes struct {
+			VersionString string `json:"versionString"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+type buildsResponse struct {
+	Data []struct {
+		Attributes struct {
+			Version string `json:"version"`
+		} `json:"attributes"`
+	} `json:"data"`
+	Included []struct {
+		Attributes struct {
+			Version string `json:"version"`
+		} `json:"attributes"`
+	} `json:"included"`
+}
+
+func (c *Client) get(ctx context.Context, path string, q url.Values, v interface{}) error {
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	token, err := c.token(time.Now())
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodGet, base+path+"?"+q.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		var body struct {
+			Errors []struct {
+				Code   string `json:"code"`
+				Detail string `json:"detail"`
+			} `json:"errors"`
+		}
+		apiErr := &APIError{Status: resp.StatusCode, Detail: resp.Status}
+		if json.NewDecoder(resp.Body).Decode(&body) == nil && len(body.Errors) > 0 {
+			apiErr.Code, apiErr.Detail = body.Errors[0].Code, body.Errors[0].Detail
+		}
+		return apiErr
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// token returns the ES256 JWT the API authenticates requests with. Tokens
+// may be valid for at most 20 minutes.
+func (c *Client) token(now time.Time) (string, error) {
+	header, _ := json.Marshal(map[string]string{"alg": "ES256", "kid": c.KeyID, "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iss": c.IssuerID,
+		"iat": now.Unix(),
+		"exp": now.Add(10 * time.Minute).Unix(),
+		"aud": "appstoreconnect-v1",
+	})
+	enc := base64.RawURLEncoding
+	signed := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+
+	sum := sha256.Sum256([]byte(signed))
+	r, s, err := ecdsa.Sign(rand.Reader, c.Key, sum[:])
+	if err != nil {
+		return "", err
+	}
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	return signed + "." + enc.EncodeToString(sig), nil
+}
@@ -0,0 +1,108 @@
+package appstoreconnect
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/follyxing/appfile-info"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewClient("KEY123", "issuer", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		parts := strings.Split(token, ".")
+		sig, _ := base64.RawURLEncoding.DecodeString(parts[len(parts)-1])
+		sum := sha256.Sum256([]byte(strings.Join(parts[:2], ".")))
+		if len(parts) != 3 || len(sig) != 64 ||
+			!ecdsa.Verify(&key.PublicKey, sum[:], new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	c.BaseURL = srv.URL
+	return c
+}
+
+func TestCompare(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch {
+		case r.URL.Path == "/v1/apps" && q.Get("filter[bundleId]") == "com.example.app":
+			w.Write([]byte(`{"data":[{"id":"42"}]}`))
+		case r.URL.Path == "/v1/apps":
+			w.Write([]byte(`{"data":[]}`))
+		case r.URL.Path == "/v1/apps/42/appStoreVersions":
+			w.Write([]byte(`{"data":[{"attributes":{"versionString":"1.2"}}]}`))
+		case r.URL.Path == "/v1/builds" && q.Get("filter[version]") == "17":
+			w.Write([]byte(`{"data":[{"attributes":{"version":"17"}}]}`))
+		case r.URL.Path == "/v1/builds" && q.Get("filter[version]") != "":
+			w.Write([]byte(`{"data":[]}`))
+		case r.URL.Path == "/v1/builds":
+			w.Write([]byte(`{"data":[{"attributes":{"version":"17"}}],"included":[{"attributes":{"version":"1.3"}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"code":"NOT_FOUND","detail":"no such path"}]}`))
+		}
+	})
+	ctx := context.Background()
+
+	cmp, err := c.Compare(ctx, &appfile.AppInfo{BundleId: "com.example.app", Version: "1.3", Build: "18"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Release{AppID: "42", LiveVersion: "1.2", TestFlightVersion: "1.3", TestFlightBuild: "17"}
+	if cmp.Release != want || !cmp.NewerVersion || !cmp.NewerBuild || cmp.Duplicate {
+		t.Errorf("got %+v want newer build of %+v", cmp, want)
+	}
+
+	cmp, err = c.Compare(ctx, &appfile.AppInfo{BundleId: "com.example.app", Version: "1.3", Build: "17"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmp.NewerBuild || !cmp.Duplicate {
+		t.Errorf("got %+v want duplicate", cmp)
+	}
+
+	if _, err := c.Latest(ctx, "com.example.other"); err != ErrNotFound {
+		t.Errorf("got %v want %v", err, ErrNotFound)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"errors":[{"code":"FORBIDDEN_ERROR","detail":"no access"}]}`))
+	})
+	_, err := c.Latest(context.Background(), "com.example.app")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusForbidden || apiErr.Code != "FORBIDDEN_ERROR" {
+		t.Errorf("got %v want %v", err, &APIError{Status: http.StatusForbidden, Code: "FORBIDDEN_ERROR"})
+	}
+}
@@ -0,0 +1,235 @@
+package appfile
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var ErrNoEntry = errors.New("no matching entry")
+
+// Parser is an open .apk, .apks, .aab or .ipa archive that files can be
+// read from without reopening it. The manifest, resource table and
+// profile are decoded on first use and kept for later queries.
+//
+// A Parser is safe for concurrent use, e.g. shared by the handlers of a
+// web server: entries are read with ReadAt, which does not move a file
+// offset, and the decoded state is built under a lock and not modified
+// afterwards. Close must not be called while queries are running.
+type Parser struct {
+	name   string
+	file   *os.File
+	reader *zip.Reader
+
+	mu          sync.Mutex // guards the decoded state below
+	res         *apkResources
+	manifest    *androidManifest
+	manifestXML []xml.Token   // raw tokens of the manifest, replayed by WriteManifestXML
+	manifestRes *apkResources // resources the manifest references name
+	plist       *AppInfo      // Info.plist of an .ipa
+	profile     *ProvisioningProfile
+	profileErr  error
+}
+
+// OpenParser opens the archive name. The caller must Close it.
+func OpenParser(name string) (*Parser, error) {
+	file, _, reader, err := openZipFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return &Parser{name: name, file: file, reader: reader}, nil
+}
+
+func (p *Parser) Close() error {
+	return p.file.Close()
+}
+
+// androidManifest returns the decoded AndroidManifest.xml of an .apk.
+func (p *Parser) androidManifest() (*androidManifest, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.decodeManifest(); err != nil {
+		return nil, err
+	}
+	return p.manifest, nil
+}
+
+// decodeManifest decodes the manifest of an .apk, .apks or .aab on first
+// use. Its tokens are kept, so the manifest is decoded once for both
+// androidManifest and WriteManifestXML. p.mu must be held.
+func (p *Parser) decodeManifest() error {
+	if p.manifest != nil {
+		return nil
+	}
+	decoder, files, err := openManifestXML(p.name, p.reader)
+	if err != nil {
+		return err
+	}
+	tokens, err := rawTokens(decoder)
+	if err != nil {
+		return err
+	}
+	replay := tokenList(tokens)
+	manifest := new(androidManifest)
+	if err := xml.NewTokenDecoder(&replay).Decode(manifest); err != nil {
+		return err
+	}
+	switch {
+	case files == nil:
+	case strings.EqualFold(path.Ext(p.name), apksExt):
+		p.manifestRes = newApkResources(files)
+		p.manifestRes.load()
+	default:
+		p.manifestRes = p.loadResources()
+	}
+	p.manifest, p.manifestXML = manifest, tokens
+	return nil
+}
+
+// resources returns the resources of an .apk with the table already
+// loaded, so lookups only read it.
+func (p *Parser) resources() *apkResources {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.loadResources()
+}
+
+// loadResources is resources with p.mu held.
+func (p *Parser) loadResources() *apkResources {
+	if p.res == nil {
+		p.res = newApkResources(p.reader.File)
+		p.res.load()
+	}
+	return p.res
+}
+
+// Manifest returns what the manifest of the archive declares: the
+// AndroidManifest.xml of an .apk, .apks or .aab, with the label resolved,
+// or the Info.plist of an .ipa. Icons, profiles and scans are left out.
+func (p *Parser) Manifest() (_ *AppInfo, err error) {
+	defer recoverPanic(&err)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if strings.EqualFold(path.Ext(p.name), iosExt) {
+		if p.plist == nil {
+			var plistFile *zip.File
+			for _, f := range p.reader.File {
+				if reInfoPlist.MatchString(f.Name) {
+					plistFile = f
+					break
+				}
+			}
+			info, err := parseIpaFile(plistFile)
+			if err != nil {
+				return nil, err
+			}
+			p.plist = info
+		}
+		return p.plist.clone(), nil
+	}
+	if err := p.decodeManifest(); err != nil {
+		return nil, err
+	}
+	info := newAndroidAppInfo(p.manifest)
+	if p.manifestRes != nil {
+		info.Name, info.Android.LabelSource = apkLabel(p.manifestRes, p.manifest)
+	} else {
+		info.Name = p.manifest.Application.Label
+	}
+	return info, nil
+}
+
+// Entitlements returns the entitlements of the provisioning profile of an
+// .ipa, with ErrProfileUnverified when its signature does not verify. The
+// map is a deep copy the caller may modify, nested values included.
+func (p *Parser) Entitlements() (_ map[string]interface{}, err error) {
+	defer recoverPanic(&err)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.profile == nil {
+		var profileFile *zip.File
+		for _, f := range p.reader.File {
+			if strings.HasSuffix(f.Name, "/"+profileFileName) && strings.Count(f.Name, "/") == 2 {
+				profileFile = f
+				break
+			}
+		}
+		profile, err := parseIpaProfile(profileFile)
+		if profile == nil {
+			return nil, err
+		}
+		p.profile, p.profileErr = profile, err
+	}
+	entitlements, _ := deepCopy(reflect.ValueOf(p.profile.Entitlements)).Interface().(map[string]interface{})
+	return entitlements, p.profileErr
+}
+
+// WriteManifestXML is the package function WriteManifestXML for the open
+// archive.
+func (p *Parser) WriteManifestXML(w io.Writer) (err error) {
+	defer recoverPanic(&err)
+	replay, res, err := p.manifestTokens()
+	if err != nil {
+		return err
+	}
+	return writeIndentedXML(w, xml.NewTokenDecoder(&replay), res)
+}
+
+// manifestTokens returns a replay of the decoded manifest and the
+// resources it references, which are only read afterwards.
+func (p *Parser) manifestTokens() (tokenList, *apkResources, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.decodeManifest(); err != nil {
+		return nil, nil, err
+	}
+	return tokenList(p.manifestXML), p.manifestRes, nil
+}
+
+// Extract writes the contents of every file whose name, or one of its
+// parent directories, matches pattern, in archive order. The syntax is
+// that of path.Match, e.g. "Payload/*.app/Settings.bundle" or
+// "google-services.json". It returns the number of files written, and
+// ErrNoEntry if nothing matched.
+func (p *Parser) Extract(pattern string, w io.Writer) (n int, err error) {
+	defer recoverPanic(&err)
+	if _, err := path.Match(pattern, ""); err != nil {
+		return 0, err
+	}
+
+	for _, f := range p.reader.File {
+		if strings.HasSuffix(f.Name, "/") || !matchEntry(pattern, f.Name) {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return n, err
+		}
+		_, err = io.Copy(w, rc)
+		rc.Close()
+		if err != nil {
+			return n, err
+		}
+		n++
+	}
+	if n == 0 {
+		return 0, ErrNoEntry
+	}
+	return n, nil
+}
+
+func matchEntry(pattern, name string) bool {
+	for name != "." && name != "" {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+		name = path.Dir(name)
+	}
+	return false
+}
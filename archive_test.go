@@ -0,0 +1,159 @@
+package appfile
+
+import (
+	"bytes"
+	"image/jpeg"
+	"image/png"
+	"sync"
+	"testing"
+)
+
+func TestParserExtract(t *testing.T) {
+	p, err := OpenParser("testdata/helloworld.apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	var buf bytes.Buffer
+	n, err := p.Extract("res/anim/abc_fade_in.xml", &buf)
+	if err != nil || n != 1 || buf.Len() != 396 {
+		t.Errorf("got %v, %v, %v bytes want 1, no error, 396 bytes", n, err, buf.Len())
+	}
+
+	buf.Reset()
+	n, err = p.Extract("res/anim", &buf)
+	if err != nil || n != 10 {
+		t.Errorf("got %v, %v want 10, no error", n, err)
+	}
+
+	if _, err := p.Extract("google-services.json", &buf); err != ErrNoEntry {
+		t.Errorf("got %v want %v", err, ErrNoEntry)
+	}
+	if _, err := p.Extract("[", &buf); err == nil {
+		t.Errorf("got no error want %v", "bad pattern")
+	}
+}
+
+func TestParserExtractIcon(t *testing.T) {
+	p, err := OpenParser("testdata/helloworld.apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	var buf bytes.Buffer
+	if err := p.ExtractIcon(&buf, ""); err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("got %v want a png", err)
+	}
+	if w := img.Bounds().Dx(); w != 192 {
+		t.Errorf("got %v want %v", w, 192)
+	}
+
+	buf.Reset()
+	if err := p.ExtractIcon(&buf, "jpeg"); err != nil {
+		t.Errorf("got %v want no error", err)
+	}
+	if _, err := jpeg.Decode(&buf); err != nil {
+		t.Errorf("got %v want a jpeg", err)
+	}
+	if err := p.ExtractIcon(&buf, "bmp"); err != ErrUnsupportedFormat {
+		t.Errorf("got %v want %v", err, ErrUnsupportedFormat)
+	}
+}
+
+func TestParserQueries(t *testing.T) {
+	p, err := OpenParser("testdata/helloworld.apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	for i := 0; i < 2; i++ {
+		img, err := p.Icon()
+		if err != nil {
+			t.Fatalf("got %v want no error", err)
+		}
+		if w := img.Bounds().Dx(); w != 192 {
+			t.Errorf("got %v want %v", w, 192)
+		}
+	}
+	if p.manifest == nil || p.res == nil || !p.res.loaded {
+		t.Errorf("got no cached manifest and resources want them kept")
+	}
+
+	var got, want bytes.Buffer
+	if err := p.WriteManifestXML(&got); err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	WriteManifestXML("testdata/helloworld.apk", &want)
+	if got.String() != want.String() {
+		t.Errorf("got %q want %q", got.String(), want.String())
+	}
+	if p.manifestXML == nil || p.manifestRes != p.res {
+		t.Errorf("got no cached manifest tokens want them shared with the manifest")
+	}
+
+	info, err := p.Manifest()
+	if err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	if info.BundleId != "com.example.helloworld" || info.Name != "HelloWorld" || info.Version != "1.0" {
+		t.Errorf("got %v %v %v want %v %v %v", info.BundleId, info.Name, info.Version, "com.example.helloworld", "HelloWorld", "1.0")
+	}
+
+	if _, err := p.Entitlements(); err == nil {
+		t.Errorf("got no error want one for an APK")
+	}
+}
+
+func TestParserEntitlementsCopy(t *testing.T) {
+	p := &Parser{profile: &ProvisioningProfile{Entitlements: map[string]interface{}{
+		"keychain-access-groups": []interface{}{"AB12CD34EF.com.example"},
+	}}}
+	got, err := p.Entitlements()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got["keychain-access-groups"].([]interface{})[0] = "changed"
+	again, _ := p.Entitlements()
+	if g := again["keychain-access-groups"].([]interface{})[0]; g != "AB12CD34EF.com.example" {
+		t.Errorf("got %v want %v", g, "AB12CD34EF.com.example")
+	}
+}
+
+func TestParserConcurrent(t *testing.T) {
+	p, err := OpenParser("testdata/helloworld.apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := p.Icon()
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			var buf bytes.Buffer
+			_, err := p.Extract("res/anim", &buf)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("got %v want no error", err)
+		}
+	}
+}
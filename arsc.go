@@ -0,0 +1,462 @@
+package appfile
+
+import (
+	"encoding/binary"
+	"errors"
+	"unicode/utf16"
+)
+
+// Chunk and value types of the resource table format, see
+// frameworks/base/libs/androidfw/include/androidfw/ResourceTypes.h.
+const (
+	resStringPoolType    = 0x0001
+	resTableType         = 0x0002
+	resTablePackageType  = 0x0200
+	resTableTypeType     = 0x0201
+	resTableTypeSpecType = 0x0202
+
+	resValueReference  = 0x01
+	resValueAttribute  = 0x02
+	resValueString     = 0x03
+	resValueColorFirst = 0x1c
+	resValueColorLast  = 0x1f
+)
+
+var errARSC = errors.New("malformed resources.arsc")
+
+var le = binary.LittleEndian
+
+// arscTable is a decoded resources.arsc. androidbinary resolves simple
+// values only; styles and per-configuration statistics need the raw table.
+type arscTable struct {
+	strings   []string
+	resources map[uint32][]arscResource
+	ids       map[string]uint32 // "type/name" of the app packages
+	packages  []*arscPackage
+}
+
+type arscPackage struct {
+	id        uint8
+	name      string
+	typeNames []string
+	keyNames  []string
+}
+
+// arscResource is the value of a resource in one configuration.
+type arscResource struct {
+	config arscConfig
+	*arscEntry
+}
+
+type arscEntry struct {
+	key   string
+	value arscValue
+
+	// bag entries such as styles
+	bag    bool
+	parent uint32
+	items  []arscBagItem
+}
+
+type arscValue struct {
+	typ  uint8
+	data uint32
+}
+
+type arscBagItem struct {
+	name  uint32
+	value arscValue
+}
+
+// arscConfig is a raw ResTable_config, starting with its size.
+type arscConfig []byte
+
+func (c arscConfig) isDefault() bool {
+	for _, b := range c[4:] {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (c arscConfig) uint16(off int) uint16 {
+	if len(c) < off+2 {
+		return 0
+	}
+	return le.Uint16(c[off:])
+}
+
+func (c arscConfig) density() uint16 { return c.uint16(14) }
+
+func (c arscConfig) locale() string {
+	if len(c) < 12 || c[8] == 0 {
+		return ""
+	}
+	l := unpackLocale(c[8], c[9], 'a')
+	if c[10] != 0 {
+		l += "-r" + unpackLocale(c[10], c[11], '0')
+	}
+	return l
+}
+
+// unpackLocale decodes a language or region code, which is packed into 15
+// bits when it has three letters.
+func unpackLocale(a, b, base byte) string {
+	if a&0x80 == 0 {
+		return string([]byte{a, b})
+	}
+	first := b & 0x1f
+	second := b>>5 | (a&0x03)<<3
+	third := (a & 0x7c) >> 2
+	return string([]byte{base + first, base + second, base + third})
+}
+
+func parseARSC(b []byte) (*arscTable, error) {
+	if len(b) < 12 || le.Uint16(b) != resTableType {
+		return nil, errARSC
+	}
+	headerSize, size := int(le.Uint16(b[2:])), int(le.Uint32(b[4:]))
+	if size > len(b) || headerSize > size {
+		return nil, errARSC
+	}
+
+	t := &arscTable{resources: make(map[uint32][]arscResource), ids: make(map[string]uint32)}
+	err := arscChunks(b[headerSize:size], func(typ uint16, chunk []byte) error {
+		var err error
+		switch typ {
+		case resStringPoolType:
+			t.strings, err = parseStringPool(chunk)
+		case resTablePackageType:
+			err = t.parsePackage(chunk)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func arscChunks(b []byte, fn func(typ uint16, chunk []byte) error) error {
+	for len(b) >= 8 {
+		size := le.Uint32(b[4:])
+		if size < 8 || uint64(size) > uint64(len(b)) {
+			return errARSC
+		}
+		if err := fn(le.Uint16(b), b[:size]); err != nil {
+			return err
+		}
+		b = b[size:]
+	}
+	return nil
+}
+
+func (t *arscTable) parsePackage(b []byte) error {
+	if len(b) < 284 {
+		return errARSC
+	}
+	p := &arscPackage{id: uint8(le.Uint32(b[8:]))}
+	name := make([]uint16, 0, 128)
+	for i := 12; i < 268; i += 2 {
+		c := le.Uint16(b[i:])
+		if c == 0 {
+			break
+		}
+		name = append(name, c)
+	}
+	p.name = string(utf16.Decode(name))
+	t.packages = append(t.packages, p)
+
+	typeStrings, keyStrings := int(le.Uint32(b[268:])), int(le.Uint32(b[276:]))
+	headerSize := int(le.Uint16(b[2:]))
+	if headerSize > len(b) {
+		return errARSC
+	}
+
+	off := headerSize
+	return arscChunks(b[headerSize:], func(typ uint16, chunk []byte) error {
+		start := off
+		off += len(chunk)
+
+		var err error
+		switch typ {
+		case resStringPoolType:
+			switch start {
+			case typeStrings:
+				p.typeNames, err = parseStringPool(chunk)
+			case keyStrings:
+				p.keyNames, err = parseStringPool(chunk)
+			}
+		case resTableTypeType:
+			err = t.parseType(p, chunk)
+		}
+		return err
+	})
+}
+
+func (t *arscTable) parseType(p *arscPackage, b []byte) error {
+	if len(b) < 24 {
+		return errARSC
+	}
+	headerSize := int(le.Uint16(b[2:]))
+	typeID, flags := b[8], b[9]
+	count, entriesStart := int(le.Uint32(b[12:])), int(le.Uint32(b[16:]))
+	configSize := int(le.Uint32(b[20:]))
+	if headerSize > len(b) || configSize < 4 || 20+configSize > headerSize || entriesStart > len(b) {
+		return errARSC
+	}
+	config := arscConfig(b[20 : 20+configSize])
+
+	typeName := ""
+	if int(typeID) >= 1 && int(typeID) <= len(p.typeNames) {
+		typeName = p.typeNames[typeID-1]
+	}
+
+	entry := func(index, offset int) error {
+		e, err := p.parseEntry(b[entriesStart:], offset)
+		if err != nil || e == nil {
+			return err
+		}
+		id := uint32(p.id)<<24 | uint32(typeID)<<16 | uint32(index)
+		t.resources[id] = append(t.resources[id], arscResource{config, e})
+		if p.id != 0x01 && typeName != "" {
+			t.ids[typeName+"/"+e.key] = id
+		}
+		return nil
+	}
+
+	offsets := b[headerSize:]
+	for i := 0; i < count; i++ {
+		var err error
+		switch {
+		case flags&0x01 != 0: // sparse: index and offset/4 pairs
+			if len(offsets) < 4*i+4 {
+				return errARSC
+			}
+			err = entry(int(le.Uint16(offsets[4*i:])), int(le.Uint16(offsets[4*i+2:]))*4)
+		case flags&0x02 != 0: // 16 bit offset/4
+			if len(offsets) < 2*i+2 {
+				return errARSC
+			}
+			if o := le.Uint16(offsets[2*i:]); o != 0xffff {
+				err = entry(i, int(o)*4)
+			}
+		default:
+			if len(offsets) < 4*i+4 {
+				return errARSC
+			}
+			if o := le.Uint32(offsets[4*i:]); o != 0xffffffff {
+				err = entry(i, int(o))
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *arscPackage) parseEntry(b []byte, off int) (*arscEntry, error) {
+	if off < 0 || off+8 > len(b) {
+		return nil, errARSC
+	}
+	size, flags := int(le.Uint16(b[off:])), le.Uint16(b[off+2:])
+	key := le.Uint32(b[off+4:])
+
+	e := new(arscEntry)
+	switch {
+	case flags&0x08 != 0: // compact: the key index and value share the header
+		e.key = p.keyName(uint32(size))
+		e.value = arscValue{typ: uint8(flags >> 8), data: key}
+	case flags&0x01 != 0:
+		if off+16 > len(b) {
+			return nil, errARSC
+		}
+		e.key = p.keyName(key)
+		e.bag = true
+		e.parent = le.Uint32(b[off+8:])
+		count := int(le.Uint32(b[off+12:]))
+		items := off + size
+		if count < 0 || items+12*count > len(b) {
+			return nil, errARSC
+		}
+		for i := 0; i < count; i++ {
+			item := b[items+12*i:]
+			e.items = append(e.items, arscBagItem{
+				name:  le.Uint32(item),
+				value: arscValue{typ: item[7], data: le.Uint32(item[8:])},
+			})
+		}
+	default:
+		if off+size+8 > len(b) {
+			return nil, errARSC
+		}
+		e.key = p.keyName(key)
+		v := b[off+size:]
+		e.value = arscValue{typ: v[3], data: le.Uint32(v[4:])}
+	}
+	return e, nil
+}
+
+func (p *arscPackage) keyName(i uint32) string {
+	if int(i) < len(p.keyNames) {
+		return p.keyNames[i]
+	}
+	return ""
+}
+
+func parseStringPool(b []byte) ([]string, error) {
+	if len(b) < 28 {
+		return nil, errARSC
+	}
+	headerSize := int(le.Uint16(b[2:]))
+	count := int(le.Uint32(b[8:]))
+	isUTF8 := le.Uint32(b[16:])&0x100 != 0
+	start := int(le.Uint32(b[20:]))
+	if count < 0 || headerSize+4*count > len(b) {
+		return nil, errARSC
+	}
+
+	strs := make([]string, count)
+	for i := range strs {
+		p := start + int(le.Uint32(b[headerSize+4*i:]))
+		if isUTF8 {
+			// UTF-16 length, then UTF-8 length, each 1 or 2 bytes
+			for skip := 0; skip < 2; skip++ {
+				if p+2 > len(b) {
+					return nil, errARSC
+				}
+				n := int(b[p])
+				p++
+				if n&0x80 != 0 {
+					n = (n&0x7f)<<8 | int(b[p])
+					p++
+				}
+				if skip == 1 {
+					if p+n > len(b) {
+						return nil, errARSC
+					}
+					strs[i] = string(b[p : p+n])
+				}
+			}
+			continue
+		}
+
+		if p+2 > len(b) {
+			return nil, errARSC
+		}
+		n := int(le.Uint16(b[p:]))
+		p += 2
+		if n&0x8000 != 0 {
+			if p+2 > len(b) {
+				return nil, errARSC
+			}
+			n = (n&0x7fff)<<16 | int(le.Uint16(b[p:]))
+			p += 2
+		}
+		if p+2*n > len(b) {
+			return nil, errARSC
+		}
+		u := make([]uint16, n)
+		for j := range u {
+			u[j] = le.Uint16(b[p+2*j:])
+		}
+		strs[i] = string(utf16.Decode(u))
+	}
+	return strs, nil
+}
+
+// entry returns the value of id in the default configuration, or in the
+// first one defining it.
+func (t *arscTable) entry(id uint32) *arscEntry {
+	rs := t.resources[id]
+	for _, r := range rs {
+		if r.config.isDefault() {
+			return r.arscEntry
+		}
+	}
+	if len(rs) > 0 {
+		return rs[0].arscEntry
+	}
+	return nil
+}
+
+// name returns the "type/name" of id.
+func (t *arscTable) name(id uint32) string {
+	e := t.entry(id)
+	if e == nil {
+		return ""
+	}
+	for _, p := range t.packages {
+		typeID := int(id >> 16 & 0xff)
+		if uint32(p.id) == id>>24 && typeID >= 1 && typeID <= len(p.typeNames) {
+			return p.typeNames[typeID-1] + "/" + e.key
+		}
+	}
+	return ""
+}
+
+func (t *arscTable) string(v arscValue) string {
+	if v.typ == resValueString && int(v.data) < len(t.strings) {
+		return t.strings[v.data]
+	}
+	return ""
+}
+
+// resolveString returns the string e holds, following references.
+func (t *arscTable) resolveString(e *arscEntry) string {
+	for depth := 0; e != nil && !e.bag && depth < 32; depth++ {
+		switch e.value.typ {
+		case resValueString:
+			return t.string(e.value)
+		case resValueReference:
+			e = t.entry(e.value.data)
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+// themeAttr looks attr up in theme and its parents.
+func (t *arscTable) themeAttr(theme, attr uint32) (arscValue, bool) {
+	for depth := 0; theme != 0 && depth < 32; depth++ {
+		e := t.entry(theme)
+		if e == nil || !e.bag {
+			break
+		}
+		for _, item := range e.items {
+			if item.name == attr {
+				return item.value, true
+			}
+		}
+		theme = e.parent
+	}
+	return arscValue{}, false
+}
+
+// color resolves v to an ARGB color, following references and theme
+// attributes.
+func (t *arscTable) color(v arscValue, theme uint32) (uint32, bool) {
+	for depth := 0; depth < 32; depth++ {
+		switch {
+		case v.typ >= resValueColorFirst && v.typ <= resValueColorLast:
+			return v.data, true
+		case v.typ == resValueReference:
+			e := t.entry(v.data)
+			if e == nil || e.bag {
+				return 0, false
+			}
+			v = e.value
+		case v.typ == resValueAttribute:
+			var ok bool
+			if v, ok = t.themeAttr(theme, v.data); !ok {
+				return 0, false
+			}
+		default:
+			return 0, false
+		}
+	}
+	return 0, false
+}
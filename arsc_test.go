@@ -0,0 +1,53 @@
+package appfile
+
+import "testing"
+
+func TestParseARSC(t *testing.T) {
+	reader, err := getAppZipReader("testdata/helloworld.apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := readZipFile(findZipFile(reader.File, "resources.arsc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	table, err := parseARSC(buf)
+	if err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+
+	if id := table.ids["string/app_name"]; id != 0x7F060021 {
+		t.Errorf("got %x want %x", id, 0x7F060021)
+	}
+	if s := table.string(table.entry(0x7F060021).value); s != "HelloWorld" {
+		t.Errorf("got %v want %v", s, "HelloWorld")
+	}
+	if n := len(table.resources[0x7F030000]); n != 5 {
+		t.Errorf("got %v want %v", n, 5)
+	}
+	if _, err := parseARSC(buf[:100]); err == nil {
+		t.Errorf("got nil want error")
+	}
+
+	// a type chunk with a config too short for its size field
+	bad := append([]byte(nil), buf...)
+	pkg := int(le.Uint16(bad[2:])) + int(le.Uint32(bad[int(le.Uint16(bad[2:]))+4:]))
+	off := pkg + int(le.Uint16(bad[pkg+2:]))
+	for le.Uint16(bad[off:]) != resTableTypeType {
+		off += int(le.Uint32(bad[off+4:]))
+	}
+	le.PutUint32(bad[off+20:], 0)
+	if _, err := parseARSC(bad); err != errARSC {
+		t.Errorf("got %v want %v", err, errARSC)
+	}
+}
+
+func TestUnpackLocale(t *testing.T) {
+	if l := unpackLocale('e', 'n', 'a'); l != "en" {
+		t.Errorf("got %v want %v", l, "en")
+	}
+	// "fil", packed
+	if l := unpackLocale(0xad, 0x05, 'a'); l != "fil" {
+		t.Errorf("got %v want %v", l, "fil")
+	}
+}
@@ -0,0 +1,76 @@
+package appfile
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+)
+
+// Classes of the libraries that download expansion files and asset packs.
+// Apps without them cannot fetch the extra assets, so finding them in the
+// dex is a good hint that the app expects them.
+var (
+	expansionDexMarkers = [][]byte{
+		[]byte("Lcom/google/android/vending/expansion/downloader/"),
+		[]byte("Lcom/unity3d/plugin/downloader/"),
+	}
+	assetPackDexMarkers = [][]byte{
+		[]byte("Lcom/google/android/play/core/assetpacks/AssetPackManager;"),
+	}
+
+	kotlinDexMarkers         = [][]byte{[]byte("Lkotlin/Metadata;")}
+	androidxDexMarkers       = [][]byte{[]byte("Landroidx/")}
+	supportLibraryDexMarkers = [][]byte{[]byte("Landroid/support/")}
+)
+
+func (m *androidManifest) isAssetPack() bool {
+	return m.Module != nil && m.Module.Type == "asset-pack"
+}
+
+// scanDexFiles sets ExpansionFiles, AssetDelivery, Obfuscated and the
+// library flags from the dex files among files. Gradle also packages library version
+// and Kotlin module files, which are still there when R8 renamed classes.
+func scanDexFiles(files []*zip.File, android *AndroidInfo) error {
+	var obfuscated, classes int
+	for _, f := range files {
+		switch {
+		case strings.HasSuffix(f.Name, ".kotlin_module"), strings.HasSuffix(f.Name, ".kotlin_builtins"):
+			android.Kotlin = true
+		case strings.HasPrefix(f.Name, "META-INF/androidx.") && strings.HasSuffix(f.Name, ".version"):
+			android.AndroidX = true
+		case strings.HasPrefix(f.Name, "META-INF/com.android.support_") && strings.HasSuffix(f.Name, ".version"):
+			android.SupportLibrary = true
+		}
+		if !strings.HasSuffix(f.Name, ".dex") {
+			continue
+		}
+		buf, err := readZipFile(f)
+		if err != nil {
+			return err
+		}
+		android.ExpansionFiles = android.ExpansionFiles || containsAny(buf, expansionDexMarkers)
+		android.AssetDelivery = android.AssetDelivery || containsAny(buf, assetPackDexMarkers)
+		android.Kotlin = android.Kotlin || containsAny(buf, kotlinDexMarkers)
+		android.AndroidX = android.AndroidX || containsAny(buf, androidxDexMarkers)
+		android.SupportLibrary = android.SupportLibrary || containsAny(buf, supportLibraryDexMarkers)
+		if names, err := dexClassNames(buf); err == nil {
+			o, n := countObfuscated(names)
+			obfuscated += o
+			classes += n
+		}
+	}
+	android.Obfuscated = classes > 0 && 2*obfuscated >= classes
+	if len(android.AssetPacks) > 0 {
+		android.AssetDelivery = true
+	}
+	return nil
+}
+
+func containsAny(b []byte, markers [][]byte) bool {
+	for _, m := range markers {
+		if bytes.Contains(b, m) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package appfile
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+)
+
+func TestScanDexFiles(t *testing.T) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"classes.dex":      "dex\n035\x00Landroid/app/Activity;",
+		"classes2.dex":     "dex\n035\x00Lcom/google/android/vending/expansion/downloader/impl/DownloaderService;",
+		"assets/a.dex.txt": "Lcom/google/android/play/core/assetpacks/AssetPackManager;",
+	} {
+		f, _ := w.Create(name)
+		f.Write([]byte(content))
+	}
+	w.Close()
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	android := new(AndroidInfo)
+	if err := scanDexFiles(reader.File, android); err != nil {
+		t.Errorf("got %v want no error", err)
+	}
+	if !android.ExpansionFiles {
+		t.Errorf("got %v want %v", android.ExpansionFiles, true)
+	}
+	if android.AssetDelivery {
+		t.Errorf("got %v want %v", android.AssetDelivery, false)
+	}
+}
+
+func TestScanDexFilesLibraries(t *testing.T) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"classes.dex":  "dex\n035\x00Landroidx/appcompat/app/AppCompatActivity;",
+		"classes2.dex": string(buildDex("La/a;", "La/b;", "Lcom/example/MainActivity;")),
+		"META-INF/com.android.support_appcompat-v7.version": "28.0.0",
+	} {
+		f, _ := w.Create(name)
+		f.Write([]byte(content))
+	}
+	w.Close()
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	android := new(AndroidInfo)
+	if err := scanDexFiles(reader.File, android); err != nil {
+		t.Errorf("got %v want no error", err)
+	}
+	if !android.Obfuscated {
+		t.Errorf("got %v want %v", android.Obfuscated, true)
+	}
+	if android.Kotlin || !android.AndroidX || !android.SupportLibrary {
+		t.Errorf("got %v, %v, %v want false, true, true", android.Kotlin, android.AndroidX, android.SupportLibrary)
+	}
+
+	android = new(AndroidInfo)
+	scanDexFiles([]*zip.File{{FileHeader: zip.FileHeader{Name: "META-INF/app_release.kotlin_module"}}}, android)
+	if !android.Kotlin {
+		t.Errorf("got %v want %v", android.Kotlin, true)
+	}
+}
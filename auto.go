@@ -0,0 +1,64 @@
+package appfile
+
+import "strings"
+
+const (
+	metaCarApplication   = "com.google.android.gms.car.application"
+	metaMinCarAPILevel   = "androidx.car.app.minCarApiLevel"
+	actionCarAppService  = "androidx.car.app.CarAppService"
+	carAppCategoryPrefix = "androidx.car.app.category."
+)
+
+type androidAutomotiveApp struct {
+	Uses []struct {
+		Name string `xml:"name,attr"`
+	} `xml:"uses"`
+}
+
+// parseApkAuto reads the Android Auto descriptor the
+// com.google.android.gms.car.application meta-data points to, and the
+// categories of Car App Library services. It leaves android.Auto nil for
+// apps that support neither Android Auto nor Android Automotive OS.
+func parseApkAuto(res *apkResources, manifest *androidManifest, android *AndroidInfo) {
+	a := new(AutoInfo)
+	for _, f := range manifest.UsesFeatures {
+		a.AutomotiveOS = a.AutomotiveOS || f.Name == featureAutomotive
+	}
+	if m := metaDataNamed(manifest.Application.MetaData, metaCarApplication); m != nil {
+		a.Descriptor = res.refName(m.Resource)
+		var d androidAutomotiveApp
+		if decodeResourceXML(res, m.Resource, &d) == nil {
+			for _, u := range d.Uses {
+				a.Uses = appendUnique(a.Uses, u.Name)
+			}
+		}
+	}
+	if m := metaDataNamed(manifest.Application.MetaData, metaMinCarAPILevel); m != nil {
+		a.MinCarAPILevel = atoi(m.Value)
+	}
+	for _, s := range manifest.Application.Services {
+		for _, f := range s.IntentFilters {
+			if !hasAction(f, actionCarAppService) {
+				continue
+			}
+			for _, c := range f.Categories {
+				if strings.HasPrefix(c.Name, carAppCategoryPrefix) {
+					a.CarAppCategories = appendUnique(a.CarAppCategories, strings.ToLower(strings.TrimPrefix(c.Name, carAppCategoryPrefix)))
+				}
+			}
+		}
+	}
+	if a.Descriptor == "" && !a.AutomotiveOS && a.CarAppCategories == nil {
+		return
+	}
+	android.Auto = a
+}
+
+func hasAction(f androidIntentFilter, action string) bool {
+	for _, a := range f.Actions {
+		if a.Name == action {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package appfile
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+)
+
+func TestParseApkAuto(t *testing.T) {
+	m := new(androidManifest)
+	m.Application.MetaData = []androidMetaData{
+		{Name: metaCarApplication, Resource: "@xml/automotive_app_desc"},
+		{Name: metaMinCarAPILevel, Value: "3"},
+	}
+	m.Application.Services = []androidComponent{{
+		Name: ".NavService",
+		IntentFilters: []androidIntentFilter{{
+			Actions:    []androidName{{actionCarAppService}},
+			Categories: []androidName{{"androidx.car.app.category.NAVIGATION"}},
+		}},
+	}}
+	android := new(AndroidInfo)
+	parseApkAuto(newApkResources(nil), m, android)
+	want := &AutoInfo{Descriptor: "@xml/automotive_app_desc", CarAppCategories: []string{"navigation"}, MinCarAPILevel: 3}
+	if !reflect.DeepEqual(android.Auto, want) {
+		t.Errorf("got %+v want %+v", android.Auto, want)
+	}
+
+	android = new(AndroidInfo)
+	parseApkAuto(newApkResources(nil), new(androidManifest), android)
+	if android.Auto != nil {
+		t.Errorf("got %+v want nil", android.Auto)
+	}
+
+	var d androidAutomotiveApp
+	if err := xml.Unmarshal([]byte(`<automotiveApp><uses name="media"/><uses name="notification"/></automotiveApp>`), &d); err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Uses) != 2 || d.Uses[0].Name != "media" {
+		t.Errorf("got %+v want media and notification", d.Uses)
+	}
+}
@@ -0,0 +1,74 @@
+package appfile
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// backupElement is any element of a full-backup-content or
+// data-extraction-rules file.
+type backupElement struct {
+	XMLName      xml.Name
+	Domain       string          `xml:"domain,attr"`
+	Path         string          `xml:"path,attr"`
+	RequireFlags string          `xml:"requireFlags,attr"`
+	Children     []backupElement `xml:",any"`
+}
+
+// parseBackupRules resolves the backup rule resources referenced by the
+// manifest and summarizes them. References that cannot be resolved are left
+// as they are.
+func parseBackupRules(res *apkResources, backup *BackupInfo) {
+	for _, ref := range []*string{&backup.FullBackupContent, &backup.DataExtractionRules} {
+		f := res.file(*ref)
+		if f == nil {
+			continue
+		}
+		buf, err := readZipFile(f)
+		if err != nil {
+			continue
+		}
+		xmlFile, err := decodeAndroidXML(buf)
+		if err != nil {
+			continue
+		}
+		rules, err := decodeBackupRules(xmlFile.Reader())
+		if err != nil {
+			continue
+		}
+		*ref = f.Name
+		backup.Rules = append(backup.Rules, rules...)
+	}
+}
+
+func decodeBackupRules(r io.Reader) ([]BackupRule, error) {
+	var root backupElement
+	if err := xml.NewDecoder(r).Decode(&root); err != nil {
+		return nil, err
+	}
+
+	var rules []BackupRule
+	add := func(source string, elements []backupElement) {
+		for _, e := range elements {
+			if e.XMLName.Local != "include" && e.XMLName.Local != "exclude" {
+				continue
+			}
+			rules = append(rules, BackupRule{
+				Source:       source,
+				Action:       e.XMLName.Local,
+				Domain:       e.Domain,
+				Path:         e.Path,
+				RequireFlags: e.RequireFlags,
+			})
+		}
+	}
+	if root.XMLName.Local == "data-extraction-rules" {
+		// <cloud-backup>, <device-transfer> and <cross-platform-transfer>
+		for _, section := range root.Children {
+			add(section.XMLName.Local, section.Children)
+		}
+	} else {
+		add(root.XMLName.Local, root.Children)
+	}
+	return rules, nil
+}
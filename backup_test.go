@@ -0,0 +1,55 @@
+package appfile
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeBackupRules(t *testing.T) {
+	fullBackup := `<full-backup-content>
+	<include domain="sharedpref" path="."/>
+	<exclude domain="sharedpref" path="device.xml"/>
+	<include domain="database" path="notes.db" requireFlags="clientSideEncryption"/>
+</full-backup-content>`
+	rules, err := decodeBackupRules(strings.NewReader(fullBackup))
+	if err != nil {
+		t.Errorf("got %v want no error", err)
+	}
+	want := []BackupRule{
+		{Source: "full-backup-content", Action: "include", Domain: "sharedpref", Path: "."},
+		{Source: "full-backup-content", Action: "exclude", Domain: "sharedpref", Path: "device.xml"},
+		{Source: "full-backup-content", Action: "include", Domain: "database", Path: "notes.db", RequireFlags: "clientSideEncryption"},
+	}
+	if !reflect.DeepEqual(rules, want) {
+		t.Errorf("got %+v want %+v", rules, want)
+	}
+
+	extraction := `<data-extraction-rules>
+	<cloud-backup disableIfNoEncryptionCapabilities="true">
+		<exclude domain="root"/>
+	</cloud-backup>
+	<device-transfer>
+		<include domain="file" path="media"/>
+	</device-transfer>
+</data-extraction-rules>`
+	rules, err = decodeBackupRules(strings.NewReader(extraction))
+	if err != nil {
+		t.Errorf("got %v want no error", err)
+	}
+	want = []BackupRule{
+		{Source: "cloud-backup", Action: "exclude", Domain: "root"},
+		{Source: "device-transfer", Action: "include", Domain: "file", Path: "media"},
+	}
+	if !reflect.DeepEqual(rules, want) {
+		t.Errorf("got %+v want %+v", rules, want)
+	}
+}
+
+func TestParseBackupRulesLiteral(t *testing.T) {
+	backup := &BackupInfo{FullBackupContent: "false"}
+	parseBackupRules(newApkResources(nil), backup)
+	if backup.FullBackupContent != "false" || backup.Rules != nil {
+		t.Errorf("got %+v want unchanged", backup)
+	}
+}
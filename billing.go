@@ -0,0 +1,54 @@
+package appfile
+
+import (
+	"archive/zip"
+	"strings"
+)
+
+// In-app purchase libraries, see BillingInfo.
+const (
+	BillingPlay       = "play-billing"
+	BillingAmazon     = "amazon-iap"
+	BillingStoreKit   = "storekit"
+	BillingRevenueCat = "revenuecat"
+)
+
+const (
+	permissionBilling  = "com.android.vending.BILLING"
+	playBillingVersion = "META-INF/com.android.billingclient_billing.version"
+)
+
+// InAppPurchases reports whether the app can sell in-app purchases.
+func (b *BillingInfo) InAppPurchases() bool {
+	return b != nil && (b.Permission || len(b.Libraries) > 0)
+}
+
+// newApkBilling returns the billing capability of an APK, or nil. The Play
+// Billing Library adds the permission itself, but older apps declare it
+// and bind the billing service by hand.
+func newApkBilling(s sdkScan, files []*zip.File, android *AndroidInfo) *BillingInfo {
+	b := &BillingInfo{Libraries: s[markerBilling]}
+	for _, p := range android.Permissions {
+		b.Permission = b.Permission || p == permissionBilling
+	}
+	if f := findZipFile(files, playBillingVersion); f != nil {
+		if buf, err := readZipFile(f); err == nil {
+			b.LibraryVersion = strings.TrimSpace(string(buf))
+			b.Libraries = appendUnique(b.Libraries, BillingPlay)
+		}
+	}
+	if !b.InAppPurchases() {
+		return nil
+	}
+	return b
+}
+
+// newIpaBilling returns the billing capability of an IPA, or nil. iOS has
+// no in-app purchase entitlement, every App ID has the capability, so an
+// app sells in-app purchases when it links StoreKit.
+func newIpaBilling(s sdkScan) *BillingInfo {
+	if len(s[markerBilling]) == 0 {
+		return nil
+	}
+	return &BillingInfo{Libraries: s[markerBilling]}
+}
@@ -0,0 +1,42 @@
+package appfile
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestNewApkBilling(t *testing.T) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"classes.dex":      "dex\n035\x00Lcom/revenuecat/purchases/Purchases;",
+		playBillingVersion: "6.1.0\n",
+	} {
+		f, _ := w.Create(name)
+		f.Write([]byte(content))
+	}
+	w.Close()
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sdks, err := scanApkSDKs(reader.File)
+	if err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	got := newApkBilling(sdks, reader.File, &AndroidInfo{Permissions: []string{"android.permission.INTERNET", permissionBilling}})
+	want := &BillingInfo{Permission: true, Libraries: []string{BillingRevenueCat, BillingPlay}, LibraryVersion: "6.1.0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+
+	if got := newApkBilling(sdkScan{}, nil, new(AndroidInfo)); got.InAppPurchases() {
+		t.Errorf("got %+v want no in-app purchases", got)
+	}
+	if got := newIpaBilling(sdkScan{markerBilling: {BillingStoreKit}}); !got.InAppPurchases() {
+		t.Errorf("got %+v want in-app purchases", got)
+	}
+}
@@ -0,0 +1,257 @@
+package appfile
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"path"
+	"sort"
+	"strings"
+)
+
+const (
+	aabExt  = ".aab"
+	apksExt = ".apks"
+)
+
+// Feature module delivery modes.
+const (
+	DeliveryInstallTime = "install-time"
+	DeliveryConditional = "conditional"
+	DeliveryOnDemand    = "on-demand"
+)
+
+// androidDistModule is the <dist:module> element of a module manifest.
+type androidDistModule struct {
+	Type     string `xml:"type,attr"`
+	Title    string `xml:"title,attr"`
+	Instant  string `xml:"instant,attr"`
+	OnDemand string `xml:"onDemand,attr"` // before <dist:delivery> existed
+	Delivery struct {
+		InstallTime *struct {
+			Conditions *androidDistConditions `xml:"conditions"`
+		} `xml:"install-time"`
+		OnDemand *struct{} `xml:"on-demand"`
+	} `xml:"delivery"`
+}
+
+type androidDistConditions struct {
+	MinSdk         []androidDistValue `xml:"min-sdk"`
+	MaxSdk         []androidDistValue `xml:"max-sdk"`
+	DeviceFeatures []androidName      `xml:"device-feature"`
+	UserCountries  []struct {
+		Exclude   string `xml:"exclude,attr"`
+		Countries []struct {
+			Code string `xml:"code,attr"`
+		} `xml:"country"`
+	} `xml:"user-countries"`
+	DeviceGroups []struct {
+		Groups []androidName `xml:"device-group"`
+	} `xml:"device-groups"`
+}
+
+type androidDistValue struct {
+	Value string `xml:"value,attr"`
+}
+
+func (c *androidDistConditions) strings() []string {
+	var s []string
+	for _, v := range c.MinSdk {
+		s = append(s, "min-sdk:"+v.Value)
+	}
+	for _, v := range c.MaxSdk {
+		s = append(s, "max-sdk:"+v.Value)
+	}
+	for _, f := range c.DeviceFeatures {
+		s = append(s, "device-feature:"+f.Name)
+	}
+	for _, u := range c.UserCountries {
+		var codes []string
+		for _, country := range u.Countries {
+			codes = append(codes, country.Code)
+		}
+		key := "user-countries:"
+		if u.Exclude == "true" {
+			key = "exclude-user-countries:"
+		}
+		s = append(s, key+strings.Join(codes, ","))
+	}
+	for _, g := range c.DeviceGroups {
+		var names []string
+		for _, group := range g.Groups {
+			names = append(names, group.Name)
+		}
+		s = append(s, "device-groups:"+strings.Join(names, ","))
+	}
+	return s
+}
+
+// featureModule describes the module named name, or returns nil for the
+// base module and asset packs.
+func (m *androidManifest) featureModule(name string) *FeatureModule {
+	d := m.Module
+	if d == nil || name == "" || name == "base" || d.Type == "asset-pack" {
+		return nil
+	}
+
+	f := &FeatureModule{Name: name, Title: d.Title, Instant: d.Instant == "true"}
+	if it := d.Delivery.InstallTime; it != nil {
+		if it.Conditions != nil {
+			f.Conditions = it.Conditions.strings()
+		}
+		if len(f.Conditions) > 0 {
+			f.Delivery = append(f.Delivery, DeliveryConditional)
+		} else {
+			f.Delivery = append(f.Delivery, DeliveryInstallTime)
+		}
+	}
+	if d.Delivery.OnDemand != nil {
+		f.Delivery = append(f.Delivery, DeliveryOnDemand)
+	}
+	if len(f.Delivery) == 0 {
+		if d.OnDemand == "true" {
+			f.Delivery = []string{DeliveryOnDemand}
+		} else {
+			f.Delivery = []string{DeliveryInstallTime}
+		}
+	}
+	return f
+}
+
+// parseAabFile reads an Android App Bundle, whose modules keep their
+// manifests as protobuf in <module>/manifest/AndroidManifest.xml.
+func parseAabFile(reader *zip.Reader) (*AppInfo, error) {
+	var info *AppInfo
+	var modules []FeatureModule
+	var assetPacks []string
+	for _, f := range reader.File {
+		dir, file := path.Split(f.Name)
+		if file != "AndroidManifest.xml" || strings.Count(dir, "/") != 2 || !strings.HasSuffix(dir, "/manifest/") {
+			continue
+		}
+		module := strings.TrimSuffix(dir, "/manifest/")
+
+		manifest, err := parseAabManifest(f)
+		if err != nil {
+			return nil, err
+		}
+		if module == "base" {
+			info = newAndroidAppInfo(manifest)
+		} else if manifest.isAssetPack() {
+			assetPacks = append(assetPacks, module)
+		} else if fm := manifest.featureModule(module); fm != nil {
+			modules = append(modules, *fm)
+		}
+	}
+	if info == nil {
+		return nil, errors.New("base/manifest/AndroidManifest.xml not found")
+	}
+
+	sort.Slice(modules, func(i, j int) bool { return modules[i].Name < modules[j].Name })
+	info.Android.FeatureModules = modules
+	sort.Strings(assetPacks)
+	info.Android.AssetPacks = assetPacks
+	if err := scanDexFiles(reader.File, info.Android); err != nil {
+		return nil, err
+	}
+	info.Android.BaselineProfile = parseBaselineProfile(reader.File)
+	return info, nil
+}
+
+func parseAabManifest(f *zip.File) (*androidManifest, error) {
+	buf, err := readZipFile(f)
+	if err != nil {
+		return nil, err
+	}
+	decoder, err := newProtoXMLDecoder(buf)
+	if err != nil {
+		return nil, err
+	}
+	manifest := new(androidManifest)
+	if err := decoder.Decode(manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// parseApksFile reads a bundletool split set. It returns the base APK so
+// the icon can be read from it.
+func parseApksFile(reader *zip.Reader) (*AppInfo, []byte, error) {
+	var base []byte
+	var modules []FeatureModule
+	var assetPacks, densities []string
+	for _, f := range reader.File {
+		// Configuration splits are named after their configuration, e.g.
+		// splits/base-xxhdpi.apk.
+		if i := strings.LastIndex(f.Name, "-"); strings.HasPrefix(f.Name, "splits/") && i >= 0 {
+			if d := densitySplit("config." + strings.TrimSuffix(f.Name[i+1:], ".apk")); d != "" {
+				densities = appendUnique(densities, d)
+				continue
+			}
+		}
+		master := strings.HasSuffix(f.Name, "-master.apk") &&
+			(strings.HasPrefix(f.Name, "splits/") || strings.HasPrefix(f.Name, "asset-slices/"))
+		if !isApksBase(f.Name) && !master {
+			continue
+		}
+
+		buf, err := readZipFile(f)
+		if err != nil {
+			return nil, nil, err
+		}
+		if isApksBase(f.Name) {
+			if base == nil || f.Name == "splits/base-master.apk" {
+				base = buf
+			}
+			continue
+		}
+
+		_, manifest, err := openNestedApk(buf)
+		if err != nil {
+			return nil, nil, err
+		}
+		if manifest.isAssetPack() {
+			assetPacks = append(assetPacks, manifest.Split)
+		} else if fm := manifest.featureModule(manifest.Split); fm != nil {
+			modules = append(modules, *fm)
+		}
+	}
+	if base == nil {
+		return nil, nil, errors.New("base APK not found")
+	}
+
+	baseReader, manifest, err := openNestedApk(base)
+	if err != nil {
+		return nil, nil, err
+	}
+	info := newAndroidAppInfo(manifest)
+	sort.Slice(modules, func(i, j int) bool { return modules[i].Name < modules[j].Name })
+	info.Android.FeatureModules = modules
+	sort.Strings(assetPacks)
+	info.Android.AssetPacks = assetPacks
+	sort.Strings(densities)
+	info.Android.Screens.DensitySplits = densities
+	if err := scanDexFiles(baseReader.File, info.Android); err != nil {
+		return nil, nil, err
+	}
+	return info, base, nil
+}
+
+func isApksBase(name string) bool {
+	return name == "splits/base-master.apk" || name == "universal.apk" ||
+		(strings.HasPrefix(name, "standalones/") && strings.HasSuffix(name, ".apk"))
+}
+
+func openNestedApk(buf []byte) (*zip.Reader, *androidManifest, error) {
+	reader, err := zip.NewReader(bytes.NewReader(buf), int64(len(buf)))
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, f := range reader.File {
+		if f.Name == "AndroidManifest.xml" {
+			manifest, err := parseAndroidManifest(f)
+			return reader, manifest, err
+		}
+	}
+	return nil, nil, errors.New("AndroidManifest.xml not found")
+}
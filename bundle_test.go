@@ -0,0 +1,176 @@
+package appfile
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const (
+	androidNS = "http://schemas.android.com/apk/res/android"
+	distNS    = "http://schemas.android.com/apk/distribution"
+)
+
+func pbField(num int, b []byte) []byte {
+	buf := binary.AppendUvarint(nil, uint64(num<<3|2))
+	buf = binary.AppendUvarint(buf, uint64(len(b)))
+	return append(buf, b...)
+}
+
+// pbElement encodes an XmlNode holding an element. attrs are namespace,
+// name, value triples; the "xmlns" namespace declares a prefix.
+func pbElement(ns, name string, attrs [][3]string, children ...[]byte) []byte {
+	el := append(pbField(2, []byte(ns)), pbField(3, []byte(name))...)
+	for _, a := range attrs {
+		if a[0] == "xmlns" {
+			el = append(el, pbField(1, append(pbField(1, []byte(a[1])), pbField(2, []byte(a[2]))...))...)
+			continue
+		}
+		attr := append(pbField(1, []byte(a[0])), pbField(2, []byte(a[1]))...)
+		attr = append(attr, pbField(3, []byte(a[2]))...)
+		// resource_id, which the decoder skips
+		attr = append(attr, 0x28, 0x81, 0x80, 0x04)
+		el = append(el, pbField(4, attr)...)
+	}
+	for _, c := range children {
+		el = append(el, pbField(5, c)...)
+	}
+	return pbField(1, el)
+}
+
+func writeZip(t *testing.T, name string, files map[string][]byte) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for n, b := range files {
+		f, err := w.Create(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write(b)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(name, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseAab(t *testing.T) {
+	base := pbElement("", "manifest", [][3]string{
+		{"", "package", "com.example.bundle"},
+		{androidNS, "versionCode", "42"},
+		{androidNS, "versionName", "2.1"},
+	},
+		pbElement("", "uses-sdk", [][3]string{{androidNS, "minSdkVersion", "21"}}),
+		pbElement(distNS, "module", [][3]string{{distNS, "instant", "true"}}),
+	)
+	camera := pbElement("", "manifest", [][3]string{{"", "split", "camera"}},
+		pbElement(distNS, "module", [][3]string{{distNS, "title", "@string/camera"}},
+			pbElement(distNS, "delivery", nil,
+				pbElement(distNS, "install-time", nil,
+					pbElement(distNS, "conditions", nil,
+						pbElement(distNS, "min-sdk", [][3]string{{distNS, "value", "24"}}),
+						pbElement(distNS, "device-feature", [][3]string{{distNS, "name", "android.hardware.camera.ar"}}),
+						pbElement(distNS, "user-countries", [][3]string{{distNS, "exclude", "true"}},
+							pbElement(distNS, "country", [][3]string{{distNS, "code", "CN"}}),
+						),
+					),
+				),
+				pbElement(distNS, "on-demand", nil),
+			),
+		),
+	)
+	legacy := pbElement("", "manifest", nil, pbElement(distNS, "module", [][3]string{{distNS, "onDemand", "true"}, {distNS, "instant", "true"}}))
+	assets := pbElement("", "manifest", nil, pbElement(distNS, "module", [][3]string{{distNS, "type", "asset-pack"}}))
+
+	dir, err := ioutil.TempDir("", "appfile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "app.aab")
+	writeZip(t, name, map[string][]byte{
+		"base/manifest/AndroidManifest.xml":   base,
+		"camera/manifest/AndroidManifest.xml": camera,
+		"legacy/manifest/AndroidManifest.xml": legacy,
+		"assets/manifest/AndroidManifest.xml": assets,
+		"base/dex/classes.dex":                []byte("dex\n035\x00Lcom/google/android/play/core/assetpacks/AssetPackManager;"),
+		"BundleConfig.pb":                     nil,
+	})
+
+	info, err := NewAppParser(name)
+	if err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	if info.BundleId != "com.example.bundle" || info.Version != "2.1" || info.Build != "42" {
+		t.Errorf("got %v %v %v want %v %v %v", info.BundleId, info.Version, info.Build, "com.example.bundle", "2.1", "42")
+	}
+	if info.Android.MinSdkVersion != "21" {
+		t.Errorf("got %v want %v", info.Android.MinSdkVersion, "21")
+	}
+	if !info.Android.IsInstantApp {
+		t.Errorf("got %v want %v", info.Android.IsInstantApp, true)
+	}
+
+	want := []FeatureModule{{
+		Name:       "camera",
+		Title:      "@string/camera",
+		Delivery:   []string{DeliveryConditional, DeliveryOnDemand},
+		Conditions: []string{"min-sdk:24", "device-feature:android.hardware.camera.ar", "exclude-user-countries:CN"},
+	}, {
+		Name:     "legacy",
+		Instant:  true,
+		Delivery: []string{DeliveryOnDemand},
+	}}
+	if !reflect.DeepEqual(info.Android.FeatureModules, want) {
+		t.Errorf("got %+v want %+v", info.Android.FeatureModules, want)
+	}
+	if !reflect.DeepEqual(info.Android.AssetPacks, []string{"assets"}) || !info.Android.AssetDelivery {
+		t.Errorf("got %v %v want %v %v", info.Android.AssetPacks, info.Android.AssetDelivery, []string{"assets"}, true)
+	}
+	if info.Android.ExpansionFiles {
+		t.Errorf("got %v want %v", info.Android.ExpansionFiles, false)
+	}
+}
+
+func TestParseApks(t *testing.T) {
+	apk, err := ioutil.ReadFile("testdata/helloworld.apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, n := range []string{"toc.pb", "splits/base-master.apk", "splits/base-xxhdpi.apk"} {
+		f, _ := w.Create(n)
+		f.Write(apk)
+	}
+	w.Close()
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, base, err := parseApksFile(reader)
+	if err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	if info.BundleId != "com.example.helloworld" {
+		t.Errorf("got %v want %v", info.BundleId, "com.example.helloworld")
+	}
+	if !bytes.Equal(base, apk) {
+		t.Errorf("got %v bytes want %v", len(base), len(apk))
+	}
+	if len(info.Android.FeatureModules) != 0 {
+		t.Errorf("got %v want none", info.Android.FeatureModules)
+	}
+	if got := info.Android.Screens.DensitySplits; !reflect.DeepEqual(got, []string{"xxhdpi"}) {
+		t.Errorf("got %v want %v", got, []string{"xxhdpi"})
+	}
+}
@@ -0,0 +1,118 @@
+package appfile
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"sync"
+)
+
+// Cache stores parse results keyed by the hex SHA-256 digest of the
+// artifact, suffixed for options that change the result: "+urls" for
+// WithURLScan, "+lenient" for ModeLenient, "+tolerant" for
+// WithTolerantZip and "+recover" for WithZipRecovery. Implementations must
+// be safe for concurrent use and must not share what Get returns with
+// what was Set, as callers may modify results.
+type Cache interface {
+	Get(ctx context.Context, key string) (*AppInfo, bool)
+	Set(ctx context.Context, key string, info *AppInfo)
+}
+
+// WithCache serves repeated parses of the same artifact from c. Only
+// results that parsed without error are stored. Parses with
+// WithFrameworkResources or WithArchivePassword bypass the cache.
+func WithCache(c Cache) Option {
+	return func(o *options) {
+		o.cache = c
+	}
+}
+
+// HashFile returns the hex SHA-256 digest of the named file, the key used
+// for cached results.
+func HashFile(name string) (string, error) {
+	return hashFile(name, nil)
+}
+
+// hashFile is HashFile reporting progress to o, if it asks for it.
+func hashFile(name string, o *options) (string, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	var w io.Writer = h
+	if o != nil && o.progress != nil {
+		stat, err := file.Stat()
+		if err != nil {
+			return "", err
+		}
+		w = progressWriter{h, newProgressCounter(o, stat.Size())}
+	}
+	if _, err := io.Copy(w, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// LRUCache is an in-memory Cache that evicts the least recently used entry
+// once it holds more than its configured number of entries.
+type LRUCache struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List
+	items map[string]*list.Element
+}
+
+type lruEntry struct {
+	key  string
+	info *AppInfo
+}
+
+func NewLRUCache(size int) *LRUCache {
+	return &LRUCache{
+		size:  size,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+func (c *LRUCache) Get(ctx context.Context, key string) (*AppInfo, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*lruEntry).info.clone(), true
+}
+
+func (c *LRUCache) Set(ctx context.Context, key string, info *AppInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stored := info.clone()
+	if e, ok := c.items[key]; ok {
+		e.Value.(*lruEntry).info = stored
+		c.order.MoveToFront(e)
+		return
+	}
+	c.items[key] = c.order.PushFront(&lruEntry{key: key, info: stored})
+	for c.size > 0 && c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
@@ -0,0 +1,90 @@
+package appfile
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLRUCacheEviction(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRUCache(2)
+	c.Set(ctx, "a", &AppInfo{BundleId: "a"})
+	c.Set(ctx, "b", &AppInfo{BundleId: "b"})
+	if _, ok := c.Get(ctx, "a"); !ok {
+		t.Errorf("got miss want hit for %v", "a")
+	}
+	c.Set(ctx, "c", &AppInfo{BundleId: "c"})
+
+	if _, ok := c.Get(ctx, "b"); ok {
+		t.Errorf("got hit want miss for %v", "b")
+	}
+	if info, ok := c.Get(ctx, "a"); !ok || info.BundleId != "a" {
+		t.Errorf("got %v want %v", info, "a")
+	}
+	if c.Len() != 2 {
+		t.Errorf("got %v want %v", c.Len(), 2)
+	}
+}
+
+func TestLRUCacheCopies(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRUCache(1)
+	info := &AppInfo{BundleId: "a", Warnings: []string{"w"}, Ios: &IosInfo{
+		Profile: &ProvisioningProfile{Name: "App", Entitlements: map[string]interface{}{"aps-environment": "production"}},
+	}}
+	c.Set(ctx, "a", info)
+	info.BundleId = "changed"
+	info.Ios.Profile.Name = "changed"
+
+	got, _ := c.Get(ctx, "a")
+	if got.BundleId != "a" || got.Ios.Profile.Name != "App" {
+		t.Errorf("got %v %v want %v %v", got.BundleId, got.Ios.Profile.Name, "a", "App")
+	}
+	got.Warnings[0] = "changed"
+	got.Ios.Profile.Entitlements["aps-environment"] = "development"
+	again, _ := c.Get(ctx, "a")
+	if again.Warnings[0] != "w" || again.Ios.Profile.Entitlements["aps-environment"] != "production" {
+		t.Errorf("got %v %v want the cached entry unchanged", again.Warnings, again.Ios.Profile.Entitlements)
+	}
+}
+
+func TestWithCacheBypass(t *testing.T) {
+	key, err := HashFile("testdata/helloworld.apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewLRUCache(4)
+	c.Set(context.Background(), key, &AppInfo{BundleId: "cached"})
+	c.Set(context.Background(), key+"+tolerant", &AppInfo{BundleId: "tolerant"})
+
+	info, _ := NewAppParser("testdata/helloworld.apk", WithCache(c), WithTolerantZip())
+	if info == nil || info.BundleId != "tolerant" {
+		t.Errorf("got %v want %v", info, "tolerant")
+	}
+	c.Set(context.Background(), key, &AppInfo{BundleId: "cached", Warnings: []string{"no icon"}})
+	info, _ = NewAppParser("testdata/helloworld.apk", WithCache(c), WithMode(ModeStrict))
+	if info != nil && info.BundleId == "cached" {
+		t.Errorf("got %v want a strict parse bypassing the default entry", info)
+	}
+	info, _ = NewAppParser("testdata/helloworld.apk", WithCache(c), WithArchivePassword("secret"))
+	if info == nil || info.BundleId == "cached" {
+		t.Errorf("got %v want a parse bypassing the cache", info)
+	}
+}
+
+func TestWithCacheHit(t *testing.T) {
+	key, err := HashFile("testdata/helloworld.apk")
+	if err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	c := NewLRUCache(1)
+	c.Set(context.Background(), key, &AppInfo{BundleId: "cached"})
+
+	info, err := NewAppParser("testdata/helloworld.apk", WithCache(c))
+	if err != nil {
+		t.Errorf("got %v want no error", err)
+	}
+	if info.BundleId != "cached" {
+		t.Errorf("got %v want %v", info.BundleId, "cached")
+	}
+}
@@ -0,0 +1,189 @@
+package appfile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Role values of the packages ParseCatalog returns.
+const (
+	RolePrimary   = "primary"
+	RoleCompanion = "companion" // Wear OS and watchOS apps
+	RoleClip      = "clip"      // App Clips
+	RoleTest      = "test"      // instrumentation APKs and XCTest runners
+)
+
+// ParseCatalog parses every app package in the .zip, .tgz or .tar.gz name,
+// such as a release bundle with a phone and a Wear OS APK or an app and
+// its App Clip, and sets their Role and Parent. Any other artifact is
+// parsed as by NewAppParser and cataloged alone. Provenance verifiers run
+// on every package. Packages that fail are left out and their errors
+// joined, each prefixed with its path.
+func ParseCatalog(name string, opts ...Option) ([]*AppInfo, error) {
+	o := newOptions(opts)
+	var infos []*AppInfo
+	var errs []error
+	add := func(info *AppInfo, err error) {
+		if info != nil {
+			info.sortSets()
+			if info.Environment == "" {
+				info.Environment = info.BuildEnvironment()
+			}
+			o.notify(name, info)
+			infos = append(infos, info)
+		}
+		if err != nil {
+			if info != nil && info.Container != "" {
+				err = fmt.Errorf("%s: %w", info.Container, err)
+			}
+			errs = append(errs, err)
+		}
+	}
+
+	switch formatExt(name) {
+	case zipExt:
+		err := catalogZip(name, o, add)
+		errs = append(errs, err)
+	case tgzExt, tarGzExt:
+		err := catalogTar(name, o, add)
+		errs = append(errs, err)
+	default:
+		info, err := o.applyExpiry(o.applyMode(parseAppFile(name, o)))
+		if info != nil {
+			o.verifyProvenance(name, info)
+		}
+		add(info, err)
+	}
+	catalogRoles(infos)
+	return infos, errors.Join(errs...)
+}
+
+func catalogZip(name string, o *options, add func(*AppInfo, error)) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	stat, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	reader, warnings, release, err := openZip(file, stat.Size(), o)
+	if err != nil {
+		return err
+	}
+	defer release()
+	packages := nestedPackages(reader)
+	if len(packages) == 0 {
+		return errNoPackage
+	}
+	for _, f := range packages {
+		var info *AppInfo
+		rc, err := f.Open()
+		if err == nil {
+			info, err = catalogEntry(f.Name, rc, o)
+			rc.Close()
+		} else {
+			var errs stageErrors
+			errs.add(StageZipRead, err)
+			err = errs.err()
+		}
+		if info != nil {
+			info.Warnings = append(append([]string(nil), warnings...), info.Warnings...)
+		} else if err != nil {
+			err = fmt.Errorf("%s: %w", f.Name, err)
+		}
+		add(info, err)
+	}
+	return nil
+}
+
+func catalogTar(name string, o *options, add func(*AppInfo, error)) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	var n int
+	err = walkNestedTar(file, func(entry string, r io.Reader) error {
+		n++
+		info, err := catalogEntry(entry, r, o)
+		if info == nil && err != nil {
+			err = fmt.Errorf("%s: %w", entry, err)
+		}
+		add(info, err)
+		return nil
+	})
+	if err == nil && n == 0 {
+		err = errNoPackage
+	}
+	return err
+}
+
+// catalogEntry parses the package entry of a container read from r. Its
+// provenance is verified against the extracted copy, the file verifiers
+// may open, before that is removed.
+func catalogEntry(entry string, r io.Reader, o *options) (*AppInfo, error) {
+	tmp, err := o.spool(entry, r)
+	if err != nil {
+		var errs stageErrors
+		errs.add(StageZipRead, err)
+		return nil, errs.err()
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	info, err := o.applyExpiry(o.applyMode(parseNestedFile(entry, tmp, o)))
+	if info != nil {
+		o.verifyProvenance(tmp.Name(), info)
+	}
+	return info, err
+}
+
+// catalogRoles sets the Role of every package and the Parent of those
+// that belong to another: App Clips to the app whose bundle ID prefixes
+// theirs, watch apps to their WKCompanionAppBundleIdentifier and Wear OS
+// apps to the phone app of the same package, which Google Play requires.
+func catalogRoles(infos []*AppInfo) {
+	var primaries []*AppInfo
+	for _, info := range infos {
+		switch {
+		case info.Test != nil:
+			info.Role = RoleTest
+		case info.Ios != nil && info.Ios.AppClip:
+			info.Role = RoleClip
+		case info.Ios != nil && info.Ios.CompanionAppBundleId != "",
+			info.Android != nil && info.Android.Wear != nil && info.Android.Wear.WatchFeature:
+			info.Role = RoleCompanion
+		default:
+			info.Role = RolePrimary
+			primaries = append(primaries, info)
+		}
+	}
+
+	for _, info := range infos {
+		if info.Role == RolePrimary {
+			continue
+		}
+		for _, p := range primaries {
+			var match bool
+			switch {
+			case info.Platform != p.Platform:
+			case info.Role == RoleTest:
+				match = info.Test.TargetPackage == p.BundleId
+			case info.Role == RoleClip:
+				match = strings.HasPrefix(info.BundleId, p.BundleId+".")
+			case info.Ios != nil:
+				match = info.Ios.CompanionAppBundleId == p.BundleId
+			default:
+				match = info.BundleId == p.BundleId
+			}
+			if match {
+				info.Parent = p.BundleId
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package appfile
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseCatalog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "appfile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	apk, err := ioutil.ReadFile("testdata/helloworld.apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	aab := filepath.Join(dir, "app.aab")
+	writeZip(t, aab, map[string][]byte{
+		"base/manifest/AndroidManifest.xml": pbElement("", "manifest", [][3]string{{"", "package", "com.example.bundle"}}),
+	})
+	bundle, err := ioutil.ReadFile(aab)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name := filepath.Join(dir, "release.zip")
+	writeZip(t, name, map[string][]byte{
+		"apk/app.apk":    apk,
+		"bundle/app.aab": bundle,
+		"notes.txt":      nil,
+	})
+	infos, _ := ParseCatalog(name)
+	got := make(map[string]string)
+	for _, info := range infos {
+		got[info.Container] = info.BundleId + " " + info.Role
+	}
+	want := map[string]string{
+		"apk/app.apk":    "com.example.helloworld " + RolePrimary,
+		"bundle/app.aab": "com.example.bundle " + RolePrimary,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	// Each package is verified against its extracted copy.
+	verified := make(map[string]string)
+	v := ProvenanceFunc(func(ctx context.Context, file string, info *AppInfo) (Provenance, error) {
+		p, err := OpenParser(file)
+		if err != nil {
+			return Provenance{}, err
+		}
+		p.Close()
+		verified[info.Container] = filepath.Ext(file)
+		return Provenance{Verifier: "slsa", Verified: true}, nil
+	})
+	infos, _ = ParseCatalog(name, WithProvenanceVerifier(v))
+	for _, info := range infos {
+		if len(info.Provenance) != 1 || !info.Provenance[0].Verified {
+			t.Errorf("%s: got %+v want verified", info.Container, info.Provenance)
+		}
+	}
+	if want := map[string]string{"apk/app.apk": ".apk", "bundle/app.aab": ".aab"}; !reflect.DeepEqual(verified, want) {
+		t.Errorf("got %v want %v", verified, want)
+	}
+
+	name = filepath.Join(dir, "empty.zip")
+	writeZip(t, name, map[string][]byte{"notes.txt": nil})
+	if infos, err := ParseCatalog(name); len(infos) != 0 || !errors.Is(err, errNoPackage) {
+		t.Errorf("got %v %v want %v", infos, err, errNoPackage)
+	}
+}
+
+func TestCatalogRoles(t *testing.T) {
+	phone := newAppInfo(PlatformAndroid)
+	phone.BundleId = "com.example.fit"
+	wear := newAppInfo(PlatformAndroid)
+	wear.BundleId = "com.example.fit"
+	wear.Android.Wear = &WearInfo{WatchFeature: true}
+	iphone := newAppInfo(PlatformIOS)
+	iphone.BundleId = "com.example.fit"
+	watch := newAppInfo(PlatformIOS)
+	watch.BundleId = "com.example.fit.watchkitapp"
+	watch.Ios.CompanionAppBundleId = "com.example.fit"
+	clip := newAppInfo(PlatformIOS)
+	clip.BundleId = "com.example.fit.Clip"
+	clip.Ios.AppClip = true
+
+	catalogRoles([]*AppInfo{phone, wear, iphone, watch, clip})
+	for _, c := range []struct {
+		info         *AppInfo
+		role, parent string
+	}{
+		{phone, RolePrimary, ""},
+		{wear, RoleCompanion, "com.example.fit"},
+		{iphone, RolePrimary, ""},
+		{watch, RoleCompanion, "com.example.fit"},
+		{clip, RoleClip, "com.example.fit"},
+	} {
+		if c.info.Role != c.role || c.info.Parent != c.parent {
+			t.Errorf("got %v %v want %v %v", c.info.Role, c.info.Parent, c.role, c.parent)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package appfile
+
+// androidCategories are the android:appCategory values in binary XML,
+// which stores the enum as an integer.
+var androidCategories = map[string]string{
+	"0": "game",
+	"1": "audio",
+	"2": "video",
+	"3": "image",
+	"4": "social",
+	"5": "news",
+	"6": "maps",
+	"7": "productivity",
+	"8": "accessibility",
+}
+
+// androidCategory returns the name of an android:appCategory value.
+func androidCategory(v string) string {
+	if name, ok := androidCategories[v]; ok {
+		return name
+	}
+	return v
+}
@@ -0,0 +1,24 @@
+package appfile
+
+import "testing"
+
+func TestAndroidCategory(t *testing.T) {
+	for v, want := range map[string]string{
+		"7":            "productivity",
+		"0":            "game",
+		"productivity": "productivity",
+		"":             "",
+	} {
+		if got := androidCategory(v); got != want {
+			t.Errorf("got %v want %v", got, want)
+		}
+	}
+
+	m := new(androidManifest)
+	m.Application.AppCategory = "4"
+	m.Application.Description = "A chat app"
+	info := newAndroidAppInfo(m)
+	if info.Category != "social" || info.Description != "A chat app" {
+		t.Errorf("got %v %v want %v %v", info.Category, info.Description, "social", "A chat app")
+	}
+}
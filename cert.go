@@ -0,0 +1,50 @@
+package appfile
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// Certificate summarizes a signing certificate.
+type Certificate struct {
+	Subject      string    `json:"subject"`
+	Organization string    `json:"organization,omitempty"`
+	Issuer       string    `json:"issuer,omitempty"`
+	Serial       string    `json:"serial"`
+	SHA1         string    `json:"sha1"`
+	SHA256       string    `json:"sha256"`
+	NotBefore    time.Time `json:"not_before"`
+	NotAfter     time.Time `json:"not_after"`
+	Expired      bool      `json:"expired"` // at parse time, see WithClock
+}
+
+func newCertificate(c *x509.Certificate) Certificate {
+	sum1 := sha1.Sum(c.Raw)
+	sum256 := sha256.Sum256(c.Raw)
+	return Certificate{
+		Subject:      c.Subject.CommonName,
+		Organization: strings.Join(c.Subject.Organization, ", "),
+		Issuer:       c.Issuer.CommonName,
+		Serial:       strings.ToUpper(c.SerialNumber.Text(16)),
+		SHA1:         strings.ToUpper(hex.EncodeToString(sum1[:])),
+		SHA256:       strings.ToUpper(hex.EncodeToString(sum256[:])),
+		NotBefore:    c.NotBefore,
+		NotAfter:     c.NotAfter,
+	}
+}
+
+func parseCertificates(ders [][]byte) []Certificate {
+	var certs []Certificate
+	for _, der := range ders {
+		c, err := x509.ParseCertificate(der)
+		if err != nil {
+			continue
+		}
+		certs = append(certs, newCertificate(c))
+	}
+	return certs
+}
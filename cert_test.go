@@ -0,0 +1,51 @@
+package appfile
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func newTestCertificate(t *testing.T, cn string) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(0x5c18),
+		Subject:      pkix.Name{CommonName: cn, Organization: []string{"M8ZCXDJQW4"}},
+		NotBefore:    time.Date(2011, 6, 21, 8, 51, 20, 0, time.UTC),
+		NotAfter:     time.Date(2012, 6, 20, 8, 51, 20, 0, time.UTC),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	return der
+}
+
+func TestParseCertificates(t *testing.T) {
+	der := newTestCertificate(t, "iPhone Distribution: KT Hitel Co., Ltd.")
+	certs := parseCertificates([][]byte{der, []byte("garbage")})
+	if len(certs) != 1 {
+		t.Fatalf("got %v want %v", len(certs), 1)
+	}
+	c := certs[0]
+	if c.Subject != "iPhone Distribution: KT Hitel Co., Ltd." {
+		t.Errorf("got %v want %v", c.Subject, "iPhone Distribution: KT Hitel Co., Ltd.")
+	}
+	if c.Organization != "M8ZCXDJQW4" {
+		t.Errorf("got %v want %v", c.Organization, "M8ZCXDJQW4")
+	}
+	if c.Serial != "5C18" {
+		t.Errorf("got %v want %v", c.Serial, "5C18")
+	}
+	if len(c.SHA1) != 40 || len(c.SHA256) != 64 {
+		t.Errorf("got %v and %v want hex fingerprints", c.SHA1, c.SHA256)
+	}
+}
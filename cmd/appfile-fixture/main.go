@@ -0,0 +1,99 @@
+// Command appfile-fixture writes a tiny synthetic .apk or .ipa, for
+// reproducing parser issues without sharing a real build.
+//
+//	appfile-fixture [flags] file.apk|file.ipa
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/follyxing/appfile-info/fixture"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: appfile-fixture [flags] file.apk|file.ipa\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+// listFlag collects the values of a repeated flag.
+type listFlag []string
+
+func (l *listFlag) String() string     { return strings.Join(*l, ",") }
+func (l *listFlag) Set(s string) error { *l = append(*l, s); return nil }
+
+func main() {
+	id := flag.String("id", "com.example.fixture", "package name or bundle `ID`")
+	name := flag.String("name", "Fixture", "app `name`")
+	version := flag.String("version", "1.0", "version name or CFBundleShortVersionString")
+	build := flag.Int("build", 1, "version code or CFBundleVersion")
+	minSdk := flag.Int("min-sdk", 21, "android:minSdkVersion")
+	targetSdk := flag.Int("target-sdk", 34, "android:targetSdkVersion")
+	minOS := flag.String("min-os", "15.0", "MinimumOSVersion")
+	team := flag.String("team", "ABCDE12345", "team `ID` of the provisioning profile; empty for none")
+	var permissions listFlag
+	flag.Var(&permissions, "permission", "add a uses-permission `name`; repeatable")
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() != 1 {
+		usage()
+	}
+	out := flag.Arg(0)
+
+	var data []byte
+	var err error
+	switch strings.ToLower(filepath.Ext(out)) {
+	case ".apk":
+		apk := &fixture.APK{
+			Package:     *id,
+			VersionCode: *build,
+			VersionName: *version,
+			Label:       *name,
+			MinSdk:      *minSdk,
+			TargetSdk:   *targetSdk,
+			Permissions: permissions,
+			Application: []*fixture.Element{fixture.Activity(*id+".MainActivity", true)},
+		}
+		data, err = apk.Bytes()
+	case ".ipa":
+		ipa := &fixture.IPA{
+			BundleId:         *id,
+			Name:             *name,
+			Version:          *version,
+			Build:            strconv.Itoa(*build),
+			MinimumOSVersion: *minOS,
+		}
+		if *team != "" {
+			ipa.Profile = &fixture.Profile{
+				Name:           *name + " Development",
+				UUID:           "00000000-0000-0000-0000-000000000000",
+				TeamId:         *team,
+				TeamName:       "Fixture Team",
+				ExpirationDate: time.Now().AddDate(1, 0, 0).Truncate(time.Second),
+				Entitlements: map[string]interface{}{
+					"application-identifier": *team + "." + *id,
+					"get-task-allow":         true,
+				},
+				ProvisionedDevices: []string{"00008030-0000000000000000"},
+			}
+		}
+		data, err = ipa.Bytes()
+	default:
+		fmt.Fprintf(os.Stderr, "%s: not an .apk or .ipa\n", out)
+		os.Exit(2)
+	}
+	if err == nil {
+		err = ioutil.WriteFile(out, data, 0644)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", out, err)
+		os.Exit(1)
+	}
+}
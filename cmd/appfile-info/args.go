@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/follyxing/appfile-info"
+)
+
+// isAppFile reports whether name is an artifact, looking into containers
+// so directories of build outputs do not yield their logs and dSYM zips.
+func isAppFile(name string) bool {
+	return appfile.IsSupported(name) || appfile.ContainsApp(name)
+}
+
+// expandArgs replaces directories by the artifacts below them and expands
+// glob patterns the shell left alone.
+func expandArgs(args []string) ([]string, error) {
+	var names []string
+	for _, arg := range args {
+		if strings.ContainsAny(arg, "*?[") {
+			matches, err := filepath.Glob(arg)
+			if err != nil {
+				return nil, err
+			}
+			names = append(names, matches...)
+			continue
+		}
+
+		stat, err := os.Stat(arg)
+		if err != nil || !stat.IsDir() || isAppFile(arg) {
+			names = append(names, arg)
+			continue
+		}
+		err = filepath.Walk(arg, func(path string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if isAppFile(path) {
+				names = append(names, path)
+				// Bundle directories such as .xcframeworks are artifacts.
+				if fi.IsDir() {
+					return filepath.SkipDir
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return names, nil
+}
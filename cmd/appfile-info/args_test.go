@@ -0,0 +1,54 @@
+package main
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExpandArgs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "appfile-info")
+	if err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"a.apk", "b.ipa", "notes.txt", "sub/c.apk"} {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := ioutil.WriteFile(path, nil, 0644); err != nil {
+			t.Fatalf("got %v want no error", err)
+		}
+	}
+
+	// Only containers holding an app package are artifacts.
+	for name, entry := range map[string]string{"build.zip": "outputs/app.apk", "dsym.zip": "App.dSYM/Contents/Info.plist"} {
+		f, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		zw := zip.NewWriter(f)
+		zw.Create(entry)
+		zw.Close()
+		f.Close()
+	}
+
+	names, err := expandArgs([]string{dir, filepath.Join(dir, "*.ipa"), "missing.apk"})
+	if err != nil {
+		t.Errorf("got %v want no error", err)
+	}
+	want := []string{
+		filepath.Join(dir, "a.apk"),
+		filepath.Join(dir, "b.ipa"),
+		filepath.Join(dir, "build.zip"),
+		filepath.Join(dir, "sub/c.apk"),
+		filepath.Join(dir, "b.ipa"),
+		"missing.apk",
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("got %v want %v", names, want)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/follyxing/appfile-info"
+)
+
+// diffMain reports the permissions and entitlements new adds or removes
+// compared to old. Like diff(1) it exits with 1 when there are changes.
+func diffMain(args []string) int {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print the changes as JSON")
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		usage()
+	}
+
+	var infos [2]*appfile.AppInfo
+	for i, name := range fs.Args() {
+		info, err := appfile.NewAppParser(name)
+		if info == nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			return 2
+		}
+		infos[i] = info
+	}
+
+	d := appfile.DiffPermissions(infos[0], infos[1])
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(d)
+	} else {
+		writeDiff(os.Stdout, d)
+	}
+	if d.Empty() {
+		return 0
+	}
+	return 1
+}
+
+func writeDiff(w io.Writer, d *appfile.PermissionDiff) {
+	for _, p := range d.AddedPermissions {
+		l := appfile.LookupPermission(p)
+		fmt.Fprintf(w, "+ permission %s (%s)\n", p, l.ProtectionLevel)
+	}
+	for _, p := range d.RemovedPermissions {
+		fmt.Fprintf(w, "- permission %s\n", p)
+	}
+	for _, e := range d.AddedEntitlements {
+		fmt.Fprintf(w, "+ entitlement %s\n", e)
+	}
+	for _, e := range d.RemovedEntitlements {
+		fmt.Fprintf(w, "- entitlement %s\n", e)
+	}
+}
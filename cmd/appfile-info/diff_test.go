@@ -0,0 +1,26 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/follyxing/appfile-info"
+)
+
+func TestWriteDiff(t *testing.T) {
+	var buf bytes.Buffer
+	writeDiff(&buf, &appfile.PermissionDiff{
+		AddedPermissions:    []string{"android.permission.CAMERA"},
+		RemovedPermissions:  []string{"android.permission.READ_SMS"},
+		AddedEntitlements:   []string{"aps-environment=production"},
+		RemovedEntitlements: []string{"aps-environment=development"},
+	})
+	want := `+ permission android.permission.CAMERA (dangerous)
+- permission android.permission.READ_SMS
++ entitlement aps-environment=production
+- entitlement aps-environment=development
+`
+	if buf.String() != want {
+		t.Errorf("got %v want %v", buf.String(), want)
+	}
+}
@@ -0,0 +1,190 @@
+// Command appfile-info prints metadata of .apk and .ipa files.
+//
+//	appfile-info [flags] file|dir|glob...
+//	appfile-info watch [flags] dir...
+//	appfile-info diff [flags] old new
+//	appfile-info manifest file
+//	appfile-info profile|entitlements [-o file] file.ipa
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/follyxing/appfile-info"
+	"github.com/follyxing/appfile-info/format"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: appfile-info [flags] file|dir|glob...\n")
+	fmt.Fprintf(os.Stderr, "       appfile-info watch [flags] dir...\n")
+	fmt.Fprintf(os.Stderr, "       appfile-info diff [flags] old new\n")
+	fmt.Fprintf(os.Stderr, "       appfile-info manifest file\n")
+	fmt.Fprintf(os.Stderr, "       appfile-info profile|entitlements [-o file] file.ipa\n")
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "watch":
+			os.Exit(watchMain(os.Args[2:]))
+		case "diff":
+			os.Exit(diffMain(os.Args[2:]))
+		case "profile", "entitlements":
+			os.Exit(profileMain(os.Args[2:], os.Args[1] == "entitlements"))
+		case "manifest":
+			if len(os.Args) != 3 {
+				usage()
+			}
+			if err := appfile.WriteManifestXML(os.Args[2], os.Stdout); err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", os.Args[2], err)
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
+	}
+	os.Exit(parseMain(os.Args[1:]))
+}
+
+var formatters = map[string]format.Formatter{
+	"json":        format.JSON,
+	"badging":     format.Badging,
+	"codesign":    format.Codesign,
+	"fastlane":    format.Fastlane,
+	"profile":     format.Profile,
+	"permissions": format.Permissions,
+}
+
+// output receives the parse result of every artifact.
+type output interface {
+	Write(name string, info *appfile.AppInfo) error
+	Flush() error
+}
+
+type formatterOutput struct {
+	f format.Formatter
+	w io.Writer
+}
+
+func (o formatterOutput) Write(name string, info *appfile.AppInfo) error { return o.f(o.w, info) }
+func (o formatterOutput) Flush() error                                   { return nil }
+
+func newOutput(name, tmpl, jsonPath string) (output, error) {
+	switch {
+	case tmpl != "" && jsonPath != "":
+		return nil, errors.New("-format and -jsonpath are mutually exclusive")
+	case tmpl != "":
+		f, err := format.Template(tmpl)
+		return formatterOutput{f, os.Stdout}, err
+	case jsonPath != "":
+		f, err := format.JSONPath(jsonPath)
+		return formatterOutput{f, os.Stdout}, err
+	case name == "csv":
+		return format.NewCSV(os.Stdout), nil
+	case name == "tsv":
+		return format.NewTSV(os.Stdout), nil
+	}
+	f, ok := formatters[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown output format %q", name)
+	}
+	return formatterOutput{f, os.Stdout}, nil
+}
+
+func parseMain(args []string) int {
+	fs := flag.NewFlagSet("appfile-info", flag.ExitOnError)
+	outputName := fs.String("o", "json", "output `format`: json, badging, permissions, codesign, profile, fastlane, csv, tsv")
+	tmpl := fs.String("format", "", "print each result using a Go `template`, e.g. '{{.BundleId}} {{.Version}}'")
+	jsonPath := fs.String("jsonpath", "", "print the values selected by a JSONPath-like `expression`, e.g. '{.ios.profile.team_id}'")
+	scanURLs := fs.Bool("urls", false, "collect the hosts of URLs found in the app")
+	tolerantZip := fs.Bool("tolerant-zip", false, "rebuild archives with malformed or duplicate zip entries instead of failing")
+	recoverZip := fs.Bool("recover-zip", false, "parse truncated archives or ones with a damaged central directory from their local headers")
+	password := fs.String("archive-password", "", "decrypt password-protected archives with this `password`")
+	progress := fs.Bool("progress", false, "report the progress of large files on stderr")
+	framework := fs.String("framework", "", "resolve @android: resources of system APKs with this framework-res.apk `file`")
+	fs.Usage = usage
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		usage()
+	}
+
+	out, err := newOutput(*outputName, *tmpl, *jsonPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	names, err := expandArgs(fs.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	var opts []appfile.Option
+	if *scanURLs {
+		opts = append(opts, appfile.WithURLScan())
+	}
+	if *tolerantZip {
+		opts = append(opts, appfile.WithTolerantZip())
+	}
+	if *recoverZip {
+		opts = append(opts, appfile.WithZipRecovery())
+	}
+	if *password != "" {
+		opts = append(opts, appfile.WithArchivePassword(*password))
+	}
+	if *framework != "" {
+		opts = append(opts, appfile.WithFrameworkResources(*framework))
+	}
+	if *progress {
+		opts = append(opts, appfile.WithProgress(func(stage string, done, total int64) {
+			if total > 0 {
+				fmt.Fprintf(os.Stderr, "\r%s %3d%%\x1b[K", stage, done*100/total)
+			}
+			if stage == appfile.StageParse && done == total {
+				fmt.Fprintln(os.Stderr)
+			}
+		}))
+	}
+
+	status := 0
+	for _, name := range names {
+		info, err := appfile.NewAppParser(name, opts...)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			status = 1
+		}
+		if info == nil {
+			continue
+		}
+		if err := out.Write(name, info); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			status = 1
+		}
+	}
+	if err := out.Flush(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		status = 1
+	}
+	return status
+}
+
+func signalContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt)
+	go func() {
+		select {
+		case <-c:
+			cancel()
+		case <-ctx.Done():
+		}
+		signal.Stop(c)
+	}()
+	return ctx, cancel
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/follyxing/appfile-info"
+)
+
+// profileMain writes the decoded embedded.mobileprovision, or with
+// entitlements set only its entitlements, of an IPA as a plist.
+func profileMain(args []string, entitlements bool) int {
+	name := "profile"
+	if entitlements {
+		name = "entitlements"
+	}
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	out := fs.String("o", "-", "write the plist to `file` instead of stdout")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		usage()
+	}
+
+	data, err := profileData(fs.Arg(0), entitlements)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", fs.Arg(0), err)
+		return 1
+	}
+	if *out == "-" {
+		_, err = os.Stdout.Write(data)
+	} else {
+		err = ioutil.WriteFile(*out, data, 0644)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func profileData(name string, entitlements bool) ([]byte, error) {
+	info, err := appfile.NewAppParser(name)
+	if info == nil {
+		return nil, err
+	}
+	if info.Ios == nil || info.Ios.Profile == nil {
+		return nil, errors.New("no provisioning profile")
+	}
+	if entitlements {
+		return info.Ios.Profile.EntitlementsPlist()
+	}
+	return info.Ios.Profile.Data, nil
+}
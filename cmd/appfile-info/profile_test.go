@@ -0,0 +1,9 @@
+package main
+
+import "testing"
+
+func TestProfileDataNoProfile(t *testing.T) {
+	if _, err := profileData("../../testdata/helloworld.apk", false); err == nil || err.Error() != "no provisioning profile" {
+		t.Errorf("got %v want %v", err, "no provisioning profile")
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/follyxing/appfile-info/sink"
+	"github.com/follyxing/appfile-info/watch"
+)
+
+func watchMain(args []string) int {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	jsonPath := fs.String("json", "", "append results as JSON lines to `file`")
+	webhook := fs.String("webhook", "", "POST results as JSON to `url`")
+	settle := fs.Duration("settle", 2*time.Second, "wait this long after the last write before parsing")
+	existing := fs.Bool("existing", false, "also ingest files already in the directories")
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		usage()
+	}
+
+	var s sink.Sink
+	switch {
+	case *jsonPath != "" && *webhook != "":
+		fmt.Fprintln(os.Stderr, "watch: -json and -webhook are mutually exclusive")
+		return 2
+	case *webhook != "":
+		s = sink.NewWebhook(*webhook, nil)
+	case *jsonPath != "":
+		s = sink.NewJSONFile(*jsonPath)
+	default:
+		s = sink.NewJSONWriter(os.Stdout)
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	w := &watch.Watcher{Sink: s, Settle: *settle, Existing: *existing}
+	if err := w.Run(ctx, fs.Args()...); err != nil && err != context.Canceled {
+		fmt.Fprintf(os.Stderr, "watch: %v\n", err)
+		return 1
+	}
+	return 0
+}
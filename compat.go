@@ -0,0 +1,215 @@
+package appfile
+
+import (
+	"archive/zip"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Device classes of Compatibility.
+const (
+	DevicePhone   = "phone"
+	DeviceTablet  = "tablet"
+	DeviceTV      = "tv"
+	DeviceWatch   = "watch"
+	DeviceCar     = "car"
+	DeviceDesktop = "desktop"
+	DeviceVision  = "vision"
+)
+
+const (
+	featureLeanback   = "android.software.leanback"
+	featureWatch      = "android.hardware.type.watch"
+	featureAutomotive = "android.hardware.type.automotive"
+	featurePC         = "android.hardware.type.pc"
+)
+
+// androidVersions are the Android releases by API level.
+var androidVersions = []string{
+	1: "1.0", "1.1", "1.5", "1.6", "2.0", "2.0.1", "2.1", "2.2", "2.3", "2.3.3",
+	"3.0", "3.1", "3.2", "4.0", "4.0.3", "4.1", "4.2", "4.3", "4.4", "4.4W",
+	"5.0", "5.1", "6.0", "7.0", "7.1", "8.0", "8.1", "9", "10", "11",
+	"12", "12L", "13", "14", "15", "16",
+}
+
+// iosDeviceFamilies are the UIDeviceFamily values.
+var iosDeviceFamilies = map[int]string{
+	1: DevicePhone,
+	2: DeviceTablet,
+	3: DeviceTV,
+	4: DeviceWatch,
+	6: DeviceDesktop, // Mac Catalyst
+	7: DeviceVision,  // Apple Vision Pro
+}
+
+// Compatibility is where an app installs, in the same terms for both
+// platforms. MinOS and TargetOS are OS versions, e.g. "8.0" for Android API
+// level 26 or "15.0" on iOS. Archs are normalized to arm, arm64, x86 and
+// x86_64; none means the app has no native code and runs on all of them.
+// Features are the device features or capabilities the app requires.
+type Compatibility struct {
+	Platform       string   `json:"platform"`
+	MinOS          string   `json:"min_os,omitempty"`
+	TargetOS       string   `json:"target_os,omitempty"`
+	MinAPILevel    int      `json:"min_api_level,omitempty"`
+	TargetAPILevel int      `json:"target_api_level,omitempty"`
+	Devices        []string `json:"devices"`
+	Archs          []string `json:"archs,omitempty"`
+	Features       []string `json:"features,omitempty"`
+}
+
+// Compatibility summarizes the devices and OS versions info supports, or
+// returns nil for platforms other than Android and iOS.
+func (info *AppInfo) Compatibility() *Compatibility {
+	switch {
+	case info.Android != nil:
+		return androidCompatibility(info.Android)
+	case info.Ios != nil:
+		return iosCompatibility(info.Ios)
+	}
+	return nil
+}
+
+func androidCompatibility(a *AndroidInfo) *Compatibility {
+	c := &Compatibility{Platform: PlatformAndroid, Features: a.RequiredFeatures}
+	// Without android:minSdkVersion the app installs from API level 1.
+	c.MinAPILevel, c.TargetAPILevel = 1, atoi(a.TargetSdkVersion)
+	if a.MinSdkVersion != "" {
+		c.MinAPILevel = atoi(a.MinSdkVersion)
+	}
+	c.MinOS = androidVersion(c.MinAPILevel)
+	c.TargetOS = androidVersion(c.TargetAPILevel)
+
+	required := make(map[string]bool)
+	for _, f := range a.RequiredFeatures {
+		required[f] = true
+	}
+	declared := func(f string) bool {
+		if required[f] {
+			return true
+		}
+		for _, o := range a.OptionalFeatures {
+			if o == f {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case required[featureLeanback]:
+		c.Devices = []string{DeviceTV}
+	case required[featureWatch]:
+		c.Devices = []string{DeviceWatch}
+	case required[featureAutomotive]:
+		c.Devices = []string{DeviceCar}
+	default:
+		c.Devices = []string{DevicePhone}
+		if a.Screens == nil || a.Screens.Tablets() {
+			c.Devices = append(c.Devices, DeviceTablet)
+		}
+		if declared(featureLeanback) {
+			c.Devices = append(c.Devices, DeviceTV)
+		}
+		if declared(featurePC) {
+			c.Devices = append(c.Devices, DeviceDesktop)
+		}
+	}
+
+	for _, abi := range a.ABIs {
+		c.Archs = appendUnique(c.Archs, normalizeArch(abi))
+	}
+	sort.Strings(c.Archs)
+	return c
+}
+
+func iosCompatibility(i *IosInfo) *Compatibility {
+	c := &Compatibility{
+		Platform: PlatformIOS,
+		MinOS:    i.MinimumOSVersion,
+		TargetOS: strings.TrimLeft(i.SDKName, "abcdefghijklmnopqrstuvwxyz"),
+		Features: i.RequiredCapabilities,
+	}
+	for _, f := range i.DeviceFamilies {
+		c.Devices = appendUnique(c.Devices, f)
+	}
+	// Before UIDeviceFamily, apps were iPhone apps.
+	if len(c.Devices) == 0 {
+		c.Devices = []string{DevicePhone}
+	}
+	for _, b := range i.Binaries {
+		if strings.Contains(b.Path, "/") {
+			continue
+		}
+		for _, a := range b.Archs {
+			c.Archs = appendUnique(c.Archs, normalizeArch(a))
+		}
+	}
+	sort.Strings(c.Archs)
+	return c
+}
+
+func androidVersion(level int) string {
+	if level > 0 && level < len(androidVersions) {
+		return androidVersions[level]
+	}
+	return ""
+}
+
+// normalizeArch maps Android ABIs and Mach-O architectures to arm, arm64,
+// x86 and x86_64.
+func normalizeArch(a string) string {
+	switch {
+	case strings.HasPrefix(a, "arm64"):
+		return "arm64"
+	case strings.HasPrefix(a, "armeabi"), strings.HasPrefix(a, "armv"):
+		return "arm"
+	case a == "i386":
+		return "x86"
+	}
+	return a
+}
+
+// iosDeviceFamily returns the device class of a UIDeviceFamily value.
+func iosDeviceFamily(v int) string {
+	if f, ok := iosDeviceFamilies[v]; ok {
+		return f
+	}
+	return strconv.Itoa(v)
+}
+
+// requiredCapabilities returns the UIRequiredDeviceCapabilities that are
+// required; the dictionary form can also list prohibited ones.
+func requiredCapabilities(v interface{}) []string {
+	var caps []string
+	switch v := v.(type) {
+	case []interface{}:
+		for _, c := range v {
+			if s, ok := c.(string); ok {
+				caps = append(caps, s)
+			}
+		}
+	case map[string]interface{}:
+		for c, required := range v {
+			if b, ok := required.(bool); ok && b {
+				caps = append(caps, c)
+			}
+		}
+		sort.Strings(caps)
+	}
+	return caps
+}
+
+// apkABIs returns the ABIs an APK has native libraries for.
+func apkABIs(files []*zip.File) []string {
+	var abis []string
+	for _, f := range files {
+		parts := strings.Split(f.Name, "/")
+		if len(parts) == 3 && parts[0] == "lib" && path.Ext(parts[2]) == ".so" {
+			abis = appendUnique(abis, parts[1])
+		}
+	}
+	sort.Strings(abis)
+	return abis
+}
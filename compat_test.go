@@ -0,0 +1,70 @@
+package appfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAndroidCompatibility(t *testing.T) {
+	m := new(androidManifest)
+	m.UsesSdk = androidUsesSdk{MinSdkVersion: "26", TargetSdkVersion: "34"}
+	m.UsesFeatures = []androidUsesFeature{
+		{Name: "android.hardware.camera"},
+		{Name: "android.software.leanback", Required: "false"},
+		{Required: "true"},
+	}
+	info := newAndroidAppInfo(m)
+	info.Android.ABIs = []string{"arm64-v8a", "armeabi-v7a", "x86_64"}
+
+	want := &Compatibility{
+		Platform:       PlatformAndroid,
+		MinOS:          "8.0",
+		TargetOS:       "14",
+		MinAPILevel:    26,
+		TargetAPILevel: 34,
+		Devices:        []string{DevicePhone, DeviceTablet, DeviceTV},
+		Archs:          []string{"arm", "arm64", "x86_64"},
+		Features:       []string{"android.hardware.camera"},
+	}
+	if got := info.Compatibility(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+
+	m = new(androidManifest)
+	m.UsesFeatures = []androidUsesFeature{{Name: "android.hardware.type.watch"}}
+	got := newAndroidAppInfo(m).Compatibility()
+	if got.MinAPILevel != 1 || got.MinOS != "1.0" || !reflect.DeepEqual(got.Devices, []string{DeviceWatch}) {
+		t.Errorf("got %+v want watch from API level 1", got)
+	}
+}
+
+func TestIosCompatibility(t *testing.T) {
+	info := newAppInfo(PlatformIOS)
+	info.Ios.MinimumOSVersion = "15.0"
+	info.Ios.SDKName = "iphoneos17.2"
+	info.Ios.DeviceFamilies = []string{iosDeviceFamily(1), iosDeviceFamily(2)}
+	info.Ios.RequiredCapabilities = requiredCapabilities(map[string]interface{}{"nfc": true, "arm64": true, "gamekit": false})
+	info.Ios.Binaries = []IosBinary{
+		{Path: "App", Archs: []string{"arm64"}},
+		{Path: "Frameworks/Old.framework/Old", Archs: []string{"armv7", "arm64"}},
+	}
+
+	want := &Compatibility{
+		Platform: PlatformIOS,
+		MinOS:    "15.0",
+		TargetOS: "17.2",
+		Devices:  []string{DevicePhone, DeviceTablet},
+		Archs:    []string{"arm64"},
+		Features: []string{"arm64", "nfc"},
+	}
+	if got := info.Compatibility(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+
+	if got := requiredCapabilities([]interface{}{"armv7", "wifi"}); !reflect.DeepEqual(got, []string{"armv7", "wifi"}) {
+		t.Errorf("got %v want %v", got, []string{"armv7", "wifi"})
+	}
+	if got := newAppInfo(PlatformTizen).Compatibility(); got != nil {
+		t.Errorf("got %+v want nil", got)
+	}
+}
@@ -0,0 +1,226 @@
+package appfile
+
+import (
+	"encoding/xml"
+	"errors"
+	"strings"
+)
+
+// Intent actions and meta-data of app widgets, static shortcuts and Quick
+// Settings tiles.
+const (
+	actionAppWidgetUpdate = "android.appwidget.action.APPWIDGET_UPDATE"
+	actionQSTile          = "android.service.quicksettings.action.QS_TILE"
+	metaAppWidgetProvider = "android.appwidget.provider"
+	metaShortcuts         = "android.app.shortcuts"
+	metaToggleableTile    = "android.service.quicksettings.TOGGLEABLE_TILE"
+	metaActiveTile        = "android.service.quicksettings.ACTIVE_TILE"
+
+	actionAccessibilityService = "android.accessibilityservice.AccessibilityService"
+	actionDeviceAdminEnabled   = "android.app.action.DEVICE_ADMIN_ENABLED"
+	actionVpnService           = "android.net.VpnService"
+	metaAccessibilityService   = "android.accessibilityservice"
+	metaDeviceAdmin            = "android.app.device_admin"
+)
+
+// Kinds of PrivilegedComponent.
+const (
+	ComponentAccessibility = "accessibility"
+	ComponentDeviceAdmin   = "device-admin"
+	ComponentVpn           = "vpn"
+)
+
+type androidComponent struct {
+	Name       string `xml:"name,attr"`
+	Label      string `xml:"label,attr"`
+	Enabled    string `xml:"enabled,attr"`
+	Permission string `xml:"permission,attr"`
+	// ForegroundServiceType is only set on services.
+	ForegroundServiceType string                `xml:"foregroundServiceType,attr"`
+	IntentFilters         []androidIntentFilter `xml:"intent-filter"`
+	MetaData              []androidMetaData     `xml:"meta-data"`
+}
+
+type androidWidgetProvider struct {
+	Configure   string `xml:"configure,attr"`
+	Description string `xml:"description,attr"`
+}
+
+type androidAccessibilityService struct {
+	Description         string `xml:"description,attr"`
+	PackageNames        string `xml:"packageNames,attr"`
+	RetrieveWindow      string `xml:"canRetrieveWindowContent,attr"`
+	PerformGestures     string `xml:"canPerformGestures,attr"`
+	FilterKeyEvents     string `xml:"canRequestFilterKeyEvents,attr"`
+	TakeScreenshot      string `xml:"canTakeScreenshot,attr"`
+	FingerprintGestures string `xml:"canRequestFingerprintGestures,attr"`
+	TouchExploration    string `xml:"canRequestTouchExplorationMode,attr"`
+}
+
+type androidDeviceAdmin struct {
+	Policies struct {
+		Policies []struct {
+			XMLName xml.Name
+		} `xml:",any"`
+	} `xml:"uses-policies"`
+}
+
+type androidShortcuts struct {
+	Shortcuts []struct {
+		Id         string `xml:"shortcutId,attr"`
+		ShortLabel string `xml:"shortcutShortLabel,attr"`
+		LongLabel  string `xml:"shortcutLongLabel,attr"`
+		Enabled    string `xml:"enabled,attr"`
+	} `xml:"shortcut"`
+}
+
+func (c *androidComponent) handles(action string) bool {
+	for _, f := range c.IntentFilters {
+		if hasAction(f, action) {
+			return true
+		}
+	}
+	return false
+}
+
+func metaDataNamed(meta []androidMetaData, name string) *androidMetaData {
+	for i := range meta {
+		if meta[i].Name == name {
+			return &meta[i]
+		}
+	}
+	return nil
+}
+
+// parseApkComponents lists the app widgets, static shortcuts and Quick
+// Settings tiles of the manifest. Widget provider and shortcut files that
+// cannot be read leave the fields they would fill empty.
+func parseApkComponents(res *apkResources, manifest *androidManifest, android *AndroidInfo) {
+	for _, r := range manifest.Application.Receivers {
+		if !r.handles(actionAppWidgetUpdate) {
+			continue
+		}
+		w := AppWidget{Name: manifest.className(r.Name), Label: res.stringValue(r.Label)}
+		if m := metaDataNamed(r.MetaData, metaAppWidgetProvider); m != nil {
+			w.Provider = res.refName(m.Resource)
+			var p androidWidgetProvider
+			if decodeResourceXML(res, m.Resource, &p) == nil {
+				w.Configure = manifest.className(p.Configure)
+				w.Description = res.stringValue(p.Description)
+			}
+		}
+		android.Widgets = append(android.Widgets, w)
+	}
+
+	for _, a := range manifest.Application.Activities {
+		m := metaDataNamed(a.MetaData, metaShortcuts)
+		if m == nil {
+			continue
+		}
+		var s androidShortcuts
+		if decodeResourceXML(res, m.Resource, &s) != nil {
+			continue
+		}
+		for _, sc := range s.Shortcuts {
+			android.Shortcuts = append(android.Shortcuts, Shortcut{
+				Id:         sc.Id,
+				ShortLabel: res.stringValue(sc.ShortLabel),
+				LongLabel:  res.stringValue(sc.LongLabel),
+				Enabled:    sc.Enabled != "false",
+				Activity:   manifest.className(a.Name),
+			})
+		}
+	}
+
+	for _, s := range manifest.Application.Services {
+		if !s.handles(actionQSTile) {
+			continue
+		}
+		t := QuickSettingsTile{Name: manifest.className(s.Name), Label: res.stringValue(s.Label)}
+		if m := metaDataNamed(s.MetaData, metaToggleableTile); m != nil {
+			t.Toggleable = m.Value == "true"
+		}
+		if m := metaDataNamed(s.MetaData, metaActiveTile); m != nil {
+			t.Active = m.Value == "true"
+		}
+		android.Tiles = append(android.Tiles, t)
+	}
+}
+
+// parsePrivilegedComponents lists the accessibility services, device
+// admin receivers and VPN services of the manifest. The system only binds
+// them when they are protected by the matching BIND_ permission, so a
+// component handling the action without it is listed with an empty
+// Permission.
+func parsePrivilegedComponents(res *apkResources, manifest *androidManifest, android *AndroidInfo) {
+	for _, s := range manifest.Application.Services {
+		switch {
+		case s.handles(actionAccessibilityService):
+			c := newPrivilegedComponent(ComponentAccessibility, res, manifest, &s)
+			if m := metaDataNamed(s.MetaData, metaAccessibilityService); m != nil {
+				c.Config = res.refName(m.Resource)
+				var a androidAccessibilityService
+				if decodeResourceXML(res, m.Resource, &a) == nil {
+					c.Description = res.stringValue(a.Description)
+					for _, f := range []struct{ value, name string }{
+						{a.RetrieveWindow, "retrieve-window-content"},
+						{a.PerformGestures, "perform-gestures"},
+						{a.FilterKeyEvents, "filter-key-events"},
+						{a.TakeScreenshot, "take-screenshot"},
+						{a.FingerprintGestures, "fingerprint-gestures"},
+						{a.TouchExploration, "touch-exploration"},
+					} {
+						if f.value == "true" {
+							c.Capabilities = append(c.Capabilities, f.name)
+						}
+					}
+					if a.PackageNames != "" {
+						c.Packages = strings.Split(a.PackageNames, ",")
+					}
+				}
+			}
+			android.PrivilegedComponents = append(android.PrivilegedComponents, c)
+		case s.handles(actionVpnService):
+			android.PrivilegedComponents = append(android.PrivilegedComponents, newPrivilegedComponent(ComponentVpn, res, manifest, &s))
+		}
+	}
+
+	for _, r := range manifest.Application.Receivers {
+		if !r.handles(actionDeviceAdminEnabled) {
+			continue
+		}
+		c := newPrivilegedComponent(ComponentDeviceAdmin, res, manifest, &r)
+		if m := metaDataNamed(r.MetaData, metaDeviceAdmin); m != nil {
+			c.Config = res.refName(m.Resource)
+			var d androidDeviceAdmin
+			if decodeResourceXML(res, m.Resource, &d) == nil {
+				for _, p := range d.Policies.Policies {
+					c.Capabilities = append(c.Capabilities, p.XMLName.Local)
+				}
+			}
+		}
+		android.PrivilegedComponents = append(android.PrivilegedComponents, c)
+	}
+}
+
+func newPrivilegedComponent(kind string, res *apkResources, manifest *androidManifest, c *androidComponent) PrivilegedComponent {
+	return PrivilegedComponent{
+		Kind:       kind,
+		Name:       manifest.className(c.Name),
+		Label:      res.stringValue(c.Label),
+		Permission: c.Permission,
+	}
+}
+
+// decodeResourceXML decodes the binary XML resource ref into v.
+func decodeResourceXML(res *apkResources, ref string, v interface{}) error {
+	f := res.file(ref)
+	if f == nil {
+		return errors.New(ref + " not found")
+	}
+	xmlFile, err := readZipAndroidXML(f)
+	if err != nil {
+		return err
+	}
+	return entryError(f.Name, xml.NewDecoder(xmlFile.Reader()).Decode(v))
+}
@@ -0,0 +1,96 @@
+package appfile
+
+import (
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+const componentsManifest = `<manifest xmlns:android="http://schemas.android.com/apk/res/android" package="com.example.helloworld">
+  <application>
+    <activity android:name=".MainActivity">
+      <meta-data android:name="android.app.shortcuts" android:resource="@xml/shortcuts"/>
+    </activity>
+    <receiver android:name=".ClockWidget" android:label="%s">
+      <intent-filter><action android:name="android.appwidget.action.APPWIDGET_UPDATE"/></intent-filter>
+      <meta-data android:name="android.appwidget.provider" android:resource="@xml/clock_widget"/>
+    </receiver>
+    <receiver android:name=".BootReceiver">
+      <intent-filter><action android:name="android.intent.action.BOOT_COMPLETED"/></intent-filter>
+    </receiver>
+    <service android:name="com.example.tiles.FlashlightTile" android:label="Flashlight">
+      <intent-filter><action android:name="android.service.quicksettings.action.QS_TILE"/></intent-filter>
+      <meta-data android:name="android.service.quicksettings.TOGGLEABLE_TILE" android:value="true"/>
+    </service>
+  </application>
+</manifest>`
+
+func TestParseApkComponents(t *testing.T) {
+	reader, err := getAppZipReader("testdata/helloworld.apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := newApkResources(reader.File)
+	label := fmt.Sprintf("@0x%08X", res.load().ids["string/app_name"])
+
+	manifest := new(androidManifest)
+	if err := xml.Unmarshal([]byte(fmt.Sprintf(componentsManifest, label)), manifest); err != nil {
+		t.Fatal(err)
+	}
+	android := new(AndroidInfo)
+	parseApkComponents(res, manifest, android)
+
+	widgets := []AppWidget{{Name: "com.example.helloworld.ClockWidget", Label: res.stringValue(label), Provider: "@xml/clock_widget"}}
+	if !reflect.DeepEqual(android.Widgets, widgets) || widgets[0].Label == label {
+		t.Errorf("got %+v want %+v", android.Widgets, widgets)
+	}
+	// The shortcuts resource is not in the APK.
+	if android.Shortcuts != nil {
+		t.Errorf("got %+v want none", android.Shortcuts)
+	}
+	tiles := []QuickSettingsTile{{Name: "com.example.tiles.FlashlightTile", Label: "Flashlight", Toggleable: true}}
+	if !reflect.DeepEqual(android.Tiles, tiles) {
+		t.Errorf("got %+v want %+v", android.Tiles, tiles)
+	}
+}
+
+func TestParsePrivilegedComponents(t *testing.T) {
+	manifest := new(androidManifest)
+	manifest.Package = "com.example.helloworld"
+	manifest.Application.Services = []androidComponent{{
+		Name:          ".Reader",
+		Permission:    "android.permission.BIND_ACCESSIBILITY_SERVICE",
+		IntentFilters: []androidIntentFilter{{Actions: []androidName{{actionAccessibilityService}}}},
+		MetaData:      []androidMetaData{{Name: metaAccessibilityService, Resource: "@xml/reader"}},
+	}, {
+		Name:          ".Tunnel",
+		IntentFilters: []androidIntentFilter{{Actions: []androidName{{actionVpnService}}}},
+	}}
+	manifest.Application.Receivers = []androidComponent{{
+		Name:          ".Admin",
+		Permission:    "android.permission.BIND_DEVICE_ADMIN",
+		IntentFilters: []androidIntentFilter{{Actions: []androidName{{actionDeviceAdminEnabled}}}},
+	}}
+
+	android := new(AndroidInfo)
+	parsePrivilegedComponents(newApkResources(nil), manifest, android)
+	want := []PrivilegedComponent{
+		{Kind: ComponentAccessibility, Name: "com.example.helloworld.Reader", Permission: "android.permission.BIND_ACCESSIBILITY_SERVICE", Config: "@xml/reader"},
+		{Kind: ComponentVpn, Name: "com.example.helloworld.Tunnel"},
+		{Kind: ComponentDeviceAdmin, Name: "com.example.helloworld.Admin", Permission: "android.permission.BIND_DEVICE_ADMIN"},
+	}
+	if !reflect.DeepEqual(android.PrivilegedComponents, want) {
+		t.Errorf("got %+v want %+v", android.PrivilegedComponents, want)
+	}
+}
+
+func TestDecodeDeviceAdmin(t *testing.T) {
+	var d androidDeviceAdmin
+	if err := xml.Unmarshal([]byte(`<device-admin><uses-policies><force-lock/><wipe-data/></uses-policies></device-admin>`), &d); err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Policies.Policies) != 2 || d.Policies.Policies[1].XMLName.Local != "wipe-data" {
+		t.Errorf("got %+v want force-lock and wipe-data", d.Policies.Policies)
+	}
+}
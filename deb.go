@@ -0,0 +1,271 @@
+package appfile
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"io/ioutil"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const debExt = ".deb"
+
+var errDeb = errors.New("malformed deb")
+
+// arMember is a member of the ar archive a .deb is made of.
+type arMember struct {
+	name string
+	r    *io.SectionReader
+}
+
+func readArMembers(r io.ReaderAt, size int64) ([]arMember, error) {
+	magic := make([]byte, 8)
+	if _, err := r.ReadAt(magic, 0); err != nil || string(magic) != "!<arch>\n" {
+		return nil, errDeb
+	}
+
+	var members []arMember
+	header := make([]byte, 60)
+	for off := int64(8); off < size; {
+		if _, err := r.ReadAt(header, off); err != nil || string(header[58:]) != "`\n" {
+			return nil, errDeb
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(string(header[48:58])), 10, 64)
+		if err != nil || n < 0 || off+60+n > size {
+			return nil, errDeb
+		}
+		name := strings.TrimSuffix(strings.TrimSpace(string(header[:16])), "/")
+		members = append(members, arMember{name, io.NewSectionReader(r, off+60, n)})
+		off += 60 + n + n%2
+	}
+	return members, nil
+}
+
+// openDebTar returns a reader of a control.tar or data.tar member. Only
+// uncompressed and gzip members can be read; xz and zstd need libraries
+// the standard library lacks.
+func openDebTar(m arMember) (*tar.Reader, error) {
+	m.r.Seek(0, io.SeekStart)
+	switch path.Ext(m.name) {
+	case ".tar":
+		return tar.NewReader(m.r), nil
+	case ".gz":
+		zr, err := gzip.NewReader(m.r)
+		if err != nil {
+			return nil, err
+		}
+		return tar.NewReader(zr), nil
+	}
+	return nil, fmt.Errorf("%s: unsupported compression", m.name)
+}
+
+// parseDebControl reads the fields of a control file. Continuation lines
+// are joined with newlines.
+func parseDebControl(r io.Reader) map[string]string {
+	fields := make(map[string]string)
+	var key string
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := s.Text()
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			if key != "" {
+				fields[key] += "\n" + strings.TrimSpace(line)
+			}
+			continue
+		}
+		if i := strings.IndexByte(line, ':'); i > 0 {
+			key = line[:i]
+			fields[key] = strings.TrimSpace(line[i+1:])
+		}
+	}
+	return fields
+}
+
+func parseDeb(_ string, r io.ReaderAt, size int64, o *options) (*AppInfo, error) {
+	var errs stageErrors
+	end := o.startStage(StageZipRead)
+	members, err := readArMembers(r, size)
+	end(err)
+	if err != nil {
+		errs.add(StageZipRead, err)
+		return nil, errs.err()
+	}
+
+	var control, data *arMember
+	for i := range members {
+		switch {
+		case strings.HasPrefix(members[i].name, "control.tar"):
+			control = &members[i]
+		case strings.HasPrefix(members[i].name, "data.tar"):
+			data = &members[i]
+		}
+	}
+
+	end = o.startStage(StageManifest)
+	fields, err := readDebControl(control)
+	end(err)
+	if err != nil {
+		errs.add(StageManifest, err)
+		return nil, errs.err()
+	}
+
+	info := newAppInfo(PlatformDebian)
+	info.BundleId = fields["Package"]
+	info.Name = fields["Name"]
+	if info.Name == "" {
+		info.Name = info.BundleId
+	}
+	info.Version = fields["Version"]
+	info.Debian.Architecture = fields["Architecture"]
+	info.Debian.Maintainer = fields["Maintainer"]
+	info.Debian.Section = fields["Section"]
+	info.Debian.Depends = fields["Depends"]
+
+	end = o.startStage(StageIcon)
+	icon, err := readDebIcon(data, fields["Icon"])
+	end(err)
+	errs.add(StageIcon, err)
+	info.setIcon(icon)
+	return info, errs.err()
+}
+
+func readDebControl(m *arMember) (map[string]string, error) {
+	if m == nil {
+		return nil, errors.New("control.tar not found")
+	}
+	tr, err := openDebTar(*m)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return nil, errors.New("control file not found")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if path.Clean(h.Name) == "control" {
+			return parseDebControl(tr), nil
+		}
+	}
+}
+
+// readDebIcon decodes the icon of a package from its data archive: the
+// file:// Icon of Cydia packages, or else the icon its desktop entry names
+// under usr/share/icons/hicolor or usr/share/pixmaps, preferring the
+// largest.
+func readDebIcon(m *arMember, icon string) (image.Image, error) {
+	if m == nil {
+		return nil, ErrNoIcon
+	}
+
+	var match func(name string) int
+	if p := strings.TrimPrefix(icon, "file://"); p != icon {
+		p = strings.TrimPrefix(path.Clean(p), "/")
+		match = func(name string) int {
+			if name == p {
+				return 1
+			}
+			return 0
+		}
+	} else {
+		tr, err := openDebTar(*m)
+		if err != nil {
+			return nil, err
+		}
+		icon, err = desktopEntryIcon(tr)
+		if err != nil {
+			return nil, err
+		}
+		match = func(name string) int { return linuxIconSize(name, icon) }
+	}
+	if icon == "" {
+		return nil, ErrNoIcon
+	}
+
+	tr, err := openDebTar(*m)
+	if err != nil {
+		return nil, err
+	}
+	var best []byte
+	bestSize := 0
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if h.Typeflag != tar.TypeReg || h.Size > maxEntrySize {
+			continue
+		}
+		if size := match(strings.TrimPrefix(path.Clean(h.Name), "/")); size > bestSize {
+			if best, err = ioutil.ReadAll(tr); err != nil {
+				return nil, err
+			}
+			bestSize = size
+		}
+	}
+	if best == nil {
+		return nil, ErrNoIcon
+	}
+	img, _, err := image.Decode(bytes.NewReader(best))
+	return img, err
+}
+
+// desktopEntryIcon returns the Icon key of the first desktop entry in
+// usr/share/applications.
+func desktopEntryIcon(tr *tar.Reader) (string, error) {
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		name := strings.TrimPrefix(path.Clean(h.Name), "/")
+		if path.Dir(name) != "usr/share/applications" || path.Ext(name) != ".desktop" {
+			continue
+		}
+		s := bufio.NewScanner(tr)
+		for s.Scan() {
+			if v := strings.TrimPrefix(s.Text(), "Icon="); v != s.Text() {
+				return strings.TrimSpace(v), nil
+			}
+		}
+	}
+}
+
+// linuxIconSize returns the size of name if it is a PNG of the icon theme
+// icon, 1 for the unsized pixmaps, and 0 otherwise.
+func linuxIconSize(name, icon string) int {
+	if path.IsAbs(icon) {
+		if name == strings.TrimPrefix(path.Clean(icon), "/") {
+			return 1
+		}
+		return 0
+	}
+	if path.Base(name) != icon+".png" {
+		return 0
+	}
+	dir := path.Dir(name)
+	if dir == "usr/share/pixmaps" {
+		return 1
+	}
+	if !strings.HasPrefix(dir, "usr/share/icons/hicolor/") || path.Base(dir) != "apps" {
+		return 0
+	}
+	size := path.Base(path.Dir(dir)) // 48x48
+	n, _ := strconv.Atoi(strings.SplitN(size, "x", 2)[0])
+	return n + 1
+}
@@ -0,0 +1,113 @@
+package appfile
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func tarGz(t *testing.T, files map[string][]byte) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	for name, b := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(b)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write(b)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func writeDeb(t *testing.T, name string, control, data []byte) {
+	buf := bytes.NewBufferString("!<arch>\n")
+	for _, m := range []struct {
+		name string
+		b    []byte
+	}{
+		{"debian-binary", []byte("2.0\n")},
+		{"control.tar.gz", control},
+		{"data.tar.gz", data},
+	} {
+		fmt.Fprintf(buf, "%-16s%-12d%-6d%-6d%-8s%-10d`\n", m.name, 0, 0, 0, "100644", len(m.b))
+		buf.Write(m.b)
+		if len(m.b)%2 == 1 {
+			buf.WriteByte('\n')
+		}
+	}
+	if err := ioutil.WriteFile(name, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseDebCydia(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "tweak.deb")
+	writeDeb(t, name, tarGz(t, map[string][]byte{
+		"./control": []byte("Package: com.example.tweak\nName: Example Tweak\nVersion: 1.0-3\nArchitecture: iphoneos-arm\n" +
+			"Description: An example\n more text\nIcon: file:///Applications/Example.app/icon.png\n"),
+	}), tarGz(t, map[string][]byte{
+		"./Applications/Example.app/icon.png": testIcon(t),
+	}))
+
+	info, err := NewAppParser(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Platform != PlatformDebian || info.BundleId != "com.example.tweak" || info.Name != "Example Tweak" || info.Version != "1.0-3" {
+		t.Errorf("got %v %v %v %v", info.Platform, info.BundleId, info.Name, info.Version)
+	}
+	if info.Debian.Architecture != "iphoneos-arm" {
+		t.Errorf("got %v want %v", info.Debian.Architecture, "iphoneos-arm")
+	}
+	if info.Icon == nil {
+		t.Errorf("got no icon")
+	}
+}
+
+func TestParseDebDesktop(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "example_2.1_amd64.deb")
+	writeDeb(t, name, tarGz(t, map[string][]byte{
+		"./control": []byte("Package: example\nVersion: 2.1\nArchitecture: amd64\nDepends: libc6 (>= 2.31)\n"),
+	}), tarGz(t, map[string][]byte{
+		"./usr/share/applications/example.desktop":         []byte("[Desktop Entry]\nName=Example\nIcon=example\n"),
+		"./usr/share/icons/hicolor/32x32/apps/example.png": []byte("not a png"),
+		"./usr/share/icons/hicolor/64x64/apps/example.png": testIcon(t),
+	}))
+
+	info, err := NewAppParser(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Name != "example" || info.Debian.Depends != "libc6 (>= 2.31)" {
+		t.Errorf("got %v %v", info.Name, info.Debian.Depends)
+	}
+	if info.Icon == nil {
+		t.Errorf("got no icon")
+	}
+}
+
+func TestParseDebNoIcon(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "lib.deb")
+	writeDeb(t, name, tarGz(t, map[string][]byte{
+		"control": []byte("Package: libexample\nVersion: 1\n"),
+	}), tarGz(t, nil))
+
+	info, err := NewAppParser(name)
+	if !errors.Is(err, ErrNoIcon) {
+		t.Errorf("got %v want %v", err, ErrNoIcon)
+	}
+	if info == nil || info.BundleId != "libexample" {
+		t.Errorf("got %+v", info)
+	}
+}
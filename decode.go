@@ -0,0 +1,84 @@
+package appfile
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/follyxing/go-plist"
+	"github.com/shogo82148/androidbinary"
+)
+
+// ErrMalformed is returned for binary XML the decoders cannot cope with,
+// which untrusted uploads may contain. Where a decoder panics instead,
+// the stage fails with a *PanicError.
+var ErrMalformed = errors.New("malformed data")
+
+// entryError adds the archive entry or file being decoded to err.
+func entryError(name string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("decoding %s: %w", name, err)
+}
+
+// readZipPlist decodes the property list entry f into v.
+func readZipPlist(f *zip.File, v interface{}) error {
+	buf, err := readZipFile(f)
+	if err == nil {
+		err = decodePlist(buf, v)
+	}
+	return entryError(f.Name, err)
+}
+
+// readZipAndroidXML decodes the Android binary XML entry f.
+func readZipAndroidXML(f *zip.File) (*androidbinary.XMLFile, error) {
+	buf, err := readZipFile(f)
+	if err != nil {
+		return nil, entryError(f.Name, err)
+	}
+	xmlFile, err := decodeAndroidXML(buf)
+	if err != nil {
+		return nil, entryError(f.Name, err)
+	}
+	return xmlFile, nil
+}
+
+// decodePlist decodes the property list buf into v.
+func decodePlist(buf []byte, v interface{}) error {
+	return plist.NewDecoder(bytes.NewReader(buf)).Decode(v)
+}
+
+// decodeAndroidXML decodes the Android binary XML buf.
+func decodeAndroidXML(buf []byte) (*androidbinary.XMLFile, error) {
+	if err := checkAndroidXML(buf); err != nil {
+		return nil, err
+	}
+	return androidbinary.NewXMLFile(bytes.NewReader(buf))
+}
+
+// checkAndroidXML rejects binary XML with chunks or strings larger than
+// the data, as the Android runtime does, since decoders allocate for them
+// before reading.
+func checkAndroidXML(buf []byte) error {
+	const resXMLType = 0x0003
+	if len(buf) < 8 || le.Uint16(buf) != resXMLType {
+		return nil
+	}
+	headerSize, size := int(le.Uint16(buf[2:])), int(le.Uint32(buf[4:]))
+	err := errARSC
+	if size <= len(buf) && headerSize >= 8 && headerSize <= size {
+		err = arscChunks(buf[headerSize:size], func(typ uint16, chunk []byte) error {
+			if typ != resStringPoolType {
+				return nil
+			}
+			_, err := parseStringPool(chunk)
+			return err
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("%w: binary xml chunk sizes", ErrMalformed)
+	}
+	return nil
+}
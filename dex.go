@@ -0,0 +1,91 @@
+package appfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strings"
+)
+
+var errDex = errors.New("malformed dex file")
+
+// Packages whose classes keep their names in minified builds, because
+// they are the platform or come with their own keep rules.
+var unobfuscatedPackages = []string{"Landroid/", "Landroidx/", "Ljava/", "Ljavax/", "Lkotlin/", "Lkotlinx/", "Ldalvik/"}
+
+// dexClassNames returns the descriptors, e.g. "Lcom/example/Main;", of the
+// classes defined in a dex file.
+func dexClassNames(b []byte) ([]string, error) {
+	if len(b) < 0x70 || !bytes.HasPrefix(b, []byte("dex\n")) {
+		return nil, errDex
+	}
+	u32 := func(off uint32) (uint32, bool) {
+		if uint64(off)+4 > uint64(len(b)) {
+			return 0, false
+		}
+		return le.Uint32(b[off:]), true
+	}
+	stringIdsSize, stringIdsOff := le.Uint32(b[0x38:]), le.Uint32(b[0x3c:])
+	typeIdsSize, typeIdsOff := le.Uint32(b[0x40:]), le.Uint32(b[0x44:])
+	classDefsSize, classDefsOff := le.Uint32(b[0x60:]), le.Uint32(b[0x64:])
+
+	names := make([]string, 0, classDefsSize)
+	for i := uint32(0); i < classDefsSize; i++ {
+		typeIdx, ok := u32(classDefsOff + 32*i)
+		if !ok || typeIdx >= typeIdsSize {
+			return nil, errDex
+		}
+		stringIdx, ok := u32(typeIdsOff + 4*typeIdx)
+		if !ok || stringIdx >= stringIdsSize {
+			return nil, errDex
+		}
+		off, ok := u32(stringIdsOff + 4*stringIdx)
+		if !ok || off >= uint32(len(b)) {
+			return nil, errDex
+		}
+		// string_data_item: uleb128 length, then MUTF-8 bytes up to a NUL
+		_, n := binary.Uvarint(b[off:])
+		if n <= 0 {
+			return nil, errDex
+		}
+		s := b[off+uint32(n):]
+		end := bytes.IndexByte(s, 0)
+		if end < 0 {
+			return nil, errDex
+		}
+		names = append(names, string(s[:end]))
+	}
+	return names, nil
+}
+
+// countObfuscated returns how many of the app's classes among names look
+// renamed by R8 or ProGuard, i.e. have a simple name of one or two
+// letters, and how many were considered.
+func countObfuscated(names []string) (obfuscated, total int) {
+	for _, name := range names {
+		if !strings.HasPrefix(name, "L") || hasAnyPrefix(name, unobfuscatedPackages) {
+			continue
+		}
+		simple := strings.TrimSuffix(name[strings.LastIndex(name, "/")+1:], ";")
+		if i := strings.IndexByte(simple, '$'); i >= 0 {
+			simple = simple[:i]
+		}
+		if simple == "R" || simple == "BuildConfig" {
+			continue
+		}
+		total++
+		if len(simple) <= 2 {
+			obfuscated++
+		}
+	}
+	return obfuscated, total
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
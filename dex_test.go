@@ -0,0 +1,60 @@
+package appfile
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+// buildDex returns a dex file defining classes, with only the header
+// fields dexClassNames reads.
+func buildDex(classes ...string) []byte {
+	n := uint32(len(classes))
+	stringIdsOff := uint32(0x70)
+	typeIdsOff := stringIdsOff + 4*n
+	classDefsOff := typeIdsOff + 4*n
+	dataOff := classDefsOff + 32*n
+
+	b := make([]byte, dataOff)
+	copy(b, "dex\n035\x00")
+	binary.LittleEndian.PutUint32(b[0x38:], n)
+	binary.LittleEndian.PutUint32(b[0x3c:], stringIdsOff)
+	binary.LittleEndian.PutUint32(b[0x40:], n)
+	binary.LittleEndian.PutUint32(b[0x44:], typeIdsOff)
+	binary.LittleEndian.PutUint32(b[0x60:], n)
+	binary.LittleEndian.PutUint32(b[0x64:], classDefsOff)
+	for i, c := range classes {
+		binary.LittleEndian.PutUint32(b[stringIdsOff+4*uint32(i):], uint32(len(b)))
+		binary.LittleEndian.PutUint32(b[typeIdsOff+4*uint32(i):], uint32(i))
+		binary.LittleEndian.PutUint32(b[classDefsOff+32*uint32(i):], uint32(i))
+		b = append(b, byte(len(c)))
+		b = append(b, c...)
+		b = append(b, 0)
+	}
+	return b
+}
+
+func TestDexClassNames(t *testing.T) {
+	want := []string{"Lcom/example/MainActivity;", "La/b;"}
+	got, err := dexClassNames(buildDex(want...))
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, %v want %v", got, err, want)
+	}
+
+	if _, err := dexClassNames([]byte("dex\n035\x00")); err != errDex {
+		t.Errorf("got %v want %v", err, errDex)
+	}
+}
+
+func TestCountObfuscated(t *testing.T) {
+	o, n := countObfuscated([]string{
+		"Lcom/example/MainActivity;",
+		"Lcom/example/R$string;",
+		"Landroidx/appcompat/app/AppCompatActivity;",
+		"La/b;",
+		"La/aa$a;",
+	})
+	if o != 2 || n != 3 {
+		t.Errorf("got %v of %v want 2 of 3", o, n)
+	}
+}
@@ -0,0 +1,94 @@
+package appfile
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PermissionDiff lists the permissions and entitlements a build adds or
+// removes compared to a previous one. Entitlements are "key" for true
+// flags and "key=value" otherwise, so a changed value shows up as removed
+// and added.
+type PermissionDiff struct {
+	AddedPermissions    []string `json:"added_permissions"`
+	RemovedPermissions  []string `json:"removed_permissions"`
+	AddedEntitlements   []string `json:"added_entitlements"`
+	RemovedEntitlements []string `json:"removed_entitlements"`
+}
+
+// DiffPermissions compares the permissions and entitlements of prev and
+// next, usually two builds of one app.
+func DiffPermissions(prev, next *AppInfo) *PermissionDiff {
+	d := new(PermissionDiff)
+	d.AddedPermissions, d.RemovedPermissions = diffSets(permissionSet(prev), permissionSet(next))
+	d.AddedEntitlements, d.RemovedEntitlements = diffSets(entitlementSet(prev), entitlementSet(next))
+	return d
+}
+
+// Empty reports whether nothing was added or removed.
+func (d *PermissionDiff) Empty() bool {
+	return len(d.AddedPermissions)+len(d.RemovedPermissions)+len(d.AddedEntitlements)+len(d.RemovedEntitlements) == 0
+}
+
+func permissionSet(info *AppInfo) []string {
+	if info.Android == nil {
+		return nil
+	}
+	return info.Android.Permissions
+}
+
+func entitlementSet(info *AppInfo) []string {
+	if info.Ios == nil || info.Ios.Profile == nil {
+		return nil
+	}
+
+	var s []string
+	for k, v := range info.Ios.Profile.Entitlements {
+		switch v := v.(type) {
+		case bool:
+			if v {
+				s = append(s, k)
+			} else {
+				s = append(s, k+"=false")
+			}
+		case []interface{}:
+			vals := make([]string, 0, len(v))
+			for _, e := range v {
+				vals = append(vals, fmt.Sprint(e))
+			}
+			sort.Strings(vals)
+			s = append(s, k+"="+strings.Join(vals, ","))
+		default:
+			s = append(s, fmt.Sprintf("%s=%v", k, v))
+		}
+	}
+	return s
+}
+
+// diffSets returns the sorted elements only in next and only in prev.
+func diffSets(prev, next []string) (added, removed []string) {
+	in := func(s []string) map[string]bool {
+		m := make(map[string]bool, len(s))
+		for _, e := range s {
+			m[e] = true
+		}
+		return m
+	}
+	inPrev, inNext := in(prev), in(next)
+
+	added, removed = []string{}, []string{}
+	for e := range inNext {
+		if !inPrev[e] {
+			added = append(added, e)
+		}
+	}
+	for e := range inPrev {
+		if !inNext[e] {
+			removed = append(removed, e)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
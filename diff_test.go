@@ -0,0 +1,52 @@
+package appfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffPermissions(t *testing.T) {
+	prev := &AppInfo{Android: &AndroidInfo{Permissions: []string{"android.permission.INTERNET", "android.permission.READ_SMS"}}}
+	next := &AppInfo{Android: &AndroidInfo{Permissions: []string{"android.permission.CAMERA", "android.permission.INTERNET"}}}
+
+	d := DiffPermissions(prev, next)
+	want := &PermissionDiff{
+		AddedPermissions:    []string{"android.permission.CAMERA"},
+		RemovedPermissions:  []string{"android.permission.READ_SMS"},
+		AddedEntitlements:   []string{},
+		RemovedEntitlements: []string{},
+	}
+	if !reflect.DeepEqual(d, want) {
+		t.Errorf("got %+v want %+v", d, want)
+	}
+	if d.Empty() {
+		t.Errorf("got %v want %v", d.Empty(), false)
+	}
+	if d := DiffPermissions(next, next); !d.Empty() {
+		t.Errorf("got %+v want empty", d)
+	}
+}
+
+func TestDiffEntitlements(t *testing.T) {
+	profile := func(e map[string]interface{}) *AppInfo {
+		return &AppInfo{Ios: &IosInfo{Profile: &ProvisioningProfile{Entitlements: e}}}
+	}
+	prev := profile(map[string]interface{}{
+		"aps-environment":        "development",
+		"get-task-allow":         true,
+		"keychain-access-groups": []interface{}{"M8ZCXDJQW4.*"},
+	})
+	next := profile(map[string]interface{}{
+		"aps-environment":        "production",
+		"get-task-allow":         false,
+		"keychain-access-groups": []interface{}{"M8ZCXDJQW4.*"},
+	})
+
+	d := DiffPermissions(prev, next)
+	if want := []string{"aps-environment=production", "get-task-allow=false"}; !reflect.DeepEqual(d.AddedEntitlements, want) {
+		t.Errorf("got %v want %v", d.AddedEntitlements, want)
+	}
+	if want := []string{"aps-environment=development", "get-task-allow"}; !reflect.DeepEqual(d.RemovedEntitlements, want) {
+		t.Errorf("got %v want %v", d.RemovedEntitlements, want)
+	}
+}
@@ -0,0 +1,86 @@
+package appfile
+
+import (
+	"archive/zip"
+	"bytes"
+	"debug/elf"
+	"path"
+	"strings"
+)
+
+// pageSize16K is the page size of Android 15 devices that need native
+// libraries aligned to 16 KB.
+const pageSize16K = 16 << 10
+
+// parseApkNativeLibs inspects the lib/<abi>/*.so files of an APK. Files
+// that are not ELF are skipped.
+func parseApkNativeLibs(files []*zip.File) ([]NativeLib, error) {
+	var libs []NativeLib
+	for _, f := range files {
+		parts := strings.Split(f.Name, "/")
+		if len(parts) != 3 || parts[0] != "lib" || path.Ext(parts[2]) != ".so" {
+			continue
+		}
+		buf, err := readZipFile(f)
+		if err != nil {
+			return nil, err
+		}
+		lib, err := parseELF(buf)
+		if err != nil {
+			continue
+		}
+		lib.Path = f.Name
+		lib.ABI = parts[1]
+		lib.Size = int64(len(buf))
+		lib.Aligned16K = lib.Align >= pageSize16K
+		// Uncompressed libraries are mapped straight from the APK, so
+		// their data has to start on a page boundary too.
+		if f.Method == zip.Store {
+			lib.Stored = true
+			offset, err := f.DataOffset()
+			lib.Aligned16K = lib.Aligned16K && err == nil && offset%pageSize16K == 0
+		}
+		libs = append(libs, *lib)
+	}
+	return libs, nil
+}
+
+// parseELF reads the dynamic section and the smallest alignment of the
+// loadable segments. A library is stripped when it has neither a symbol
+// table nor debug info; the dynamic symbols are always kept.
+func parseELF(buf []byte) (*NativeLib, error) {
+	f, err := elf.NewFile(bytes.NewReader(buf))
+	if err != nil {
+		return nil, err
+	}
+	lib := &NativeLib{
+		Is64Bit:  f.Class == elf.ELFCLASS64,
+		Stripped: f.Section(".symtab") == nil && f.Section(".debug_info") == nil,
+	}
+	if soname, err := f.DynString(elf.DT_SONAME); err == nil && len(soname) > 0 {
+		lib.Soname = soname[0]
+	}
+	if needed, err := f.DynString(elf.DT_NEEDED); err == nil && len(needed) > 0 {
+		lib.Needed = needed
+	}
+	for _, p := range f.Progs {
+		if p.Type != elf.PT_LOAD {
+			continue
+		}
+		if lib.Align == 0 || p.Align < lib.Align {
+			lib.Align = p.Align
+		}
+	}
+	return lib, nil
+}
+
+// pageSize16KReady reports whether all 64-bit libraries are 16 KB aligned.
+// 32-bit ABIs never run with 16 KB pages.
+func pageSize16KReady(libs []NativeLib) bool {
+	for _, l := range libs {
+		if l.Is64Bit && !l.Aligned16K {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,123 @@
+package appfile
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+// elfLib returns a 64-bit little-endian shared object with one PT_LOAD
+// segment per alignment.
+func elfLib(aligns ...uint64) []byte {
+	return elfFile(aligns, "", nil)
+}
+
+// elfFile returns a shared object whose .dynamic section has the soname,
+// when set, and needed libraries.
+func elfFile(aligns []uint64, soname string, needed []string) []byte {
+	le := binary.LittleEndian
+	dynstr := []byte{0}
+	var dyn []uint64
+	addString := func(tag uint64, s string) {
+		dyn = append(dyn, tag, uint64(len(dynstr)))
+		dynstr = append(append(dynstr, s...), 0)
+	}
+	if soname != "" {
+		addString(14, soname) // DT_SONAME
+	}
+	for _, n := range needed {
+		addString(1, n) // DT_NEEDED
+	}
+	dyn = append(dyn, 0, 0) // DT_NULL
+	shstrtab := []byte("\x00.dynstr\x00.dynamic\x00.shstrtab\x00")
+
+	dynstrOff := uint64(64 + 56*len(aligns))
+	dynOff := dynstrOff + uint64(len(dynstr))
+	shstrOff := dynOff + uint64(8*len(dyn))
+	shOff := shstrOff + uint64(len(shstrtab))
+
+	var buf bytes.Buffer
+	buf.Write([]byte{0x7f, 'E', 'L', 'F', 2, 1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0})
+	binary.Write(&buf, le, uint16(3))   // ET_DYN
+	binary.Write(&buf, le, uint16(183)) // EM_AARCH64
+	binary.Write(&buf, le, uint32(1))
+	binary.Write(&buf, le, []uint64{0, 64, shOff}) // entry, phoff, shoff
+	binary.Write(&buf, le, uint32(0))
+	binary.Write(&buf, le, []uint16{64, 56, uint16(len(aligns)), 64, 4, 3})
+	for _, a := range aligns {
+		binary.Write(&buf, le, []uint32{1, 5}) // PT_LOAD, R+X
+		binary.Write(&buf, le, []uint64{0, 0, 0, 0, 0, a})
+	}
+	buf.Write(dynstr)
+	binary.Write(&buf, le, dyn)
+	buf.Write(shstrtab)
+
+	section := func(name, typ uint32, off, size uint64, link uint32, entsize uint64) {
+		binary.Write(&buf, le, []uint32{name, typ})
+		binary.Write(&buf, le, []uint64{0, 0, off, size})
+		binary.Write(&buf, le, []uint32{link, 0})
+		binary.Write(&buf, le, []uint64{1, entsize})
+	}
+	section(0, 0, 0, 0, 0, 0)
+	section(1, 3, dynstrOff, uint64(len(dynstr)), 0, 0)   // SHT_STRTAB
+	section(9, 6, dynOff, uint64(8*len(dyn)), 1, 16)      // SHT_DYNAMIC
+	section(18, 3, shstrOff, uint64(len(shstrtab)), 0, 0) // SHT_STRTAB
+	return buf.Bytes()
+}
+
+func TestParseELF(t *testing.T) {
+	lib, err := parseELF(elfFile([]uint64{4096}, "libfoo.so", []string{"liblog.so", "libc.so"}))
+	if err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	want := &NativeLib{Soname: "libfoo.so", Needed: []string{"liblog.so", "libc.so"}, Stripped: true, Is64Bit: true, Align: 4096}
+	if !reflect.DeepEqual(lib, want) {
+		t.Errorf("got %+v want %+v", lib, want)
+	}
+}
+
+func TestParseApkNativeLibs(t *testing.T) {
+	ok, old, stored := elfLib(16384, 65536), elfLib(4096, 16384), elfLib(16384)
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, e := range []struct {
+		name   string
+		method uint16
+		lib    []byte
+	}{
+		{"lib/arm64-v8a/libok.so", zip.Deflate, ok},
+		{"lib/x86_64/libold.so", zip.Deflate, old},
+		{"lib/arm64-v8a/libstored.so", zip.Store, stored},
+		{"lib/arm64-v8a/libtext.so", zip.Deflate, []byte("not elf")},
+		{"assets/lib.so", zip.Deflate, elfLib(4096)},
+	} {
+		f, _ := w.CreateHeader(&zip.FileHeader{Name: e.name, Method: e.method})
+		f.Write(e.lib)
+	}
+	w.Close()
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	libs, err := parseApkNativeLibs(reader.File)
+	if err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	want := []NativeLib{
+		{Path: "lib/arm64-v8a/libok.so", ABI: "arm64-v8a", Size: int64(len(ok)), Stripped: true, Is64Bit: true, Align: 16384, Aligned16K: true},
+		{Path: "lib/x86_64/libold.so", ABI: "x86_64", Size: int64(len(old)), Stripped: true, Is64Bit: true, Align: 4096},
+		{Path: "lib/arm64-v8a/libstored.so", ABI: "arm64-v8a", Size: int64(len(stored)), Stripped: true, Is64Bit: true, Align: 16384, Stored: true},
+	}
+	if !reflect.DeepEqual(libs, want) {
+		t.Errorf("got %+v want %+v", libs, want)
+	}
+	if pageSize16KReady(libs) {
+		t.Errorf("got ready want not ready")
+	}
+	if !pageSize16KReady([]NativeLib{want[0], {ABI: "armeabi-v7a", Align: 4096}}) {
+		t.Errorf("got not ready want ready")
+	}
+}
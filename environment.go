@@ -0,0 +1,63 @@
+package appfile
+
+import "strings"
+
+// Build environments, from least to most release-ready.
+const (
+	EnvDebug      = "debug"
+	EnvStaging    = "staging"
+	EnvProduction = "production"
+)
+
+var (
+	debugIdSuffixes   = []string{".debug", ".dev"}
+	stagingIdSuffixes = []string{".beta", ".staging", ".qa", ".alpha", ".internal", ".test"}
+)
+
+// BuildEnvironment classifies the build as EnvDebug, EnvStaging or
+// EnvProduction. Debuggable builds and development signing are debug;
+// ad-hoc and enterprise signing, the development APNs environment and
+// application id suffixes such as .beta are staging; everything else,
+// including App Store signed builds, is production.
+func (info *AppInfo) BuildEnvironment() string {
+	debug, staging := false, false
+	switch {
+	case hasAnySuffix(info.BundleId, debugIdSuffixes):
+		debug = true
+	case hasAnySuffix(info.BundleId, stagingIdSuffixes):
+		staging = true
+	}
+
+	if info.Android != nil && info.Android.Debug {
+		debug = true
+	}
+	if info.Ios != nil && info.Ios.Profile != nil {
+		p := info.Ios.Profile
+		if allow, _ := p.Entitlements["get-task-allow"].(bool); allow || p.SigningType == "development" {
+			debug = true
+		}
+		if p.SigningType == "ad-hoc" || p.SigningType == "enterprise" {
+			staging = true
+		}
+		if aps, _ := p.Entitlements["aps-environment"].(string); aps == "development" {
+			staging = true
+		}
+	}
+
+	switch {
+	case debug:
+		return EnvDebug
+	case staging:
+		return EnvStaging
+	}
+	return EnvProduction
+}
+
+func hasAnySuffix(s string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package appfile
+
+import "testing"
+
+func TestBuildEnvironment(t *testing.T) {
+	for _, tt := range []struct {
+		info *AppInfo
+		want string
+	}{
+		{&AppInfo{BundleId: "com.example", Android: &AndroidInfo{}}, EnvProduction},
+		{&AppInfo{BundleId: "com.example", Android: &AndroidInfo{Debug: true}}, EnvDebug},
+		{&AppInfo{BundleId: "com.example.beta", Android: &AndroidInfo{}}, EnvStaging},
+		{&AppInfo{BundleId: "com.example.debug", Android: &AndroidInfo{}}, EnvDebug},
+		{&AppInfo{BundleId: "com.example", Ios: &IosInfo{Profile: &ProvisioningProfile{SigningType: "app-store"}}}, EnvProduction},
+		{&AppInfo{BundleId: "com.example", Ios: &IosInfo{Profile: &ProvisioningProfile{SigningType: "ad-hoc"}}}, EnvStaging},
+		{&AppInfo{BundleId: "com.example", Ios: &IosInfo{Profile: &ProvisioningProfile{
+			SigningType:  "app-store",
+			Entitlements: map[string]interface{}{"aps-environment": "development"},
+		}}}, EnvStaging},
+		{&AppInfo{BundleId: "com.example.beta", Ios: &IosInfo{Profile: &ProvisioningProfile{
+			SigningType:  "ad-hoc",
+			Entitlements: map[string]interface{}{"get-task-allow": true},
+		}}}, EnvDebug},
+	} {
+		if got := tt.info.BuildEnvironment(); got != tt.want {
+			t.Errorf("%v: got %v want %v", tt.info.BundleId, got, tt.want)
+		}
+	}
+}
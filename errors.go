@@ -0,0 +1,52 @@
+package appfile
+
+import "strings"
+
+// StageError is the failure of one parse stage, such as StageIcon.
+type StageError struct {
+	Stage string
+	Err   error
+}
+
+func (e *StageError) Error() string { return e.Stage + ": " + e.Err.Error() }
+func (e *StageError) Unwrap() error { return e.Err }
+
+// ParseError lists every stage that failed. NewAppParser returns it along
+// with the AppInfo when the artifact could be read in part, e.g. when only
+// the icon failed, and without one otherwise. errors.Is and errors.As
+// match any of the stage errors.
+type ParseError struct {
+	Errors []*StageError
+}
+
+func (e *ParseError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e *ParseError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// stageErrors collects the errors of a parse.
+type stageErrors []*StageError
+
+func (s *stageErrors) add(stage string, err error) {
+	if err != nil {
+		*s = append(*s, &StageError{Stage: stage, Err: err})
+	}
+}
+
+func (s stageErrors) err() error {
+	if len(s) == 0 {
+		return nil
+	}
+	return &ParseError{Errors: s}
+}
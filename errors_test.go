@@ -0,0 +1,131 @@
+package appfile
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/follyxing/appfile-info/fixture"
+)
+
+func TestParseApkWithoutManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "appfile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "broken.apk")
+	writeZip(t, name, map[string][]byte{"classes.dex": []byte("dex\n035\x00")})
+
+	info, err := NewAppParser(name)
+	if info != nil {
+		t.Errorf("got %+v want nil", info)
+	}
+	var perr *ParseError
+	if !errors.As(err, &perr) || len(perr.Errors) != 1 || perr.Errors[0].Stage != StageManifest {
+		t.Fatalf("got %v want a %v ParseError", err, StageManifest)
+	}
+	if got := err.Error(); got != "manifest_decode: AndroidManifest.xml not found" {
+		t.Errorf("got %v want %v", got, "manifest_decode: AndroidManifest.xml not found")
+	}
+}
+
+func TestParseApkCollectsBinaryErrors(t *testing.T) {
+	data, err := (&fixture.APK{Package: "com.example.dex", Files: map[string][]byte{
+		"classes.dex":             buildDex("Lcom/example/Main;"),
+		"lib/arm64-v8a/libapp.so": elfLib(0x4000),
+	}}).Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Break the CRC of classes.dex in the central directory.
+	for i := 0; ; i++ {
+		j := bytes.Index(data[i:], []byte("PK\x01\x02"))
+		if j < 0 {
+			t.Fatal("classes.dex not found")
+		}
+		i += j
+		if bytes.HasPrefix(data[i+46:], []byte("classes.dex")) {
+			data[i+16] ^= 0xff
+			break
+		}
+	}
+
+	info, err := NewAppParser(writeFile(t, "baddex.apk", data))
+	if info == nil || len(info.Android.NativeLibs) != 1 {
+		t.Fatalf("got %v want the native library despite the bad dex", info)
+	}
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Errors[0].Stage != StageBinary {
+		t.Errorf("got %v want a %v ParseError", err, StageBinary)
+	}
+}
+
+func TestParseIpaCollectsErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "appfile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "broken.ipa")
+	writeZip(t, name, map[string][]byte{"Payload/App.app/App": []byte("\xcf\xfa\xed\xfe")})
+
+	info, err := NewAppParser(name)
+	if info != nil {
+		t.Errorf("got %+v want nil", info)
+	}
+	want := "manifest_decode: info.plist not found; profile_decode: profile not found; icon_decode: icon not found"
+	if err == nil || err.Error() != want {
+		t.Errorf("got %v want %v", err, want)
+	}
+	if !errors.Is(err, ErrNoIcon) {
+		t.Errorf("got %v want %v", err, ErrNoIcon)
+	}
+}
+
+func TestParseErrorIs(t *testing.T) {
+	var errs stageErrors
+	if errs.err() != nil {
+		t.Errorf("got %v want nil", errs.err())
+	}
+	errs.add(StageManifest, nil)
+	errs.add(StageIcon, ErrNoIcon)
+	err := errs.err()
+	if !errors.Is(err, ErrNoIcon) {
+		t.Errorf("got %v want %v", err, ErrNoIcon)
+	}
+	var serr *StageError
+	if !errors.As(err, &serr) || serr.Stage != StageIcon {
+		t.Errorf("got %v want a %v StageError", err, StageIcon)
+	}
+}
+
+func TestParseErrorEntry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "appfile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "broken.apk")
+	writeZip(t, name, map[string][]byte{"AndroidManifest.xml": []byte("\x03\x00\x08\x00\xff\xff\x00\x00")})
+
+	_, err = NewAppParser(name)
+	want := "manifest_decode: decoding AndroidManifest.xml: malformed data: binary xml chunk sizes"
+	if err == nil || err.Error() != want {
+		t.Errorf("got %v want %v", err, want)
+	}
+	if !errors.Is(err, ErrMalformed) {
+		t.Errorf("got %v want %v", err, ErrMalformed)
+	}
+
+	name = filepath.Join(dir, "broken.ipa")
+	writeZip(t, name, map[string][]byte{"Payload/App.app/Info.plist": []byte("<plist><dict><key>")})
+	_, err = NewAppParser(name)
+	if err == nil || !strings.Contains(err.Error(), "manifest_decode: decoding Payload/App.app/Info.plist: ") {
+		t.Errorf("got %v want the Info.plist entry", err)
+	}
+}
@@ -0,0 +1,35 @@
+package appfile
+
+import (
+	"archive/zip"
+	"io"
+)
+
+// checkExecutable looks up the CFBundleExecutable of an IPA and warns if
+// it is not a Mach-O binary in the .app, as in IPAs repackaged by hand,
+// which fail to install or launch.
+func checkExecutable(info *AppInfo, files []*zip.File) {
+	if info.Ios.Executable == "" {
+		info.warn("Info.plist has no CFBundleExecutable")
+		return
+	}
+	f := findIpaAppFile(files, info.Ios.Executable)
+	if f == nil {
+		info.warn("CFBundleExecutable %s is not in the app", info.Ios.Executable)
+		return
+	}
+	info.Ios.ExecutableSize = int64(f.UncompressedSize64)
+	rc, err := f.Open()
+	if err != nil {
+		info.warn("CFBundleExecutable %s: %v", info.Ios.Executable, err)
+		return
+	}
+	defer rc.Close()
+	magic := make([]byte, 4)
+	io.ReadFull(rc, magic)
+	if !isMachO(magic) {
+		info.warn("CFBundleExecutable %s is not a Mach-O binary", info.Ios.Executable)
+		return
+	}
+	info.Ios.ExecutableFound = true
+}
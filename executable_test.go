@@ -0,0 +1,43 @@
+package appfile
+
+import (
+	"archive/zip"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckExecutable(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.ipa")
+	bin := thinMachO(0x0100000c, 2)
+	writeZip(t, name, map[string][]byte{
+		"Payload/App.app/Info.plist": []byte("plist"),
+		"Payload/App.app/App":        bin,
+		"Payload/App.app/Script":     []byte("#!/bin/sh"),
+	})
+	r, err := zip.OpenReader(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	for _, tt := range []struct {
+		executable string
+		size       int64
+		found      bool
+	}{
+		{"App", int64(len(bin)), true},
+		{"Script", 9, false},
+		{"Missing", 0, false},
+		{"", 0, false},
+	} {
+		info := newAppInfo(PlatformIOS)
+		info.Ios.Executable = tt.executable
+		checkExecutable(info, r.File)
+		if info.Ios.ExecutableSize != tt.size || info.Ios.ExecutableFound != tt.found {
+			t.Errorf("%q: got %d %v want %d %v", tt.executable, info.Ios.ExecutableSize, info.Ios.ExecutableFound, tt.size, tt.found)
+		}
+		if got := len(info.Warnings) > 0; got == tt.found {
+			t.Errorf("%q: got warnings %v", tt.executable, info.Warnings)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package appfile
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// foregroundServiceTypes are the android:foregroundServiceType flags in
+// the order of their bits, see ServiceInfo.FOREGROUND_SERVICE_TYPE_*.
+var foregroundServiceTypes = []struct {
+	bit  uint64
+	name string
+}{
+	{1 << 0, "dataSync"},
+	{1 << 1, "mediaPlayback"},
+	{1 << 2, "phoneCall"},
+	{1 << 3, "location"},
+	{1 << 4, "connectedDevice"},
+	{1 << 5, "mediaProjection"},
+	{1 << 6, "camera"},
+	{1 << 7, "microphone"},
+	{1 << 8, "health"},
+	{1 << 9, "remoteMessaging"},
+	{1 << 10, "systemExempted"},
+	{1 << 11, "shortService"},
+	{1 << 12, "fileManagement"},
+	{1 << 13, "mediaProcessing"},
+	{1 << 30, "specialUse"},
+}
+
+// parseForegroundServiceType returns the type names of v, which is a
+// "location|camera" list in plain and proto XML and an integer in binary
+// XML.
+func parseForegroundServiceType(v string) []string {
+	if v == "" {
+		return nil
+	}
+	n, err := strconv.ParseUint(v, 0, 32)
+	if err != nil {
+		return strings.Split(v, "|")
+	}
+	var types []string
+	for _, t := range foregroundServiceTypes {
+		if n&t.bit != 0 {
+			types = append(types, t.name)
+		}
+	}
+	return types
+}
+
+// foregroundServicePermission returns the permission apps targeting
+// Android 14 need to start a foreground service of type t, e.g.
+// android.permission.FOREGROUND_SERVICE_DATA_SYNC. Short services need
+// none.
+func foregroundServicePermission(t string) string {
+	if t == "shortService" {
+		return ""
+	}
+	var b strings.Builder
+	for i, r := range t {
+		if unicode.IsUpper(r) && i > 0 {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return "android.permission.FOREGROUND_SERVICE_" + b.String()
+}
+
+// parseForegroundServices lists the services declaring a
+// foregroundServiceType and warns about types whose permission is missing
+// from apps targeting Android 14, which Google Play rejects.
+func parseForegroundServices(manifest *androidManifest, info *AppInfo) {
+	permissions := make(map[string]bool)
+	for _, p := range info.Android.Permissions {
+		permissions[p] = true
+	}
+	target, _ := strconv.Atoi(info.Android.TargetSdkVersion)
+	for _, s := range manifest.Application.Services {
+		types := parseForegroundServiceType(s.ForegroundServiceType)
+		if types == nil {
+			continue
+		}
+		name := manifest.className(s.Name)
+		info.Android.ForegroundServices = append(info.Android.ForegroundServices, ForegroundService{Name: name, Types: types})
+		if target < 34 {
+			continue
+		}
+		for _, t := range types {
+			if p := foregroundServicePermission(t); p != "" && !permissions[p] {
+				info.warn("foreground service %s of type %s requires the %s permission", name, t, p)
+			}
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package appfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseForegroundServiceType(t *testing.T) {
+	for v, want := range map[string][]string{
+		"location|camera": {"location", "camera"},
+		"0x48":            {"location", "camera"},
+		"1073741825":      {"dataSync", "specialUse"},
+		"":                nil,
+	} {
+		if got := parseForegroundServiceType(v); !reflect.DeepEqual(got, want) {
+			t.Errorf("%q: got %v want %v", v, got, want)
+		}
+	}
+	if got := foregroundServicePermission("dataSync"); got != "android.permission.FOREGROUND_SERVICE_DATA_SYNC" {
+		t.Errorf("got %v want %v", got, "android.permission.FOREGROUND_SERVICE_DATA_SYNC")
+	}
+}
+
+func TestParseForegroundServices(t *testing.T) {
+	manifest := new(androidManifest)
+	manifest.Package = "com.example"
+	manifest.Application.Services = []androidComponent{
+		{Name: ".Tracker", ForegroundServiceType: "location|shortService"},
+		{Name: ".Sync", ForegroundServiceType: "dataSync"},
+		{Name: ".Plain"},
+	}
+	info := newAppInfo(PlatformAndroid)
+	info.Android.TargetSdkVersion = "34"
+	info.Android.Permissions = []string{"android.permission.FOREGROUND_SERVICE_LOCATION"}
+
+	parseForegroundServices(manifest, info)
+	want := []ForegroundService{
+		{Name: "com.example.Tracker", Types: []string{"location", "shortService"}},
+		{Name: "com.example.Sync", Types: []string{"dataSync"}},
+	}
+	if !reflect.DeepEqual(info.Android.ForegroundServices, want) {
+		t.Errorf("got %+v want %+v", info.Android.ForegroundServices, want)
+	}
+	if len(info.Warnings) != 1 {
+		t.Errorf("got %v want a dataSync permission warning", info.Warnings)
+	}
+}
@@ -0,0 +1,153 @@
+// Package fixture builds tiny synthetic .apk and .ipa files, so parser
+// tests can describe the app they need instead of committing real builds.
+// The output of a spec is the same on every run.
+package fixture
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"sort"
+)
+
+// APK describes an .apk with a binary AndroidManifest.xml. Zero fields are
+// left out of the manifest.
+type APK struct {
+	Package     string
+	VersionCode int
+	VersionName string
+	Label       string
+	MinSdk      int
+	TargetSdk   int
+	Debuggable  bool
+	Permissions []string
+	// Application holds the children of <application>, such as activities.
+	Application []*Element
+	// Files are added as they are, e.g. "classes.dex" or "lib/arm64-v8a/libx.so".
+	Files map[string][]byte
+}
+
+// Manifest returns the AndroidManifest.xml of a.
+func (a *APK) Manifest() *Element {
+	manifest := &Element{Name: "manifest", Attrs: []Attr{{Name: "package", Value: a.Package}}}
+	if a.VersionCode != 0 {
+		manifest.Attrs = append(manifest.Attrs, Attr{Android: true, Name: "versionCode", Value: a.VersionCode})
+	}
+	if a.VersionName != "" {
+		manifest.Attrs = append(manifest.Attrs, Attr{Android: true, Name: "versionName", Value: a.VersionName})
+	}
+
+	if a.MinSdk != 0 || a.TargetSdk != 0 {
+		sdk := &Element{Name: "uses-sdk"}
+		if a.MinSdk != 0 {
+			sdk.Attrs = append(sdk.Attrs, Attr{Android: true, Name: "minSdkVersion", Value: a.MinSdk})
+		}
+		if a.TargetSdk != 0 {
+			sdk.Attrs = append(sdk.Attrs, Attr{Android: true, Name: "targetSdkVersion", Value: a.TargetSdk})
+		}
+		manifest.Children = append(manifest.Children, sdk)
+	}
+	for _, p := range a.Permissions {
+		manifest.Children = append(manifest.Children, &Element{
+			Name:  "uses-permission",
+			Attrs: []Attr{{Android: true, Name: "name", Value: p}},
+		})
+	}
+
+	app := &Element{Name: "application", Children: a.Application}
+	if a.Label != "" {
+		app.Attrs = append(app.Attrs, Attr{Android: true, Name: "label", Value: a.Label})
+	}
+	if a.Debuggable {
+		app.Attrs = append(app.Attrs, Attr{Android: true, Name: "debuggable", Value: true})
+	}
+	manifest.Children = append(manifest.Children, app)
+	return manifest
+}
+
+// Bytes returns the .apk.
+func (a *APK) Bytes() ([]byte, error) {
+	files := map[string][]byte{"AndroidManifest.xml": AXML(a.Manifest())}
+	for name, data := range a.Files {
+		files[name] = data
+	}
+	return Zip(files)
+}
+
+// Activity returns an <activity> named name, with a launcher intent filter
+// if main is set.
+func Activity(name string, main bool) *Element {
+	activity := &Element{Name: "activity", Attrs: []Attr{{Android: true, Name: "name", Value: name}}}
+	if main {
+		activity.Children = []*Element{{
+			Name: "intent-filter",
+			Children: []*Element{
+				{Name: "action", Attrs: []Attr{{Android: true, Name: "name", Value: "android.intent.action.MAIN"}}},
+				{Name: "category", Attrs: []Attr{{Android: true, Name: "name", Value: "android.intent.category.LAUNCHER"}}},
+			},
+		}}
+	}
+	return activity
+}
+
+// Zip returns an archive of files in name order. Entries are stored
+// uncompressed and undated so the output does not depend on the flate
+// implementation or the clock.
+func Zip(files map[string][]byte) ([]byte, error) {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, name := range names {
+		fw, err := w.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := fw.Write(files[name]); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SigningBlockPair is an ID-value pair of an APK Signing Block.
+type SigningBlockPair struct {
+	ID    uint32
+	Value []byte
+}
+
+// AddSigningBlock inserts an APK Signing Block with pairs before the
+// central directory of the archive apk, as apksigner does, and moves the
+// central directory offset of the end record accordingly.
+func AddSigningBlock(apk []byte, pairs []SigningBlockPair) ([]byte, error) {
+	end := bytes.LastIndex(apk, []byte("PK\x05\x06"))
+	if end < 0 || len(apk)-end < 22 {
+		return nil, errors.New("fixture: no zip end record")
+	}
+	dirOffset := binary.LittleEndian.Uint32(apk[end+16:])
+
+	var pairData bytes.Buffer
+	for _, p := range pairs {
+		binary.Write(&pairData, binary.LittleEndian, uint64(4+len(p.Value)))
+		binary.Write(&pairData, binary.LittleEndian, p.ID)
+		pairData.Write(p.Value)
+	}
+	blockSize := uint64(pairData.Len() + 24)
+	var block bytes.Buffer
+	binary.Write(&block, binary.LittleEndian, blockSize)
+	block.Write(pairData.Bytes())
+	binary.Write(&block, binary.LittleEndian, blockSize)
+	block.WriteString("APK Sig Block 42")
+
+	out := append(append(append([]byte(nil), apk[:dirOffset]...), block.Bytes()...), apk[dirOffset:]...)
+	binary.LittleEndian.PutUint32(out[end+block.Len()+16:], dirOffset+uint32(block.Len()))
+	return out, nil
+}
@@ -0,0 +1,208 @@
+package fixture
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+	"unicode/utf16"
+)
+
+const androidNS = "http://schemas.android.com/apk/res/android"
+
+// androidAttrs are the resource IDs of the android: attributes, which aapt
+// records in the resource map so decoders can tell them apart from
+// same-named attributes of other namespaces.
+var androidAttrs = map[string]uint32{
+	"label":            0x01010001,
+	"icon":             0x01010002,
+	"name":             0x01010003,
+	"permission":       0x01010006,
+	"debuggable":       0x0101000f,
+	"exported":         0x01010010,
+	"value":            0x01010024,
+	"minSdkVersion":    0x0101020c,
+	"versionCode":      0x0101021b,
+	"versionName":      0x0101021c,
+	"targetSdkVersion": 0x01010270,
+	"required":         0x0101028e,
+}
+
+const (
+	resStringPool   = 0x0001
+	resXML          = 0x0003
+	resXMLNSStart   = 0x0100
+	resXMLNSEnd     = 0x0101
+	resXMLElemStart = 0x0102
+	resXMLElemEnd   = 0x0103
+	resXMLResMap    = 0x0180
+
+	typeReference = 0x01
+	typeString    = 0x03
+	typeInt       = 0x10
+	typeBool      = 0x12
+
+	noIndex = 0xffffffff
+)
+
+// Element is an element of an Android binary XML file.
+type Element struct {
+	Name     string
+	Attrs    []Attr
+	Children []*Element
+}
+
+// Attr is an attribute of an Element. Value is a string, an int, a bool or
+// a Ref.
+type Attr struct {
+	Android bool // in the android: namespace
+	Name    string
+	Value   interface{}
+}
+
+// Ref is a resource reference attribute value, such as @string/app_name.
+type Ref uint32
+
+// AXML encodes root as aapt compiles AndroidManifest.xml, declaring the
+// android: namespace on it.
+func AXML(root *Element) []byte {
+	e := &axmlEncoder{index: make(map[string]uint32)}
+	e.collect(root)
+
+	var body bytes.Buffer
+	nsPrefix, nsURI := e.index["android"], e.index[androidNS]
+	writeChunk(&body, resXMLNSStart, 16, func(b *bytes.Buffer) {
+		put32(b, 1, noIndex, nsPrefix, nsURI)
+	})
+	e.element(&body, root)
+	writeChunk(&body, resXMLNSEnd, 16, func(b *bytes.Buffer) {
+		put32(b, 1, noIndex, nsPrefix, nsURI)
+	})
+
+	var out bytes.Buffer
+	writeChunk(&out, resXML, 8, func(b *bytes.Buffer) {
+		e.stringPool(b)
+		writeChunk(b, resXMLResMap, 8, func(b *bytes.Buffer) {
+			for _, s := range e.strings[:e.mapped] {
+				put32(b, androidAttrs[s])
+			}
+		})
+		b.Write(body.Bytes())
+	})
+	return out.Bytes()
+}
+
+type axmlEncoder struct {
+	strings []string
+	index   map[string]uint32
+	mapped  int // the leading strings that are android: attribute names
+}
+
+// collect builds the string pool, with the android: attribute names first
+// as the resource map requires.
+func (e *axmlEncoder) collect(root *Element) {
+	var attrs, others []string
+	seen := make(map[string]bool)
+	var walk func(el *Element)
+	walk = func(el *Element) {
+		others = append(others, el.Name)
+		for _, a := range el.Attrs {
+			if _, ok := androidAttrs[a.Name]; a.Android && ok && !seen[a.Name] {
+				seen[a.Name] = true
+				attrs = append(attrs, a.Name)
+			} else {
+				others = append(others, a.Name)
+			}
+			if s, ok := a.Value.(string); ok {
+				others = append(others, s)
+			}
+		}
+		for _, c := range el.Children {
+			walk(c)
+		}
+	}
+	walk(root)
+	sort.Strings(attrs)
+	e.mapped = len(attrs)
+	for _, s := range append(append(attrs, "android", androidNS), others...) {
+		if _, ok := e.index[s]; !ok {
+			e.index[s] = uint32(len(e.strings))
+			e.strings = append(e.strings, s)
+		}
+	}
+}
+
+func (e *axmlEncoder) stringPool(b *bytes.Buffer) {
+	var data bytes.Buffer
+	offsets := make([]uint32, len(e.strings))
+	for i, s := range e.strings {
+		offsets[i] = uint32(data.Len())
+		units := utf16.Encode([]rune(s))
+		binary.Write(&data, binary.LittleEndian, uint16(len(units)))
+		binary.Write(&data, binary.LittleEndian, units)
+		binary.Write(&data, binary.LittleEndian, uint16(0))
+	}
+	for data.Len()%4 != 0 {
+		data.WriteByte(0)
+	}
+	writeChunk(b, resStringPool, 28, func(b *bytes.Buffer) {
+		put32(b, uint32(len(e.strings)), 0, 0, uint32(28+4*len(e.strings)), 0)
+		put32(b, offsets...)
+		b.Write(data.Bytes())
+	})
+}
+
+func (e *axmlEncoder) element(b *bytes.Buffer, el *Element) {
+	writeChunk(b, resXMLElemStart, 16, func(b *bytes.Buffer) {
+		put32(b, 1, noIndex, noIndex, e.index[el.Name])
+		put16(b, 20, 20, uint16(len(el.Attrs)), 0, 0, 0)
+		for _, a := range el.Attrs {
+			attrNS := uint32(noIndex)
+			if a.Android {
+				attrNS = e.index[androidNS]
+			}
+			raw, typ, data := uint32(noIndex), uint8(typeString), uint32(0)
+			switch v := a.Value.(type) {
+			case string:
+				raw, data = e.index[v], e.index[v]
+			case int:
+				typ, data = typeInt, uint32(v)
+			case bool:
+				typ = typeBool
+				if v {
+					data = noIndex
+				}
+			case Ref:
+				typ, data = typeReference, uint32(v)
+			}
+			put32(b, attrNS, e.index[a.Name], raw)
+			put16(b, 8)
+			b.WriteByte(0)
+			b.WriteByte(typ)
+			put32(b, data)
+		}
+	})
+	for _, c := range el.Children {
+		e.element(b, c)
+	}
+	writeChunk(b, resXMLElemEnd, 16, func(b *bytes.Buffer) {
+		put32(b, 1, noIndex, noIndex, e.index[el.Name])
+	})
+}
+
+// writeChunk writes a chunk of type typ; fill writes the header fields past
+// the type and sizes, then the body.
+func writeChunk(b *bytes.Buffer, typ uint16, headerSize uint16, fill func(*bytes.Buffer)) {
+	var body bytes.Buffer
+	fill(&body)
+	put16(b, typ, headerSize)
+	put32(b, uint32(8+body.Len()))
+	b.Write(body.Bytes())
+}
+
+func put16(b *bytes.Buffer, v ...uint16) {
+	binary.Write(b, binary.LittleEndian, v)
+}
+
+func put32(b *bytes.Buffer, v ...uint32) {
+	binary.Write(b, binary.LittleEndian, v)
+}
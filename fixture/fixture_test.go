@@ -0,0 +1,101 @@
+package fixture
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/asn1"
+	"strings"
+	"testing"
+
+	"github.com/shogo82148/androidbinary"
+)
+
+func TestAXML(t *testing.T) {
+	apk := &APK{
+		Package:     "com.example.fixture",
+		VersionCode: 3,
+		VersionName: "1.2",
+		Label:       "Fixture",
+		MinSdk:      21,
+		Debuggable:  true,
+		Permissions: []string{"android.permission.INTERNET"},
+		Application: []*Element{Activity(".Main", true)},
+	}
+	xmlFile, err := androidbinary.NewXMLFile(bytes.NewReader(AXML(apk.Manifest())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(xmlFile.Reader()); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		`package="com.example.fixture"`,
+		`versionCode="3"`,
+		`versionName="1.2"`,
+		`minSdkVersion="21"`,
+		`name="android.permission.INTERNET"`,
+		`label="Fixture"`,
+		`debuggable="true"`,
+		`name="android.intent.action.MAIN"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("got %s want %s", got, want)
+		}
+	}
+}
+
+func TestIPA(t *testing.T) {
+	ipa := &IPA{BundleId: "com.example.fixture", Name: "Fixture", Version: "1.0", Build: "1", Profile: &Profile{TeamId: "ABCDE12345"}}
+	data, err := ipa.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range reader.File {
+		names = append(names, f.Name)
+	}
+	want := "Payload/Fixture.app/AppIcon60x60@2x.png Payload/Fixture.app/Info.plist Payload/Fixture.app/embedded.mobileprovision"
+	if got := strings.Join(names, " "); got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	again, _ := ipa.Bytes()
+	if !bytes.Equal(data, again) {
+		t.Errorf("output differs between runs")
+	}
+}
+
+func TestPKCS7(t *testing.T) {
+	der, err := PKCS7([]byte("content"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ci struct {
+		ContentType asn1.ObjectIdentifier
+		Content     asn1.RawValue `asn1:"explicit,tag:0"`
+	}
+	if _, err := asn1.Unmarshal(der, &ci); err != nil {
+		t.Fatal(err)
+	}
+	var sd struct {
+		Version          int
+		DigestAlgorithms asn1.RawValue
+		ContentInfo      struct {
+			ContentType asn1.ObjectIdentifier
+			Content     []byte `asn1:"explicit,tag:0"`
+		}
+		SignerInfos asn1.RawValue
+	}
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+		t.Fatal(err)
+	}
+	if !ci.ContentType.Equal(oidSignedData) || !sd.ContentInfo.ContentType.Equal(oidData) || string(sd.ContentInfo.Content) != "content" {
+		t.Errorf("got %v %v %q", ci.ContentType, sd.ContentInfo.ContentType, sd.ContentInfo.Content)
+	}
+}
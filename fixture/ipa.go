@@ -0,0 +1,161 @@
+package fixture
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"image"
+	"image/color"
+	"image/png"
+	"time"
+)
+
+// IPA describes an .ipa holding Payload/<Name>.app with an Info.plist and
+// a 120x120 AppIcon60x60@2x.png.
+type IPA struct {
+	BundleId         string
+	Name             string
+	Version          string
+	Build            string
+	MinimumOSVersion string
+	// Plist holds further Info.plist keys, which override those above.
+	Plist map[string]interface{}
+	// Profile is written as embedded.mobileprovision if set.
+	Profile *Profile
+	// Files are added under the .app, e.g. "Frameworks/X.framework/X".
+	Files map[string][]byte
+}
+
+// Profile describes a provisioning profile. Its PKCS #7 envelope has no
+// signers, so parsers read it but report it unverified.
+type Profile struct {
+	Name                 string
+	UUID                 string
+	TeamId               string
+	TeamName             string
+	ExpirationDate       time.Time
+	ProvisionedDevices   []string
+	ProvisionsAllDevices bool
+	Entitlements         map[string]interface{}
+}
+
+// InfoPlist returns the Info.plist of i.
+func (i *IPA) InfoPlist() []byte {
+	dict := map[string]interface{}{
+		"CFBundleIdentifier":         i.BundleId,
+		"CFBundleName":               i.Name,
+		"CFBundleExecutable":         i.Name,
+		"CFBundleShortVersionString": i.Version,
+		"CFBundleVersion":            i.Build,
+		"CFBundlePackageType":        "APPL",
+	}
+	if i.MinimumOSVersion != "" {
+		dict["MinimumOSVersion"] = i.MinimumOSVersion
+	}
+	for k, v := range i.Plist {
+		dict[k] = v
+	}
+	return Plist(dict)
+}
+
+// Bytes returns the .ipa.
+func (i *IPA) Bytes() ([]byte, error) {
+	icon, err := Icon(120, color.RGBA{R: 0x20, G: 0x80, B: 0xf0, A: 0xff})
+	if err != nil {
+		return nil, err
+	}
+	dir := "Payload/" + i.Name + ".app/"
+	files := map[string][]byte{
+		dir + "Info.plist":          i.InfoPlist(),
+		dir + "AppIcon60x60@2x.png": icon,
+	}
+	if i.Profile != nil {
+		profile, err := i.Profile.Bytes()
+		if err != nil {
+			return nil, err
+		}
+		files[dir+"embedded.mobileprovision"] = profile
+	}
+	for name, data := range i.Files {
+		files[dir+name] = data
+	}
+	return Zip(files)
+}
+
+// Bytes returns the profile as an embedded.mobileprovision.
+func (p *Profile) Bytes() ([]byte, error) {
+	dict := map[string]interface{}{
+		"Name":           p.Name,
+		"UUID":           p.UUID,
+		"TeamIdentifier": []string{p.TeamId},
+		"TeamName":       p.TeamName,
+		"Platform":       []string{"iOS"},
+		"ExpirationDate": p.ExpirationDate,
+		"Entitlements":   p.Entitlements,
+	}
+	if p.Entitlements == nil {
+		dict["Entitlements"] = map[string]interface{}{}
+	}
+	if p.ProvisionedDevices != nil {
+		dict["ProvisionedDevices"] = p.ProvisionedDevices
+	}
+	if p.ProvisionsAllDevices {
+		dict["ProvisionsAllDevices"] = true
+	}
+	return PKCS7(Plist(dict))
+}
+
+var (
+	oidData       = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidSignedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+)
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue
+}
+
+type signedData struct {
+	Version          int
+	DigestAlgorithms []asn1.RawValue `asn1:"set"`
+	ContentInfo      contentInfo
+	SignerInfos      []asn1.RawValue `asn1:"set"`
+}
+
+// PKCS7 wraps content in a PKCS #7 SignedData without signers, the
+// envelope of provisioning profiles.
+func PKCS7(content []byte) ([]byte, error) {
+	data, err := asn1.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+	sd, err := asn1.Marshal(signedData{
+		Version:          1,
+		DigestAlgorithms: []asn1.RawValue{},
+		ContentInfo:      contentInfo{ContentType: oidData, Content: explicit(data)},
+		SignerInfos:      []asn1.RawValue{},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(contentInfo{ContentType: oidSignedData, Content: explicit(sd)})
+}
+
+// explicit tags der as the [0] EXPLICIT content of a contentInfo.
+func explicit(der []byte) asn1.RawValue {
+	return asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: der}
+}
+
+// Icon returns a size by size PNG filled with c.
+func Icon(size int, c color.Color) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
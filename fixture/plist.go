@@ -0,0 +1,70 @@
+package fixture
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Plist encodes dict as an XML property list. Values are strings, bools,
+// ints, time.Times, []byte, []string, []interface{} and
+// map[string]interface{}; keys are written in order.
+func Plist(dict map[string]interface{}) []byte {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	b.WriteString(`<plist version="1.0">` + "\n")
+	writePlistValue(&b, dict)
+	b.WriteString("</plist>\n")
+	return b.Bytes()
+}
+
+func writePlistValue(b *bytes.Buffer, v interface{}) {
+	switch v := v.(type) {
+	case string:
+		b.WriteString("<string>")
+		xml.EscapeText(b, []byte(v))
+		b.WriteString("</string>\n")
+	case bool:
+		fmt.Fprintf(b, "<%t/>\n", v)
+	case int:
+		fmt.Fprintf(b, "<integer>%d</integer>\n", v)
+	case time.Time:
+		fmt.Fprintf(b, "<date>%s</date>\n", v.UTC().Format(time.RFC3339))
+	case []byte:
+		b.WriteString("<data>")
+		b.WriteString(base64.StdEncoding.EncodeToString(v))
+		b.WriteString("</data>\n")
+	case []string:
+		b.WriteString("<array>\n")
+		for _, s := range v {
+			writePlistValue(b, s)
+		}
+		b.WriteString("</array>\n")
+	case []interface{}:
+		b.WriteString("<array>\n")
+		for _, e := range v {
+			writePlistValue(b, e)
+		}
+		b.WriteString("</array>\n")
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b.WriteString("<dict>\n")
+		for _, k := range keys {
+			b.WriteString("<key>")
+			xml.EscapeText(b, []byte(k))
+			b.WriteString("</key>\n")
+			writePlistValue(b, v[k])
+		}
+		b.WriteString("</dict>\n")
+	default:
+		panic(fmt.Sprintf("fixture: unsupported plist value %T", v))
+	}
+}
@@ -0,0 +1,209 @@
+package appfile
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Format parses one kind of artifact from its contents. name is the path
+// of the file being parsed.
+type Format interface {
+	Parse(name string, r io.ReaderAt, size int64) (*AppInfo, error)
+}
+
+// FormatFunc adapts a function to Format.
+type FormatFunc func(name string, r io.ReaderAt, size int64) (*AppInfo, error)
+
+func (f FormatFunc) Parse(name string, r io.ReaderAt, size int64) (*AppInfo, error) {
+	return f(name, r, size)
+}
+
+// Detector reports whether an artifact is of a format from its first
+// bytes, for formats without a distinctive extension.
+type Detector func(header []byte) bool
+
+// detectorHeaderSize is the number of bytes passed to a Detector, fewer
+// for smaller files.
+const detectorHeaderSize = 512
+
+type detectedFormat struct {
+	detect Detector
+	format Format
+}
+
+var (
+	formatsMu sync.RWMutex
+	formats   = map[string]Format{
+		androidExt: zipFormat(parseApk),
+		apksExt: zipFormat(func(_ string, reader *zip.Reader, o *options) (*AppInfo, error) {
+			return parseApks(reader, o)
+		}),
+		aabExt: zipFormat(parseAab),
+		iosExt: zipFormat(func(_ string, reader *zip.Reader, o *options) (*AppInfo, error) {
+			return parseIpa(reader, o)
+		}),
+		tpkExt:         zipFormat(parseTpk),
+		wgtExt:         zipFormat(parseWgt),
+		crxExt:         formatFunc(parseCrx),
+		webManifestExt: formatFunc(parseWebManifest),
+		debExt:         formatFunc(parseDeb),
+		aarExt:         zipFormat(parseAar),
+		frameworkExt:   dirFormat(parseFramework),
+		xcframeworkExt: dirFormat(parseFramework),
+	}
+	detectors []detectedFormat
+)
+
+// RegisterFormat makes NewAppParser parse files with the extension ext,
+// e.g. ".xapk", with f. It replaces the format previously registered for
+// ext, including the built-in ones.
+func RegisterFormat(ext string, f Format) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	formats[strings.ToLower(ext)] = f
+}
+
+// RegisterDetector makes NewAppParser parse files with f when detect
+// matches them and their extension has no registered format. Detectors are
+// tried in the order they were registered.
+func RegisterDetector(detect Detector, f Format) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	detectors = append(detectors, detectedFormat{detect, f})
+}
+
+// IsSupported reports whether name has the extension of a registered
+// format. Files only a Detector recognizes are not reported, nor are
+// containers such as .zip, most of which hold no app; see ContainsApp.
+func IsSupported(name string) bool {
+	if IsContainer(name) {
+		return false
+	}
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	_, ok := formats[formatExt(name)]
+	return ok
+}
+
+// formatExt returns the extension of name that formats are registered
+// by: the last one, or the compound .tar.gz, in lower case.
+func formatExt(name string) string {
+	name = strings.ToLower(name)
+	if strings.HasSuffix(name, tarGzExt) {
+		return tarGzExt
+	}
+	return filepath.Ext(name)
+}
+
+func lookupFormat(name string, stat os.FileInfo, r io.ReaderAt) Format {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	f := formats[formatExt(name)]
+	if _, ok := f.(dirFormat); ok != stat.IsDir() {
+		return nil
+	}
+	if f != nil || stat.IsDir() || len(detectors) == 0 {
+		return f
+	}
+
+	header := make([]byte, detectorHeaderSize)
+	n, err := r.ReadAt(header, 0)
+	if err != nil && err != io.EOF {
+		return nil
+	}
+	for _, d := range detectors {
+		if d.detect(header[:n]) {
+			return d.format
+		}
+	}
+	return nil
+}
+
+// optionsFormat is implemented by the built-in formats, which report their
+// stages to the parse options.
+type optionsFormat interface {
+	parse(name string, r io.ReaderAt, size int64, o *options) (*AppInfo, error)
+}
+
+type formatFunc func(name string, r io.ReaderAt, size int64, o *options) (*AppInfo, error)
+
+func (f formatFunc) Parse(name string, r io.ReaderAt, size int64) (*AppInfo, error) {
+	return f(name, r, size, newOptions(nil))
+}
+
+func (f formatFunc) parse(name string, r io.ReaderAt, size int64, o *options) (*AppInfo, error) {
+	return f(name, r, size, o)
+}
+
+// dirFormat is a built-in format of bundle directories, which are read
+// from name.
+type dirFormat func(name string, r io.ReaderAt, size int64, o *options) (*AppInfo, error)
+
+func (f dirFormat) Parse(name string, r io.ReaderAt, size int64) (*AppInfo, error) {
+	return f(name, r, size, newOptions(nil))
+}
+
+func (f dirFormat) parse(name string, r io.ReaderAt, size int64, o *options) (*AppInfo, error) {
+	return f(name, r, size, o)
+}
+
+// zipFormat is a built-in format read from a zip archive.
+type zipFormat func(name string, reader *zip.Reader, o *options) (*AppInfo, error)
+
+func (f zipFormat) Parse(name string, r io.ReaderAt, size int64) (*AppInfo, error) {
+	return f.parse(name, r, size, newOptions(nil))
+}
+
+func (f zipFormat) parse(name string, r io.ReaderAt, size int64, o *options) (*AppInfo, error) {
+	reader, warnings, release, err := openZip(r, size, o)
+	if err != nil {
+		var errs stageErrors
+		errs.add(StageZipRead, err)
+		return nil, errs.err()
+	}
+	defer release()
+	info, err := f(name, reader, o)
+	if info != nil {
+		info.Warnings = append(warnings, info.Warnings...)
+		// The v2 and v3 signatures lie between the entries and the
+		// central directory, so the signer is read from r. Containers and
+		// .apks files leave the signer of the APK they hold.
+		if info.Android != nil && info.Android.Signer == nil {
+			info.Android.Signer = apkSigner(r, size, reader.File)
+		}
+	}
+	return info, err
+}
+
+// openZip reads the zip archive r as the options allow, repairing or
+// decrypting it, and returns the warnings about its repairs. release frees
+// the decrypted copy once the reader is no longer used.
+func openZip(r io.ReaderAt, size int64, o *options) (_ *zip.Reader, _ []string, release func(), err error) {
+	reader, err := zip.NewReader(r, size)
+	var warnings []string
+	release = func() {}
+	if o.tolerantZip && (err != nil || needsRepair(reader)) {
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%v; the archive is rebuilt", err))
+		}
+		var fixes []string
+		reader, fixes, err = repairZip(r, size)
+		if err != nil && o.recoverZip {
+			reader, fixes, err = recoverZip(r, size)
+		}
+		warnings = append(warnings, fixes...)
+	}
+	if err == nil && zipEncrypted(reader) {
+		if o.password == "" {
+			err = ErrEncrypted
+		} else {
+			reader, release, err = decryptZip(reader, size, o.password, o)
+		}
+	}
+	return reader, warnings, release, err
+}
@@ -0,0 +1,45 @@
+package format
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/follyxing/appfile-info"
+)
+
+// Badging writes info in the layout of `aapt dump badging`. Only lines for
+// data the parser extracts are written.
+func Badging(w io.Writer, info *appfile.AppInfo) error {
+	if info.Android == nil {
+		return ErrPlatform
+	}
+	android := info.Android
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "package: name=%s versionCode=%s versionName=%s\n",
+		aaptQuote(info.BundleId), aaptQuote(info.Build), aaptQuote(info.Version))
+	if android.MinSdkVersion != "" {
+		fmt.Fprintf(bw, "sdkVersion:%s\n", aaptQuote(android.MinSdkVersion))
+	}
+	if android.TargetSdkVersion != "" {
+		fmt.Fprintf(bw, "targetSdkVersion:%s\n", aaptQuote(android.TargetSdkVersion))
+	}
+	for _, p := range android.Permissions {
+		fmt.Fprintf(bw, "uses-permission: name=%s\n", aaptQuote(p))
+	}
+	fmt.Fprintf(bw, "application-label:%s\n", aaptQuote(info.Name))
+	fmt.Fprintf(bw, "application: label=%s\n", aaptQuote(info.Name))
+	if android.Debug {
+		fmt.Fprintf(bw, "application-debuggable\n")
+	}
+	if android.MainActivity != "" {
+		fmt.Fprintf(bw, "launchable-activity: name=%s  label=%s\n", aaptQuote(android.MainActivity), aaptQuote(info.Name))
+	}
+	return bw.Flush()
+}
+
+func aaptQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `\'`, -1) + "'"
+}
@@ -0,0 +1,50 @@
+package format
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/follyxing/appfile-info"
+)
+
+func TestBadging(t *testing.T) {
+	info := &appfile.AppInfo{
+		Platform: appfile.PlatformAndroid,
+		Name:     "Hello 'World'",
+		BundleId: "com.example.helloworld",
+		Version:  "1.0",
+		Build:    "1",
+		Android: &appfile.AndroidInfo{
+			Debug:            true,
+			MinSdkVersion:    "15",
+			TargetSdkVersion: "24",
+			Permissions:      []string{"android.permission.INTERNET", "android.permission.CAMERA"},
+			MainActivity:     "com.example.helloworld.MainActivity",
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Badging(&buf, info); err != nil {
+		t.Errorf("got %v want no error", err)
+	}
+	want := `package: name='com.example.helloworld' versionCode='1' versionName='1.0'
+sdkVersion:'15'
+targetSdkVersion:'24'
+uses-permission: name='android.permission.INTERNET'
+uses-permission: name='android.permission.CAMERA'
+application-label:'Hello \'World\''
+application: label='Hello \'World\''
+application-debuggable
+launchable-activity: name='com.example.helloworld.MainActivity'  label='Hello \'World\''
+`
+	if buf.String() != want {
+		t.Errorf("got %v want %v", buf.String(), want)
+	}
+}
+
+func TestBadgingIpa(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Badging(&buf, &appfile.AppInfo{Platform: appfile.PlatformIOS, Ios: &appfile.IosInfo{}}); err != ErrPlatform {
+		t.Errorf("got %v want %v", err, ErrPlatform)
+	}
+}
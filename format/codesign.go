@@ -0,0 +1,53 @@
+package format
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/follyxing/appfile-info"
+)
+
+var ErrNoProfile = errors.New("provisioning profile not available")
+
+// Codesign writes info in the key=value layout of `codesign -dvvv`. The
+// authority chain is taken from the first developer certificate of the
+// embedded provisioning profile.
+func Codesign(w io.Writer, info *appfile.AppInfo) error {
+	if info.Ios == nil {
+		return ErrPlatform
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "Identifier=%s\n", info.BundleId)
+	fmt.Fprintf(bw, "Format=app bundle\n")
+	teamId := "not set"
+	if p := info.Ios.Profile; p != nil {
+		if len(p.Certificates) > 0 {
+			c := p.Certificates[0]
+			fmt.Fprintf(bw, "Authority=%s\n", c.Subject)
+			if c.Issuer != "" && c.Issuer != c.Subject {
+				fmt.Fprintf(bw, "Authority=%s\n", c.Issuer)
+			}
+		}
+		if p.TeamId != "" {
+			teamId = p.TeamId
+		}
+	}
+	fmt.Fprintf(bw, "TeamIdentifier=%s\n", teamId)
+	return bw.Flush()
+}
+
+// Profile writes the decoded embedded.mobileprovision plist, like
+// `security cms -D -i embedded.mobileprovision`.
+func Profile(w io.Writer, info *appfile.AppInfo) error {
+	if info.Ios == nil {
+		return ErrPlatform
+	}
+	if info.Ios.Profile == nil || len(info.Ios.Profile.Data) == 0 {
+		return ErrNoProfile
+	}
+	_, err := w.Write(info.Ios.Profile.Data)
+	return err
+}
@@ -0,0 +1,66 @@
+package format
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/follyxing/appfile-info"
+)
+
+func TestCodesign(t *testing.T) {
+	info := &appfile.AppInfo{
+		Platform: appfile.PlatformIOS,
+		BundleId: "com.kthcorp.helloworld",
+		Ios: &appfile.IosInfo{
+			Profile: &appfile.ProvisioningProfile{
+				TeamId: "M8ZCXDJQW4",
+				Certificates: []appfile.Certificate{{
+					Subject: "iPhone Distribution: KT Hitel Co., Ltd.",
+					Issuer:  "Apple Worldwide Developer Relations Certification Authority",
+				}},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Codesign(&buf, info); err != nil {
+		t.Errorf("got %v want no error", err)
+	}
+	want := `Identifier=com.kthcorp.helloworld
+Format=app bundle
+Authority=iPhone Distribution: KT Hitel Co., Ltd.
+Authority=Apple Worldwide Developer Relations Certification Authority
+TeamIdentifier=M8ZCXDJQW4
+`
+	if buf.String() != want {
+		t.Errorf("got %v want %v", buf.String(), want)
+	}
+}
+
+func TestCodesignNoProfile(t *testing.T) {
+	info := &appfile.AppInfo{Platform: appfile.PlatformIOS, BundleId: "a", Ios: &appfile.IosInfo{}}
+	var buf bytes.Buffer
+	if err := Codesign(&buf, info); err != nil {
+		t.Errorf("got %v want no error", err)
+	}
+	want := "Identifier=a\nFormat=app bundle\nTeamIdentifier=not set\n"
+	if buf.String() != want {
+		t.Errorf("got %v want %v", buf.String(), want)
+	}
+}
+
+func TestProfile(t *testing.T) {
+	info := &appfile.AppInfo{Platform: appfile.PlatformIOS, Ios: &appfile.IosInfo{}}
+	var buf bytes.Buffer
+	if err := Profile(&buf, info); err != ErrNoProfile {
+		t.Errorf("got %v want %v", err, ErrNoProfile)
+	}
+
+	info.Ios.Profile = &appfile.ProvisioningProfile{Data: []byte("<plist/>")}
+	if err := Profile(&buf, info); err != nil {
+		t.Errorf("got %v want no error", err)
+	}
+	if buf.String() != "<plist/>" {
+		t.Errorf("got %v want %v", buf.String(), "<plist/>")
+	}
+}
@@ -0,0 +1,75 @@
+package format
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/follyxing/appfile-info"
+)
+
+// fastlaneInfo mirrors the keys of the app_info gem used by fastlane's
+// app_info plugin, so lanes reading lane_context[:APP_INFO] work unchanged.
+type fastlaneInfo struct {
+	OS             string   `json:"os"`
+	Name           string   `json:"name"`
+	Identifier     string   `json:"identifier"`
+	ReleaseVersion string   `json:"release_version"`
+	BuildVersion   string   `json:"build_version"`
+	Size           int64    `json:"size"`
+	MinSdkVersion  string   `json:"min_sdk_version,omitempty"`
+	TargetSdk      string   `json:"target_sdk_version,omitempty"`
+	Permissions    []string `json:"use_permissions,omitempty"`
+	Debuggable     *bool    `json:"debuggable,omitempty"`
+	ReleaseType    string   `json:"release_type,omitempty"`
+	ProfileName    string   `json:"profile_name,omitempty"`
+	TeamName       string   `json:"team_name,omitempty"`
+	TeamIdentifier string   `json:"team_identifier,omitempty"`
+	ExpiredDate    string   `json:"expired_date,omitempty"`
+	Devices        []string `json:"devices,omitempty"`
+}
+
+var fastlaneReleaseTypes = map[string]string{
+	"development": "Development",
+	"ad-hoc":      "AdHoc",
+	"enterprise":  "Enterprise",
+	"app-store":   "AppStore",
+}
+
+// Fastlane writes info as the JSON document fastlane's app_info plugin
+// produces for an ipa or apk.
+func Fastlane(w io.Writer, info *appfile.AppInfo) error {
+	f := fastlaneInfo{
+		Name:           info.Name,
+		Identifier:     info.BundleId,
+		ReleaseVersion: info.Version,
+		BuildVersion:   info.Build,
+		Size:           info.Size,
+	}
+
+	switch {
+	case info.Android != nil:
+		f.OS = "Android"
+		f.MinSdkVersion = info.Android.MinSdkVersion
+		f.TargetSdk = info.Android.TargetSdkVersion
+		f.Permissions = info.Android.Permissions
+		debug := info.Android.Debug
+		f.Debuggable = &debug
+	case info.Ios != nil:
+		f.OS = "iOS"
+		if p := info.Ios.Profile; p != nil {
+			f.ReleaseType = fastlaneReleaseTypes[p.SigningType]
+			f.ProfileName = p.Name
+			f.TeamName = p.TeamName
+			f.TeamIdentifier = p.TeamId
+			f.Devices = p.ProvisionedDevices
+			f.ExpiredDate = p.ExpirationDate.UTC().Format(time.RFC3339)
+		}
+	default:
+		return ErrPlatform
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(f)
+}
@@ -0,0 +1,76 @@
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/follyxing/appfile-info"
+)
+
+func decodeFastlane(t *testing.T, info *appfile.AppInfo) map[string]interface{} {
+	var buf bytes.Buffer
+	if err := Fastlane(&buf, info); err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	return m
+}
+
+func TestFastlaneApk(t *testing.T) {
+	m := decodeFastlane(t, &appfile.AppInfo{
+		Platform: appfile.PlatformAndroid,
+		Name:     "HelloWorld",
+		BundleId: "com.example.helloworld",
+		Version:  "1.0",
+		Build:    "1",
+		Android:  &appfile.AndroidInfo{MinSdkVersion: "15"},
+	})
+
+	want := map[string]interface{}{
+		"os":              "Android",
+		"identifier":      "com.example.helloworld",
+		"release_version": "1.0",
+		"build_version":   "1",
+		"min_sdk_version": "15",
+		"debuggable":      false,
+	}
+	for k, v := range want {
+		if m[k] != v {
+			t.Errorf("%v: got %v want %v", k, m[k], v)
+		}
+	}
+	if _, ok := m["team_identifier"]; ok {
+		t.Errorf("got team_identifier want omitted")
+	}
+}
+
+func TestFastlaneIpa(t *testing.T) {
+	m := decodeFastlane(t, &appfile.AppInfo{
+		Platform: appfile.PlatformIOS,
+		BundleId: "com.kthcorp.helloworld",
+		Ios: &appfile.IosInfo{
+			Profile: &appfile.ProvisioningProfile{
+				SigningType:    "enterprise",
+				ExpirationDate: time.Date(2012, 6, 20, 5, 48, 15, 0, time.UTC),
+				TeamId:         "M8ZCXDJQW4",
+			},
+		},
+	})
+
+	want := map[string]interface{}{
+		"os":              "iOS",
+		"release_type":    "Enterprise",
+		"team_identifier": "M8ZCXDJQW4",
+		"expired_date":    "2012-06-20T05:48:15Z",
+	}
+	for k, v := range want {
+		if m[k] != v {
+			t.Errorf("%v: got %v want %v", k, m[k], v)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+// Package format renders AppInfo in the output formats of other tools.
+package format
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/follyxing/appfile-info"
+)
+
+var ErrPlatform = errors.New("format not supported for this platform")
+
+// Formatter writes info to w.
+type Formatter func(w io.Writer, info *appfile.AppInfo) error
+
+// JSON writes info as indented JSON.
+func JSON(w io.Writer, info *appfile.AppInfo) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(info)
+}
@@ -0,0 +1,26 @@
+package format
+
+import (
+	"io"
+	"text/tabwriter"
+
+	"github.com/follyxing/appfile-info"
+)
+
+// Permissions lists the requested permissions with their protection level
+// and label, most sensitive first.
+func Permissions(w io.Writer, info *appfile.AppInfo) error {
+	if info.Android == nil {
+		return ErrPlatform
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for _, p := range appfile.ClassifyPermissions(info.Android.Permissions) {
+		label := p.Label
+		if label == "" {
+			label = "-"
+		}
+		io.WriteString(tw, p.ProtectionLevel+"\t"+p.Name+"\t"+label+"\n")
+	}
+	return tw.Flush()
+}
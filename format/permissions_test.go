@@ -0,0 +1,31 @@
+package format
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/follyxing/appfile-info"
+)
+
+func TestPermissions(t *testing.T) {
+	info := &appfile.AppInfo{
+		Platform: appfile.PlatformAndroid,
+		Android: &appfile.AndroidInfo{Permissions: []string{
+			"android.permission.INTERNET",
+			"android.permission.CAMERA",
+			"com.example.permission.C2D_MESSAGE",
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := Permissions(&buf, info); err != nil {
+		t.Errorf("got %v want no error", err)
+	}
+	want := `dangerous  android.permission.CAMERA           take pictures and videos
+unknown    com.example.permission.C2D_MESSAGE  -
+normal     android.permission.INTERNET         have full network access
+`
+	if buf.String() != want {
+		t.Errorf("got %v want %v", buf.String(), want)
+	}
+}
@@ -0,0 +1,65 @@
+package format
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/follyxing/appfile-info"
+)
+
+var tableHeader = []string{
+	"path", "platform", "name", "bundle_id", "version", "build", "size", "signing", "expiration",
+}
+
+// Table writes one row per artifact as CSV or TSV, preceded by a header.
+type Table struct {
+	w      *csv.Writer
+	header bool
+}
+
+func NewCSV(w io.Writer) *Table {
+	return &Table{w: csv.NewWriter(w)}
+}
+
+func NewTSV(w io.Writer) *Table {
+	t := NewCSV(w)
+	t.w.Comma = '\t'
+	return t
+}
+
+func (t *Table) Write(path string, info *appfile.AppInfo) error {
+	if !t.header {
+		if err := t.w.Write(tableHeader); err != nil {
+			return err
+		}
+		t.header = true
+	}
+
+	var signing, expiration string
+	if info.Ios != nil && info.Ios.Profile != nil {
+		signing = info.Ios.Profile.SigningType
+		expiration = info.Ios.Profile.ExpirationDate.UTC().Format(time.RFC3339)
+	}
+	if info.Android != nil && info.Android.Signer != nil {
+		signing = info.Android.Signer.Subject
+		expiration = info.Android.Signer.NotAfter.UTC().Format(time.RFC3339)
+	}
+	return t.w.Write([]string{
+		path,
+		info.Platform,
+		info.Name,
+		info.BundleId,
+		info.Version,
+		info.Build,
+		strconv.FormatInt(info.Size, 10),
+		signing,
+		expiration,
+	})
+}
+
+func (t *Table) Flush() error {
+	t.w.Flush()
+	return t.w.Error()
+}
@@ -0,0 +1,71 @@
+package format
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/follyxing/appfile-info"
+)
+
+func TestCSV(t *testing.T) {
+	var buf bytes.Buffer
+	table := NewCSV(&buf)
+	table.Write("a.apk", &appfile.AppInfo{
+		Platform: appfile.PlatformAndroid,
+		Name:     "Hello, World",
+		BundleId: "com.example.helloworld",
+		Version:  "1.0",
+		Build:    "1",
+		Size:     371613,
+	})
+	table.Write("b.ipa", &appfile.AppInfo{
+		Platform: appfile.PlatformIOS,
+		Name:     "helloworld",
+		BundleId: "com.kthcorp.helloworld",
+		Version:  "1.0",
+		Build:    "1.0",
+		Size:     37819,
+		Ios: &appfile.IosInfo{
+			Profile: &appfile.ProvisioningProfile{
+				SigningType:    "enterprise",
+				ExpirationDate: time.Date(2012, 6, 20, 5, 48, 15, 0, time.UTC),
+			},
+		},
+	})
+	table.Write("c.apk", &appfile.AppInfo{
+		Platform: appfile.PlatformAndroid,
+		BundleId: "com.example.signed",
+		Android: &appfile.AndroidInfo{
+			Signer: &appfile.Certificate{
+				Subject:  "Android Release",
+				NotAfter: time.Date(2051, 1, 2, 3, 4, 5, 0, time.UTC),
+			},
+		},
+	})
+	if err := table.Flush(); err != nil {
+		t.Errorf("got %v want no error", err)
+	}
+
+	want := `path,platform,name,bundle_id,version,build,size,signing,expiration
+a.apk,android,"Hello, World",com.example.helloworld,1.0,1,371613,,
+b.ipa,ios,helloworld,com.kthcorp.helloworld,1.0,1.0,37819,enterprise,2012-06-20T05:48:15Z
+c.apk,android,,com.example.signed,,,0,Android Release,2051-01-02T03:04:05Z
+`
+	if buf.String() != want {
+		t.Errorf("got %v want %v", buf.String(), want)
+	}
+}
+
+func TestTSV(t *testing.T) {
+	var buf bytes.Buffer
+	table := NewTSV(&buf)
+	table.Write("a.apk", &appfile.AppInfo{Platform: appfile.PlatformAndroid})
+	table.Flush()
+
+	want := "path\tplatform\tname\tbundle_id\tversion\tbuild\tsize\tsigning\texpiration\n" +
+		"a.apk\tandroid\t\t\t\t\t0\t\t\n"
+	if buf.String() != want {
+		t.Errorf("got %q want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,173 @@
+package format
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/follyxing/appfile-info"
+)
+
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"join": strings.Join,
+}
+
+// Template returns a Formatter executing the Go template text against the
+// AppInfo, followed by a newline.
+func Template(text string) (Formatter, error) {
+	t, err := template.New("format").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return func(w io.Writer, info *appfile.AppInfo) error {
+		bw := bufio.NewWriter(w)
+		if err := t.Execute(bw, info); err != nil {
+			return err
+		}
+		bw.WriteByte('\n')
+		return bw.Flush()
+	}, nil
+}
+
+type pathStep struct {
+	field string
+	index int
+	all   bool
+}
+
+// JSONPath returns a Formatter printing the values selected by a simple
+// JSONPath-like expression over the JSON form of AppInfo, one per line.
+// Supported are field access and array indexing, e.g.
+// "{.ios.profile.team_id}" or ".android.permissions[*]". Strings and
+// numbers are printed bare, objects and arrays as JSON.
+func JSONPath(expr string) (Formatter, error) {
+	steps, err := parseJSONPath(expr)
+	if err != nil {
+		return nil, err
+	}
+	return func(w io.Writer, info *appfile.AppInfo) error {
+		b, err := json.Marshal(info)
+		if err != nil {
+			return err
+		}
+		// Numbers are kept as written: as float64, sizes would print in
+		// exponent form and large integers lose precision.
+		d := json.NewDecoder(bytes.NewReader(b))
+		d.UseNumber()
+		var doc interface{}
+		if err := d.Decode(&doc); err != nil {
+			return err
+		}
+
+		bw := bufio.NewWriter(w)
+		for _, v := range selectJSONPath(doc, steps) {
+			switch v := v.(type) {
+			case string:
+				bw.WriteString(v)
+			case map[string]interface{}, []interface{}:
+				b, err := json.Marshal(v)
+				if err != nil {
+					return err
+				}
+				bw.Write(b)
+			case nil:
+			default:
+				fmt.Fprint(bw, v)
+			}
+			bw.WriteByte('\n')
+		}
+		return bw.Flush()
+	}, nil
+}
+
+func parseJSONPath(expr string) ([]pathStep, error) {
+	p := strings.TrimSpace(expr)
+	if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
+		p = p[1 : len(p)-1]
+	}
+	p = strings.TrimPrefix(p, "$")
+
+	var steps []pathStep
+	for len(p) > 0 {
+		switch p[0] {
+		case '.':
+			p = p[1:]
+			n := strings.IndexAny(p, ".[")
+			if n < 0 {
+				n = len(p)
+			}
+			if n == 0 {
+				return nil, fmt.Errorf("jsonpath %q: empty field name", expr)
+			}
+			steps = append(steps, pathStep{field: p[:n]})
+			p = p[n:]
+		case '[':
+			n := strings.IndexByte(p, ']')
+			if n < 0 {
+				return nil, fmt.Errorf("jsonpath %q: unterminated [", expr)
+			}
+			idx := p[1:n]
+			p = p[n+1:]
+			if idx == "*" {
+				steps = append(steps, pathStep{all: true})
+				continue
+			}
+			i, err := strconv.Atoi(idx)
+			if err != nil {
+				return nil, fmt.Errorf("jsonpath %q: bad index %q", expr, idx)
+			}
+			steps = append(steps, pathStep{index: i})
+		default:
+			return nil, fmt.Errorf("jsonpath %q: unexpected %q", expr, p[0])
+		}
+	}
+	return steps, nil
+}
+
+func selectJSONPath(v interface{}, steps []pathStep) []interface{} {
+	if len(steps) == 0 {
+		return []interface{}{v}
+	}
+	step, rest := steps[0], steps[1:]
+
+	if step.field != "" {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		child, ok := m[step.field]
+		if !ok {
+			return nil
+		}
+		return selectJSONPath(child, rest)
+	}
+
+	a, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	if step.all {
+		var out []interface{}
+		for _, child := range a {
+			out = append(out, selectJSONPath(child, rest)...)
+		}
+		return out
+	}
+	i := step.index
+	if i < 0 {
+		i += len(a)
+	}
+	if i < 0 || i >= len(a) {
+		return nil
+	}
+	return selectJSONPath(a[i], rest)
+}
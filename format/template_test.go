@@ -0,0 +1,88 @@
+package format
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/follyxing/appfile-info"
+)
+
+var templateInfo = &appfile.AppInfo{
+	Platform: appfile.PlatformAndroid,
+	BundleId: "com.example.helloworld",
+	Version:  "1.0",
+	Size:     371613,
+	Android: &appfile.AndroidInfo{
+		Permissions: []string{"android.permission.INTERNET", "android.permission.CAMERA"},
+	},
+}
+
+func TestTemplate(t *testing.T) {
+	f, err := Template(`{{.BundleId}} {{.Version}} {{join .Android.Permissions ","}}`)
+	if err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	var buf bytes.Buffer
+	if err := f(&buf, templateInfo); err != nil {
+		t.Errorf("got %v want no error", err)
+	}
+	want := "com.example.helloworld 1.0 android.permission.INTERNET,android.permission.CAMERA\n"
+	if buf.String() != want {
+		t.Errorf("got %v want %v", buf.String(), want)
+	}
+}
+
+func TestJSONPath(t *testing.T) {
+	tests := map[string]string{
+		"{.bundle_id}":             "com.example.helloworld\n",
+		".size":                    "371613\n",
+		".android.permissions[1]":  "android.permission.CAMERA\n",
+		".android.permissions[-1]": "android.permission.CAMERA\n",
+		"$.android.permissions[*]": "android.permission.INTERNET\nandroid.permission.CAMERA\n",
+		".android.permissions":     `["android.permission.INTERNET","android.permission.CAMERA"]` + "\n",
+		".missing":                 "",
+		".android.permissions[9]":  "",
+		".bundle_id.unexpected":    "",
+	}
+	for expr, want := range tests {
+		f, err := JSONPath(expr)
+		if err != nil {
+			t.Errorf("%v: got %v want no error", expr, err)
+			continue
+		}
+		var buf bytes.Buffer
+		if err := f(&buf, templateInfo); err != nil {
+			t.Errorf("%v: got %v want no error", expr, err)
+		}
+		if buf.String() != want {
+			t.Errorf("%v: got %q want %q", expr, buf.String(), want)
+		}
+	}
+}
+
+func TestJSONPathNumbers(t *testing.T) {
+	for size, want := range map[int64]string{
+		52428800:         "52428800\n",
+		9007199254740993: "9007199254740993\n",
+	} {
+		f, err := JSONPath("{.size}")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := f(&buf, &appfile.AppInfo{Size: size}); err != nil {
+			t.Errorf("got %v want no error", err)
+		}
+		if buf.String() != want {
+			t.Errorf("got %q want %q", buf.String(), want)
+		}
+	}
+}
+
+func TestJSONPathInvalid(t *testing.T) {
+	for _, expr := range []string{".", ".a[", ".a[x]", "a"} {
+		if _, err := JSONPath(expr); err == nil {
+			t.Errorf("%v: got nil want error", expr)
+		}
+	}
+}
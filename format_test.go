@@ -0,0 +1,69 @@
+package appfile
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, name string, b []byte) string {
+	name = filepath.Join(t.TempDir(), name)
+	if err := ioutil.WriteFile(name, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+func readBundleId(name string, r io.ReaderAt, size int64) (*AppInfo, error) {
+	b := make([]byte, size)
+	if _, err := r.ReadAt(b, 0); err != nil {
+		return nil, err
+	}
+	info := newAppInfo(PlatformAndroid)
+	info.BundleId = string(bytes.TrimPrefix(b, []byte("CONTAINER ")))
+	return info, nil
+}
+
+func TestRegisterFormat(t *testing.T) {
+	name := writeFile(t, "app.Container", []byte("CONTAINER com.example.app"))
+	if IsSupported(name) {
+		t.Fatalf("got supported want unsupported")
+	}
+	if _, err := NewAppParser(name); err != ErrUnknownFormat {
+		t.Errorf("got %v want %v", err, ErrUnknownFormat)
+	}
+
+	RegisterFormat(".container", FormatFunc(readBundleId))
+	if !IsSupported(name) {
+		t.Errorf("got unsupported want supported")
+	}
+	info, err := NewAppParser(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.BundleId != "com.example.app" {
+		t.Errorf("got %v want %v", info.BundleId, "com.example.app")
+	}
+	if info.Size != 25 {
+		t.Errorf("got %v want %v", info.Size, 25)
+	}
+}
+
+func TestRegisterDetector(t *testing.T) {
+	RegisterDetector(func(header []byte) bool {
+		return bytes.HasPrefix(header, []byte("CONTAINER "))
+	}, FormatFunc(readBundleId))
+
+	info, err := NewAppParser(writeFile(t, "app.bin", []byte("CONTAINER com.example.detected")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.BundleId != "com.example.detected" {
+		t.Errorf("got %v want %v", info.BundleId, "com.example.detected")
+	}
+	if _, err := NewAppParser(writeFile(t, "other.bin", []byte("PK"))); err != ErrUnknownFormat {
+		t.Errorf("got %v want %v", err, ErrUnknownFormat)
+	}
+}
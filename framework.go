@@ -0,0 +1,54 @@
+package appfile
+
+import (
+	"archive/zip"
+	"os"
+	"sync"
+)
+
+// frameworkPackageID is the package id of the android: resources.
+const frameworkPackageID = 0x01
+
+// frameworkResources is a framework-res.apk, opened on first use and kept
+// open for the resources it serves.
+type frameworkResources struct {
+	name string
+	once sync.Once
+	res  *apkResources
+	err  error
+}
+
+// WithFrameworkResources resolves @android: references of system APKs,
+// such as android:label="@android:string/...", against the resource table
+// of name, a framework-res.apk like the one apktool installs. Reuse the
+// option across parses to read the framework only once.
+func WithFrameworkResources(name string) Option {
+	fw := &frameworkResources{name: name}
+	return func(o *options) {
+		o.framework = fw
+	}
+}
+
+func (fw *frameworkResources) load() (*apkResources, error) {
+	fw.once.Do(func() {
+		file, err := os.Open(fw.name)
+		if err != nil {
+			fw.err = err
+			return
+		}
+		stat, err := file.Stat()
+		if err != nil {
+			file.Close()
+			fw.err = err
+			return
+		}
+		reader, err := zip.NewReader(file, stat.Size())
+		if err != nil {
+			file.Close()
+			fw.err = err
+			return
+		}
+		fw.res = newApkResources(reader.File)
+	})
+	return fw.res, fw.err
+}
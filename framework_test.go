@@ -0,0 +1,30 @@
+package appfile
+
+import "testing"
+
+func TestFrameworkResources(t *testing.T) {
+	fw := &frameworkResources{name: "testdata/helloworld.apk"}
+	framework, err := fw.load()
+	if err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	if again, _ := fw.load(); again != framework {
+		t.Errorf("got %p want the loaded %p", again, framework)
+	}
+
+	res := newApkResources(nil)
+	if res.owner("@0x01040000") != res {
+		t.Errorf("got the framework without one set")
+	}
+	res.framework = framework
+	if res.owner("@0x01040000") != framework {
+		t.Errorf("got the app for an android: reference")
+	}
+	if res.owner("@0x7F030000") != res || res.owner("label") != res {
+		t.Errorf("got the framework for an app reference")
+	}
+
+	if _, err := (&frameworkResources{name: "testdata/missing.apk"}).load(); err == nil {
+		t.Errorf("got no error want one")
+	}
+}
@@ -0,0 +1,99 @@
+package appfile
+
+import (
+	"archive/zip"
+	"testing"
+
+	"github.com/follyxing/appfile-info/fixture"
+)
+
+// The fuzz targets feed the decoders of untrusted archive entries, e.g.
+//
+//	go test -run '^$' -fuzz FuzzInfoPlist
+//
+// They are seeded with fixture files and, where it has one, the entry of
+// testdata/helloworld; they fail on panics only.
+
+func FuzzAndroidManifest(f *testing.F) {
+	apk := &fixture.APK{
+		Package:     "com.example.fuzz",
+		VersionCode: 1,
+		VersionName: "1.0",
+		Label:       "Fuzz",
+		MinSdk:      21,
+		Permissions: []string{"android.permission.INTERNET"},
+		Application: []*fixture.Element{fixture.Activity(".Main", true)},
+	}
+	f.Add(fixture.AXML(apk.Manifest()))
+	addZipSeed(f, "testdata/helloworld.apk", "AndroidManifest.xml")
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if manifest, err := decodeAndroidManifest(data); err == nil {
+			newAndroidAppInfo(manifest)
+		}
+	})
+}
+
+func FuzzInfoPlist(f *testing.F) {
+	ipa := &fixture.IPA{
+		BundleId: "com.example.fuzz",
+		Name:     "Fuzz",
+		Version:  "1.0",
+		Build:    "1",
+		Plist: map[string]interface{}{
+			"UIDeviceFamily":               []interface{}{1, 2},
+			"UIRequiredDeviceCapabilities": []string{"arm64"},
+			"NSAppClip":                    map[string]interface{}{},
+		},
+	}
+	f.Add(ipa.InfoPlist())
+	addZipSeed(f, "testdata/helloworld.ipa", "Payload/helloworld.app/Info.plist")
+	f.Fuzz(func(t *testing.T, data []byte) {
+		decodeInfoPlist(data)
+	})
+}
+
+func FuzzProfile(f *testing.F) {
+	profile, err := (&fixture.Profile{
+		Name:               "Fuzz",
+		TeamId:             "ABCDE12345",
+		ProvisionedDevices: []string{"00008030-0000000000000000"},
+		Entitlements:       map[string]interface{}{"get-task-allow": true},
+	}).Bytes()
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(profile)
+	addZipSeed(f, "testdata/helloworld.ipa", "Payload/helloworld.app/"+profileFileName)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		decodeProfile(data)
+	})
+}
+
+func FuzzARSC(f *testing.F) {
+	addZipSeed(f, "testdata/helloworld.apk", "resources.arsc")
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if table, err := parseARSC(data); err == nil {
+			for id, rs := range table.resources {
+				table.name(id)
+				for _, r := range rs {
+					table.resolveString(r.arscEntry)
+				}
+			}
+		}
+	})
+}
+
+// addZipSeed adds the entry of the archive name to the corpus of f, if both
+// exist.
+func addZipSeed(f *testing.F, name, entry string) {
+	r, err := zip.OpenReader(name)
+	if err != nil {
+		return
+	}
+	defer r.Close()
+	if zf := findZipFile(r.File, entry); zf != nil {
+		if buf, err := readZipFile(zf); err == nil {
+			f.Add(buf)
+		}
+	}
+}
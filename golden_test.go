@@ -0,0 +1,86 @@
+package appfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/follyxing/appfile-info/fixture"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+// goldenFixtures are parsed by TestGolden and their results compared with
+// testdata/golden/<name>.json. Add a fixture for a new parser feature and
+// run go test -run TestGolden -update to record its output.
+var goldenFixtures = []struct {
+	name    string
+	ext     string
+	fixture interface{ Bytes() ([]byte, error) }
+}{
+	{"minimal", ".apk", &fixture.APK{
+		Package:     "com.example.minimal",
+		VersionCode: 1,
+		VersionName: "1.0",
+		Label:       "Minimal",
+		MinSdk:      21,
+		TargetSdk:   34,
+	}},
+	{"permissions", ".apk", &fixture.APK{
+		Package:     "com.example.permissions",
+		VersionCode: 42,
+		VersionName: "2.1.0",
+		Label:       "Permissions",
+		MinSdk:      26,
+		TargetSdk:   34,
+		Debuggable:  true,
+		Permissions: []string{"android.permission.INTERNET", "android.permission.CAMERA"},
+		Application: []*fixture.Element{
+			fixture.Activity("com.example.permissions.MainActivity", true),
+			fixture.Activity("com.example.permissions.SettingsActivity", false),
+		},
+	}},
+}
+
+func TestGolden(t *testing.T) {
+	dir := t.TempDir()
+	for _, tt := range goldenFixtures {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.fixture.Bytes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			name := filepath.Join(dir, tt.name+tt.ext)
+			if err := ioutil.WriteFile(name, data, 0644); err != nil {
+				t.Fatal(err)
+			}
+			info, err := NewAppParser(name)
+			if info == nil {
+				t.Fatal(err)
+			}
+			got, err := json.MarshalIndent(info, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+
+			golden := filepath.Join("testdata", "golden", tt.name+".json")
+			if *update {
+				if err := ioutil.WriteFile(golden, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s differs from the parse result, rerun with -update if the change is intended:\n%s", golden, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package appfile
+
+import (
+	"archive/zip"
+	"strings"
+)
+
+// googleServicesPlist is the GoogleService-Info.plist of iOS apps.
+type googleServicesPlist struct {
+	GoogleAppId   string `plist:"GOOGLE_APP_ID"`
+	ProjectId     string `plist:"PROJECT_ID"`
+	SenderId      string `plist:"GCM_SENDER_ID"`
+	ApiKey        string `plist:"API_KEY"`
+	DatabaseURL   string `plist:"DATABASE_URL"`
+	StorageBucket string `plist:"STORAGE_BUCKET"`
+}
+
+// parseApkGoogleServices reads the string resources the google-services
+// Gradle plugin generates from google-services.json.
+func parseApkGoogleServices(res *apkResources) *GoogleServices {
+	return newGoogleServices(googleServicesPlist{
+		GoogleAppId:   res.stringNamed("google_app_id"),
+		ProjectId:     res.stringNamed("project_id"),
+		SenderId:      res.stringNamed("gcm_defaultSenderId"),
+		ApiKey:        res.stringNamed("google_api_key"),
+		DatabaseURL:   res.stringNamed("firebase_database_url"),
+		StorageBucket: res.stringNamed("google_storage_bucket"),
+	})
+}
+
+func parseIpaGoogleServices(f *zip.File) (*GoogleServices, error) {
+	if f == nil {
+		return nil, nil
+	}
+	var p googleServicesPlist
+	if err := readZipPlist(f, &p); err != nil {
+		return nil, err
+	}
+	return newGoogleServices(p), nil
+}
+
+// newGoogleServices returns nil when the app has no Google app id. The
+// project number is the second field of the app id,
+// 1:<number>:<platform>:<hash>.
+func newGoogleServices(p googleServicesPlist) *GoogleServices {
+	if p.GoogleAppId == "" {
+		return nil
+	}
+	g := &GoogleServices{
+		AppId:         p.GoogleAppId,
+		ProjectId:     p.ProjectId,
+		SenderId:      p.SenderId,
+		ApiKey:        p.ApiKey != "",
+		DatabaseURL:   p.DatabaseURL,
+		StorageBucket: p.StorageBucket,
+	}
+	if parts := strings.Split(p.GoogleAppId, ":"); len(parts) == 4 {
+		g.ProjectNumber = parts[1]
+	}
+	return g
+}
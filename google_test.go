@@ -0,0 +1,43 @@
+package appfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewGoogleServices(t *testing.T) {
+	got := newGoogleServices(googleServicesPlist{
+		GoogleAppId: "1:123456789012:android:0123456789abcdef",
+		ProjectId:   "example-prod",
+		SenderId:    "123456789012",
+		ApiKey:      "AIzaSyExample",
+	})
+	want := &GoogleServices{
+		AppId:         "1:123456789012:android:0123456789abcdef",
+		ProjectId:     "example-prod",
+		ProjectNumber: "123456789012",
+		SenderId:      "123456789012",
+		ApiKey:        true,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+
+	if got := newGoogleServices(googleServicesPlist{SenderId: "1"}); got != nil {
+		t.Errorf("got %+v want nil", got)
+	}
+}
+
+func TestParseApkGoogleServices(t *testing.T) {
+	reader, err := getAppZipReader("testdata/helloworld.apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := newApkResources(reader.File)
+	if got := res.stringNamed("app_name"); got != "HelloWorld" {
+		t.Errorf("got %v want %v", got, "HelloWorld")
+	}
+	if got := parseApkGoogleServices(res); got != nil {
+		t.Errorf("got %+v want nil", got)
+	}
+}
@@ -0,0 +1,267 @@
+// Package googleplay checks a parsed .apk or .aab against the tracks of its
+// app on Google Play through the Play Developer API, so a CI job can fail
+// before an upload is rejected for its versionCode.
+package googleplay
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/follyxing/appfile-info"
+)
+
+// DefaultBaseURL is the Play Developer API.
+const DefaultBaseURL = "https://androidpublisher.googleapis.com"
+
+const scope = "https://www.googleapis.com/auth/androidpublisher"
+
+// APIError is an error response of the API or the token endpoint.
+type APIError struct {
+	Status  int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("googleplay: %d: %s", e.Status, e.Message)
+}
+
+// Client queries the API as a service account that was granted access to
+// the app in the Play Console.
+type Client struct {
+	Email    string
+	Key      *rsa.PrivateKey
+	TokenURL string
+
+	BaseURL    string       // DefaultBaseURL if empty
+	HTTPClient *http.Client // http.DefaultClient if nil
+}
+
+// NewClient returns a Client for the JSON key of a service account, as
+// downloaded from the Google Cloud console.
+func NewClient(serviceAccount []byte) (*Client, error) {
+	var sa struct {
+		ClientEmail string `json:"client_email"`
+		PrivateKey  string `json:"private_key"`
+		TokenURI    string `json:"token_uri"`
+	}
+	if err := json.Unmarshal(serviceAccount, &sa); err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode([]byte(sa.PrivateKey))
+	if block == nil {
+		return nil, errors.New("googleplay: no PEM key")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("googleplay: not an RSA key")
+	}
+	return &Client{Email: sa.ClientEmail, Key: rsaKey, TokenURL: sa.TokenURI}, nil
+}
+
+// Check relates a build to the tracks of its app.
+type Check struct {
+	VersionCode int64 `json:"version_code"`
+	// Tracks holds the highest versionCode released on each track, e.g.
+	// production or beta.
+	Tracks map[string]int64 `json:"tracks"`
+	// Accepted is set if VersionCode is above that of every track. Codes
+	// of releases no longer on a track are not visible to the API, so
+	// Play may still reject a code it has seen before.
+	Accepted bool   `json:"accepted"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// Tracks returns the highest versionCode released on each track of the
+// app packageName.
+func (c *Client) Tracks(ctx context.Context, packageName string) (map[string]int64, error) {
+	token, err := c.token(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	// Tracks are read within an edit, which is deleted again unchanged.
+	path := "/androidpublisher/v3/applications/" + url.PathEscape(packageName) + "/edits"
+	var edit struct {
+		ID string `json:"id"`
+	}
+	if err := c.do(ctx, token, http.MethodPost, path, &edit); err != nil {
+		return nil, err
+	}
+	defer c.do(ctx, token, http.MethodDelete, path+"/"+url.PathEscape(edit.ID), nil)
+
+	var resp struct {
+		Tracks []struct {
+			Track    string `json:"track"`
+			Releases []struct {
+				VersionCodes []string `json:"versionCodes"`
+			} `json:"releases"`
+		} `json:"tracks"`
+	}
+	if err := c.do(ctx, token, http.MethodGet, path+"/"+url.PathEscape(edit.ID)+"/tracks", &resp); err != nil {
+		return nil, err
+	}
+	tracks := make(map[string]int64, len(resp.Tracks))
+	for _, t := range resp.Tracks {
+		var highest int64
+		for _, r := range t.Releases {
+			for _, code := range r.VersionCodes {
+				if n, err := strconv.ParseInt(code, 10, 64); err == nil && n > highest {
+					highest = n
+				}
+			}
+		}
+		tracks[t.Track] = highest
+	}
+	return tracks, nil
+}
+
+// Check checks the versionCode of info, the parsed .apk or .aab, against
+// the tracks of its app.
+func (c *Client) Check(ctx context.Context, info *appfile.AppInfo) (*Check, error) {
+	code, err := strconv.ParseInt(info.Build, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("googleplay: versionCode %q: %w", info.Build, err)
+	}
+	tracks, err := c.Tracks(ctx, info.BundleId)
+	if err != nil {
+		return nil, err
+	}
+
+	check := &Check{VersionCode: code, Tracks: tracks, Accepted: true}
+	names := make([]string, 0, len(tracks))
+	for name := range tracks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if tracks[name] >= code {
+			check.Accepted = false
+			check.Reason = fmt.Sprintf("versionCode %d is not above %d on %s", code, tracks[name], name)
+			break
+		}
+	}
+	return check, nil
+}
+
+func (c *Client) do(ctx context.Context, token, method, path string, v interface{}) error {
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	req, err := http.NewRequest(method, base+path, nil)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return err
+	}
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func (c *Client) client() *http.Client {
+	if c.HTTPClient == nil {
+		return http.DefaultClient
+	}
+	return c.HTTPClient
+}
+
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode/100 == 2 {
+		return nil
+	}
+	var body struct {
+		Error            json.RawMessage `json:"error"`
+		ErrorDescription string          `json:"error_description"`
+	}
+	apiErr := &APIError{Status: resp.StatusCode, Message: resp.Status}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if json.Unmarshal(data, &body) == nil {
+		// The API nests a message, the token endpoint uses OAuth errors.
+		var nested struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body.Error, &nested) == nil && nested.Message != "" {
+			apiErr.Message = nested.Message
+		} else if body.ErrorDescription != "" {
+			apiErr.Message = body.ErrorDescription
+		}
+	}
+	return apiErr
+}
+
+// token exchanges an RS256 JWT signed with the service account key for an
+// OAuth access token.
+func (c *Client) token(ctx context.Context, now time.Time) (string, error) {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iss":   c.Email,
+		"scope": scope,
+		"aud":   c.TokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	enc := base64.RawURLEncoding
+	signed := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	sum := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, c.Key, crypto.SHA256, sum[:])
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {signed + "." + enc.EncodeToString(sig)},
+	}
+	req, err := http.NewRequest(http.MethodPost, c.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.client().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return "", err
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
@@ -0,0 +1,116 @@
+package googleplay
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/follyxing/appfile-info"
+)
+
+func newTestClient(t *testing.T, tracks string) (*Client, *[]string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var calls []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(r.FormValue("assertion"), ".")
+		sig, _ := base64.RawURLEncoding.DecodeString(parts[len(parts)-1])
+		sum := sha256.Sum256([]byte(strings.Join(parts[:2], ".")))
+		if len(parts) != 3 || rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, sum[:], sig) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant","error_description":"Invalid JWT Signature."}`))
+			return
+		}
+		w.Write([]byte(`{"access_token":"t0k3n","expires_in":3599}`))
+	})
+	mux.HandleFunc("/androidpublisher/v3/applications/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer t0k3n" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		switch {
+		case !strings.HasPrefix(r.URL.Path, "/androidpublisher/v3/applications/com.example.app/"):
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":404,"message":"Package not found: com.example.other."}}`))
+		case r.Method == http.MethodPost:
+			w.Write([]byte(`{"id":"e1"}`))
+		case strings.HasSuffix(r.URL.Path, "/tracks"):
+			w.Write([]byte(tracks))
+		}
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	sa, _ := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "ci@example.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":    srv.URL + "/token",
+	})
+	c, err := NewClient(sa)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.BaseURL = srv.URL
+	return c, &calls
+}
+
+func TestCheck(t *testing.T) {
+	c, calls := newTestClient(t, `{"tracks":[
+		{"track":"production","releases":[{"status":"completed","versionCodes":["40"]}]},
+		{"track":"beta","releases":[{"status":"completed","versionCodes":["41","42"]}]}
+	]}`)
+	ctx := context.Background()
+
+	check, err := c.Check(ctx, &appfile.AppInfo{BundleId: "com.example.app", Build: "43"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Check{VersionCode: 43, Tracks: map[string]int64{"production": 40, "beta": 42}, Accepted: true}
+	if !reflect.DeepEqual(check, want) {
+		t.Errorf("got %+v want %+v", check, want)
+	}
+	wantCalls := []string{
+		"POST /androidpublisher/v3/applications/com.example.app/edits",
+		"GET /androidpublisher/v3/applications/com.example.app/edits/e1/tracks",
+		"DELETE /androidpublisher/v3/applications/com.example.app/edits/e1",
+	}
+	if !reflect.DeepEqual(*calls, wantCalls) {
+		t.Errorf("got %v want %v", *calls, wantCalls)
+	}
+
+	check, err = c.Check(ctx, &appfile.AppInfo{BundleId: "com.example.app", Build: "42"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if check.Accepted || check.Reason != "versionCode 42 is not above 42 on beta" {
+		t.Errorf("got %+v want rejected on beta", check)
+	}
+
+	_, err = c.Check(ctx, &appfile.AppInfo{BundleId: "com.example.other", Build: "1"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusNotFound || apiErr.Message != "Package not found: com.example.other." {
+		t.Errorf("got %v want not found", err)
+	}
+}
@@ -0,0 +1,63 @@
+package appfile
+
+import "context"
+
+// Parse stages reported to hooks.
+const (
+	StageParse      = "parse"
+	StageHash       = "hash"
+	StageSpool      = "spool" // ParseReader copying its stream
+	StageZipRead    = "zip_read"
+	StageManifest   = "manifest_decode"
+	StageProfile    = "profile_decode"
+	StageIcon       = "icon_decode"
+	StageBinary     = "binary_decode"
+	StageURLScan    = "url_scan"
+	StageProvenance = "provenance_verify"
+	StageNotify     = "notify"
+)
+
+// Hook observes parse stages. StartStage is called when a stage begins and
+// the returned function is called with the stage result when it ends. The
+// returned context is used as the parent of nested stages.
+type Hook interface {
+	StartStage(ctx context.Context, stage string) (context.Context, func(err error))
+}
+
+// startStage begins stage and returns the function ending it. The running
+// stages are recorded so a recovered panic can end them.
+func (o *options) startStage(stage string) func(err error) {
+	end := o.beginStage(stage)
+	depth := len(o.running)
+	o.running = append(o.running, end)
+	return func(err error) {
+		o.running = o.running[:depth]
+		end(err)
+	}
+}
+
+func (o *options) beginStage(stage string) func(err error) {
+	parentStage := o.stage
+	o.stage = stage
+	if len(o.hooks) == 0 {
+		return func(error) { o.stage = parentStage }
+	}
+
+	parent := o.ctx
+	ctx := parent
+	ends := make([]func(error), 0, len(o.hooks))
+	for _, h := range o.hooks {
+		var end func(error)
+		ctx, end = h.StartStage(ctx, stage)
+		ends = append(ends, end)
+	}
+	o.ctx = ctx
+
+	return func(err error) {
+		for i := len(ends) - 1; i >= 0; i-- {
+			ends[i](err)
+		}
+		o.ctx = parent
+		o.stage = parentStage
+	}
+}
@@ -0,0 +1,59 @@
+package appfile
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+type stageKey struct{}
+
+type recordingHook struct {
+	events []string
+}
+
+func (h *recordingHook) StartStage(ctx context.Context, stage string) (context.Context, func(error)) {
+	parent, _ := ctx.Value(stageKey{}).(string)
+	h.events = append(h.events, "start "+parent+"/"+stage)
+	return context.WithValue(ctx, stageKey{}, stage), func(error) {
+		h.events = append(h.events, "end "+stage)
+	}
+}
+
+func TestHookStages(t *testing.T) {
+	h := new(recordingHook)
+	NewAppParser("testdata/helloworld.apk", WithHook(h))
+
+	want := []string{
+		"start /parse",
+		"start parse/zip_read",
+		"end zip_read",
+		"start parse/manifest_decode",
+		"end manifest_decode",
+		"start parse/binary_decode",
+		"end binary_decode",
+		"start parse/icon_decode",
+		"end icon_decode",
+		"end parse",
+	}
+	if !reflect.DeepEqual(h.events, want) {
+		t.Errorf("got %v want %v", h.events, want)
+	}
+}
+
+func TestHookStagesOpenError(t *testing.T) {
+	h := new(recordingHook)
+	if _, err := NewAppParser("testdata/missing.apk", WithHook(h)); err == nil {
+		t.Errorf("got nil want error")
+	}
+
+	want := []string{
+		"start /parse",
+		"start parse/zip_read",
+		"end zip_read",
+		"end parse",
+	}
+	if !reflect.DeepEqual(h.events, want) {
+		t.Errorf("got %v want %v", h.events, want)
+	}
+}
@@ -0,0 +1,234 @@
+package appfile
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"strings"
+)
+
+// Hybrid app frameworks and their over-the-air updaters, see HybridInfo.
+const (
+	HybridExpo        = "expo"
+	HybridCapacitor   = "capacitor"
+	HybridReactNative = "react-native"
+
+	UpdaterExpo     = "expo-updates"
+	UpdaterCodePush = "codepush"
+	UpdaterAppflow  = "appflow"
+	UpdaterCapgo    = "capgo"
+)
+
+const (
+	metaExpoRuntimeVersion = "expo.modules.updates.EXPO_RUNTIME_VERSION"
+	metaExpoSDKVersion     = "expo.modules.updates.EXPO_SDK_VERSION"
+	metaExpoUpdateURL      = "expo.modules.updates.EXPO_UPDATE_URL"
+	metaExpoEnabled        = "expo.modules.updates.ENABLED"
+	metaExpoRequestHeaders = "expo.modules.updates.UPDATES_CONFIGURATION_REQUEST_HEADERS_KEY"
+
+	expoChannelHeader = "expo-channel-name"
+)
+
+// expoConfig is the app.config Expo embeds, the resolved app.json.
+type expoConfig struct {
+	SDKVersion string `json:"sdkVersion"`
+	// RuntimeVersion is a string or a policy object, which the build
+	// resolves into the native configuration.
+	RuntimeVersion interface{} `json:"runtimeVersion"`
+	Updates        struct {
+		URL            string            `json:"url"`
+		Enabled        *bool             `json:"enabled"`
+		RequestHeaders map[string]string `json:"requestHeaders"`
+	} `json:"updates"`
+}
+
+type expoPlist struct {
+	RuntimeVersion string            `plist:"EXUpdatesRuntimeVersion"`
+	SDKVersion     string            `plist:"EXUpdatesSDKVersion"`
+	URL            string            `plist:"EXUpdatesURL"`
+	Enabled        *bool             `plist:"EXUpdatesEnabled"`
+	RequestHeaders map[string]string `plist:"EXUpdatesRequestHeaders"`
+}
+
+// capacitorConfig is capacitor.config.json with the settings of the Ionic
+// Appflow and Capgo live update plugins.
+type capacitorConfig struct {
+	Plugins struct {
+		LiveUpdates *struct {
+			Channel          string `json:"channel"`
+			AutoUpdateMethod string `json:"autoUpdateMethod"`
+		} `json:"LiveUpdates"`
+		CapacitorUpdater *struct {
+			AutoUpdate     *bool  `json:"autoUpdate"`
+			UpdateURL      string `json:"updateUrl"`
+			DefaultChannel string `json:"defaultChannel"`
+		} `json:"CapacitorUpdater"`
+	} `json:"plugins"`
+}
+
+// parseApkHybrid reads the CodePush deployment key string resources, or
+// else the Expo configuration from the app.config asset and the
+// expo-updates manifest meta-data, which take precedence.
+func parseApkHybrid(files []*zip.File, res *apkResources, manifest *androidManifest) *HybridInfo {
+	if h := codePushHybrid(res.stringNamed("CodePushDeploymentKey"), res.stringNamed("CodePushServerUrl")); h != nil {
+		return h
+	}
+	if f := findZipFile(files, "assets/capacitor.config.json"); f != nil {
+		return parseCapacitorConfig(f)
+	}
+
+	var h *HybridInfo
+	if f := findZipFile(files, "assets/app.config"); f != nil {
+		h = parseExpoConfig(f)
+	}
+	for _, m := range manifest.Application.MetaData {
+		if !strings.HasPrefix(m.Name, "expo.modules.updates.") {
+			continue
+		}
+		if h == nil {
+			h = &HybridInfo{Framework: HybridExpo, Updater: UpdaterExpo, OTAUpdates: true}
+		}
+		v := res.stringValue(m.Value)
+		switch m.Name {
+		case metaExpoRuntimeVersion:
+			h.RuntimeVersion = v
+		case metaExpoSDKVersion:
+			h.SDKVersion = v
+		case metaExpoUpdateURL:
+			h.UpdateURL = v
+		case metaExpoEnabled:
+			h.OTAUpdates = v != "false"
+		case metaExpoRequestHeaders:
+			var headers map[string]string
+			if json.Unmarshal([]byte(v), &headers) == nil && headers[expoChannelHeader] != "" {
+				h.Channel = headers[expoChannelHeader]
+			}
+		}
+	}
+	if h != nil {
+		h.OTAUpdates = h.OTAUpdates && h.UpdateURL != ""
+	} else if findZipFile(files, "assets/index.android.bundle") != nil {
+		h = &HybridInfo{Framework: HybridReactNative}
+	}
+	return h
+}
+
+// parseIpaHybrid returns codePush, the CodePush configuration from
+// Info.plist, if set. Otherwise it reads the Expo configuration from the
+// app.config of EXConstants.bundle and Expo.plist, which takes precedence.
+func parseIpaHybrid(files []*zip.File, codePush *HybridInfo) *HybridInfo {
+	if codePush != nil {
+		return codePush
+	}
+	if f := findIpaAppFile(files, "capacitor.config.json"); f != nil {
+		return parseCapacitorConfig(f)
+	}
+
+	var h *HybridInfo
+	if f := findIpaAppFile(files, "EXConstants.bundle/app.config"); f != nil {
+		h = parseExpoConfig(f)
+	}
+	if f := findIpaAppFile(files, "Expo.plist"); f != nil {
+		buf, err := readZipFile(f)
+		p := new(expoPlist)
+		if err == nil && decodePlist(buf, p) == nil {
+			if h == nil {
+				h = &HybridInfo{Framework: HybridExpo, Updater: UpdaterExpo}
+			}
+			if p.RuntimeVersion != "" {
+				h.RuntimeVersion = p.RuntimeVersion
+			}
+			if p.SDKVersion != "" {
+				h.SDKVersion = p.SDKVersion
+			}
+			if p.URL != "" {
+				h.UpdateURL = p.URL
+			}
+			if c := p.RequestHeaders[expoChannelHeader]; c != "" {
+				h.Channel = c
+			}
+			h.OTAUpdates = p.Enabled == nil || *p.Enabled
+		}
+	}
+	if h != nil {
+		h.OTAUpdates = h.OTAUpdates && h.UpdateURL != ""
+	} else if findIpaAppFile(files, "main.jsbundle") != nil {
+		h = &HybridInfo{Framework: HybridReactNative}
+	}
+	return h
+}
+
+// codePushHybrid returns the CodePush configuration of a React Native app,
+// or nil without a deployment key. The default server is App Center.
+func codePushHybrid(key, serverURL string) *HybridInfo {
+	if key == "" {
+		return nil
+	}
+	return &HybridInfo{
+		Framework:     HybridReactNative,
+		Updater:       UpdaterCodePush,
+		OTAUpdates:    true,
+		UpdateURL:     serverURL,
+		DeploymentKey: key,
+	}
+}
+
+// parseExpoConfig leaves OTAUpdates set when updates are not disabled; the
+// callers clear it for apps without an update URL.
+func parseExpoConfig(f *zip.File) *HybridInfo {
+	buf, err := readZipFile(f)
+	if err != nil {
+		return nil
+	}
+	var c expoConfig
+	if err := json.Unmarshal(buf, &c); err != nil {
+		return nil
+	}
+	h := &HybridInfo{
+		Framework:  HybridExpo,
+		Updater:    UpdaterExpo,
+		SDKVersion: c.SDKVersion,
+		UpdateURL:  c.Updates.URL,
+		Channel:    c.Updates.RequestHeaders[expoChannelHeader],
+	}
+	if v, ok := c.RuntimeVersion.(string); ok {
+		h.RuntimeVersion = v
+	}
+	h.OTAUpdates = c.Updates.Enabled == nil || *c.Updates.Enabled
+	return h
+}
+
+func parseCapacitorConfig(f *zip.File) *HybridInfo {
+	h := &HybridInfo{Framework: HybridCapacitor}
+	buf, err := readZipFile(f)
+	if err != nil {
+		return h
+	}
+	var c capacitorConfig
+	if err := json.Unmarshal(buf, &c); err != nil {
+		return h
+	}
+	if l := c.Plugins.LiveUpdates; l != nil {
+		h.Updater = UpdaterAppflow
+		h.Channel = l.Channel
+		h.OTAUpdates = l.AutoUpdateMethod != "none"
+	}
+	if u := c.Plugins.CapacitorUpdater; u != nil {
+		h.Updater = UpdaterCapgo
+		h.UpdateURL = u.UpdateURL
+		h.Channel = u.DefaultChannel
+		h.OTAUpdates = u.AutoUpdate == nil || *u.AutoUpdate
+	}
+	return h
+}
+
+// findIpaAppFile returns the file name relative to the .app directory of
+// an IPA.
+func findIpaAppFile(files []*zip.File, name string) *zip.File {
+	for _, f := range files {
+		parts := strings.SplitN(f.Name, "/", 3)
+		if len(parts) == 3 && parts[0] == "Payload" && strings.HasSuffix(parts[1], ".app") && parts[2] == name {
+			return f
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package appfile
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func zipReader(t *testing.T, files map[string]string) *zip.Reader {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, _ := w.Create(name)
+		f.Write([]byte(content))
+	}
+	w.Close()
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reader
+}
+
+func TestParseApkHybridExpo(t *testing.T) {
+	reader := zipReader(t, map[string]string{
+		"assets/app.config": `{"name": "App", "sdkVersion": "50.0.0", "runtimeVersion": {"policy": "appVersion"}}`,
+	})
+	manifest := new(androidManifest)
+	manifest.Application.MetaData = []androidMetaData{
+		{Name: metaExpoRuntimeVersion, Value: "1.2.0"},
+		{Name: metaExpoUpdateURL, Value: "https://u.expo.dev/0e0f"},
+		{Name: metaExpoRequestHeaders, Value: `{"expo-channel-name":"production"}`},
+		{Name: "com.google.android.geo.API_KEY", Value: "key"},
+	}
+
+	got := parseApkHybrid(reader.File, newApkResources(reader.File), manifest)
+	want := &HybridInfo{
+		Framework:      HybridExpo,
+		Updater:        UpdaterExpo,
+		SDKVersion:     "50.0.0",
+		RuntimeVersion: "1.2.0",
+		OTAUpdates:     true,
+		UpdateURL:      "https://u.expo.dev/0e0f",
+		Channel:        "production",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+
+	manifest.Application.MetaData = append(manifest.Application.MetaData, androidMetaData{Name: metaExpoEnabled, Value: "false"})
+	if got := parseApkHybrid(reader.File, newApkResources(reader.File), manifest); got.OTAUpdates {
+		t.Errorf("got %+v want OTA updates disabled", got)
+	}
+}
+
+func TestParseApkHybridNone(t *testing.T) {
+	reader := zipReader(t, map[string]string{"assets/fonts/a.ttf": ""})
+	if got := parseApkHybrid(reader.File, newApkResources(reader.File), new(androidManifest)); got != nil {
+		t.Errorf("got %+v want nil", got)
+	}
+}
+
+func TestParseIpaHybridExpo(t *testing.T) {
+	reader := zipReader(t, map[string]string{
+		"Payload/App.app/EXConstants.bundle/app.config": `{"sdkVersion": "49.0.0", "updates": {"url": "https://u.expo.dev/old"}}`,
+		"Payload/App.app/Expo.plist": `<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict>
+	<key>EXUpdatesRuntimeVersion</key>
+	<string>exposdk:49.0.0</string>
+	<key>EXUpdatesURL</key>
+	<string>https://u.expo.dev/0e0f</string>
+	<key>EXUpdatesRequestHeaders</key>
+	<dict>
+		<key>expo-channel-name</key>
+		<string>staging</string>
+	</dict>
+</dict>
+</plist>`,
+	})
+
+	got := parseIpaHybrid(reader.File, nil)
+	want := &HybridInfo{
+		Framework:      HybridExpo,
+		Updater:        UpdaterExpo,
+		SDKVersion:     "49.0.0",
+		RuntimeVersion: "exposdk:49.0.0",
+		OTAUpdates:     true,
+		UpdateURL:      "https://u.expo.dev/0e0f",
+		Channel:        "staging",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+}
+
+func TestParseHybridCapacitor(t *testing.T) {
+	for _, tt := range []struct {
+		name, config, updater string
+	}{
+		{"assets/capacitor.config.json", `{"appId": "com.example.app", "plugins": {"LiveUpdates": {"appId": "a1b2", "channel": "Production", "autoUpdateMethod": "background"}}}`, UpdaterAppflow},
+		{"Payload/App.app/capacitor.config.json", `{"appId": "com.example.app", "plugins": {"CapacitorUpdater": {"defaultChannel": "Production"}}}`, UpdaterCapgo},
+	} {
+		reader := zipReader(t, map[string]string{tt.name: tt.config})
+		var got *HybridInfo
+		if tt.name[0] == 'a' {
+			got = parseApkHybrid(reader.File, newApkResources(reader.File), new(androidManifest))
+		} else {
+			got = parseIpaHybrid(reader.File, nil)
+		}
+		want := &HybridInfo{Framework: HybridCapacitor, Updater: tt.updater, OTAUpdates: true, Channel: "Production"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %+v want %+v", tt.name, got, want)
+		}
+	}
+
+	reader := zipReader(t, map[string]string{"assets/capacitor.config.json": `{"appId": "com.example.app"}`})
+	got := parseApkHybrid(reader.File, newApkResources(reader.File), new(androidManifest))
+	if want := (&HybridInfo{Framework: HybridCapacitor}); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+}
+
+func TestParseHybridCodePush(t *testing.T) {
+	reader := zipReader(t, map[string]string{
+		"Payload/App.app/main.jsbundle": "",
+		"Payload/App.app/Expo.plist":    `<plist version="1.0"><dict><key>EXUpdatesURL</key><string>https://u.expo.dev/0e0f</string></dict></plist>`,
+	})
+	got := parseIpaHybrid(reader.File, codePushHybrid("dk-ios-0123", "https://codepush.example.com/"))
+	want := &HybridInfo{
+		Framework:     HybridReactNative,
+		Updater:       UpdaterCodePush,
+		OTAUpdates:    true,
+		UpdateURL:     "https://codepush.example.com/",
+		DeploymentKey: "dk-ios-0123",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+	if got := codePushHybrid("", "https://codepush.example.com/"); got != nil {
+		t.Errorf("got %+v want nil", got)
+	}
+}
+
+func TestParseHybridReactNative(t *testing.T) {
+	reader := zipReader(t, map[string]string{"assets/index.android.bundle": ""})
+	want := &HybridInfo{Framework: HybridReactNative}
+	if got := parseApkHybrid(reader.File, newApkResources(reader.File), new(androidManifest)); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+	reader = zipReader(t, map[string]string{"Payload/App.app/main.jsbundle": ""})
+	if got := parseIpaHybrid(reader.File, nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+}
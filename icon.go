@@ -0,0 +1,254 @@
+package appfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math"
+	"strings"
+	"sync"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported image format")
+
+// IosCornerRadius is the corner radius of iOS home screen icons relative to
+// the icon size.
+const IosCornerRadius = 0.2237
+
+// IconEncoder encodes an image in one format.
+type IconEncoder func(w io.Writer, img image.Image) error
+
+var (
+	iconEncodersMu sync.RWMutex
+	iconEncoders   = map[string]IconEncoder{
+		"png": png.Encode,
+		"jpeg": func(w io.Writer, img image.Image) error {
+			// JPEG has no alpha channel, so composite onto white like
+			// browsers do for transparent icons.
+			return jpeg.Encode(w, flatten(img, color.White), &jpeg.Options{Quality: 90})
+		},
+	}
+)
+
+// RegisterIconEncoder makes an encoder available to EncodeIcon. The
+// standard library has no WebP encoder, so "webp" must be registered by the
+// caller, e.g. with github.com/chai2010/webp.
+func RegisterIconEncoder(format string, enc IconEncoder) {
+	iconEncodersMu.Lock()
+	defer iconEncodersMu.Unlock()
+	iconEncoders[strings.ToLower(format)] = enc
+}
+
+// EncodeIcon writes img to w in format ("png", "jpeg" or a registered one).
+func EncodeIcon(w io.Writer, img image.Image, format string) error {
+	format = strings.ToLower(format)
+	if format == "jpg" {
+		format = "jpeg"
+	}
+
+	iconEncodersMu.RLock()
+	enc, ok := iconEncoders[format]
+	iconEncodersMu.RUnlock()
+	if !ok {
+		return ErrUnsupportedFormat
+	}
+	return enc(w, img)
+}
+
+// IconBytes returns img encoded in format.
+func IconBytes(img image.Image, format string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := EncodeIcon(&buf, img, format); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func flatten(img image.Image, bg color.Color) *image.RGBA {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(bg), image.ZP, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Over)
+	return dst
+}
+
+type resampleWeight struct {
+	index  int
+	weight float32
+}
+
+// resampleWeights returns, for each destination index, the source indexes
+// and weights contributing to it: box coverage when shrinking and linear
+// interpolation when enlarging.
+func resampleWeights(dstN, srcN int) [][]resampleWeight {
+	scale := float64(srcN) / float64(dstN)
+	out := make([][]resampleWeight, dstN)
+	for i := range out {
+		var ws []resampleWeight
+		if scale > 1 {
+			lo := float64(i) * scale
+			hi := lo + scale
+			for j := int(lo); j < srcN && float64(j) < hi; j++ {
+				w := math.Min(hi, float64(j+1)) - math.Max(lo, float64(j))
+				if w > 0 {
+					ws = append(ws, resampleWeight{j, float32(w)})
+				}
+			}
+		} else {
+			x := (float64(i)+0.5)*scale - 0.5
+			j := int(math.Floor(x))
+			f := float32(x - float64(j))
+			ws = append(ws, resampleWeight{clampIndex(j, srcN), 1 - f}, resampleWeight{clampIndex(j+1, srcN), f})
+		}
+
+		var sum float32
+		for _, w := range ws {
+			sum += w.weight
+		}
+		for k := range ws {
+			ws[k].weight /= sum
+		}
+		out[i] = ws
+	}
+	return out
+}
+
+func clampIndex(i, n int) int {
+	if i < 0 {
+		return 0
+	}
+	if i >= n {
+		return n - 1
+	}
+	return i
+}
+
+// ResizeIcon scales img to width x height.
+func ResizeIcon(img image.Image, width, height int) *image.NRGBA {
+	b := img.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	if sw == 0 || sh == 0 || width <= 0 || height <= 0 {
+		return dst
+	}
+
+	// premultiplied RGBA, resampled horizontally then vertically
+	src := make([][4]float32, sw*sh)
+	for y := 0; y < sh; y++ {
+		for x := 0; x < sw; x++ {
+			r, g, bl, a := img.At(b.Min.X+x, b.Min.Y+y).RGBA()
+			src[y*sw+x] = [4]float32{float32(r), float32(g), float32(bl), float32(a)}
+		}
+	}
+
+	xw := resampleWeights(width, sw)
+	tmp := make([][4]float32, width*sh)
+	for y := 0; y < sh; y++ {
+		for x, ws := range xw {
+			var p [4]float32
+			for _, w := range ws {
+				s := src[y*sw+w.index]
+				for c := range p {
+					p[c] += s[c] * w.weight
+				}
+			}
+			tmp[y*width+x] = p
+		}
+	}
+
+	yw := resampleWeights(height, sh)
+	for y, ws := range yw {
+		for x := 0; x < width; x++ {
+			var p [4]float32
+			for _, w := range ws {
+				s := tmp[w.index*width+x]
+				for c := range p {
+					p[c] += s[c] * w.weight
+				}
+			}
+			dst.SetNRGBA(x, y, unpremultiply(p))
+		}
+	}
+	return dst
+}
+
+func unpremultiply(p [4]float32) color.NRGBA {
+	a := p[3]
+	if a <= 0 {
+		return color.NRGBA{}
+	}
+	c := color.NRGBA{A: uint8(math.Min(float64(a)/257+0.5, 255))}
+	c.R = uint8(math.Min(float64(p[0]/a)*255+0.5, 255))
+	c.G = uint8(math.Min(float64(p[1]/a)*255+0.5, 255))
+	c.B = uint8(math.Min(float64(p[2]/a)*255+0.5, 255))
+	return c
+}
+
+// RoundCorners masks img with anti-aliased rounded corners. radius is
+// relative to the shorter side; use IosCornerRadius for iOS-style icons.
+func RoundCorners(img image.Image, radius float64) *image.NRGBA {
+	b := img.Bounds()
+	w, h := float64(b.Dx()), float64(b.Dy())
+	r := radius * math.Min(w, h)
+	dst := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Src)
+
+	hw, hh := w/2, h/2
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			// signed distance from the pixel center to the rounded rectangle
+			qx := math.Abs(float64(x)+0.5-hw) - (hw - r)
+			qy := math.Abs(float64(y)+0.5-hh) - (hh - r)
+			d := math.Hypot(math.Max(qx, 0), math.Max(qy, 0)) + math.Min(math.Max(qx, qy), 0) - r
+			coverage := math.Max(0, math.Min(1, 0.5-d))
+			if coverage < 1 {
+				i := dst.PixOffset(x, y) + 3
+				dst.Pix[i] = uint8(float64(dst.Pix[i])*coverage + 0.5)
+			}
+		}
+	}
+	return dst
+}
+
+// DominantColor returns the most common color of the opaque pixels of img,
+// ignoring small variations, or a zero color if img is fully transparent.
+func DominantColor(img image.Image) color.NRGBA {
+	small := ResizeIcon(img, 32, 32)
+
+	// 4 bits per channel buckets, summing the pixels in each
+	type bucket struct{ n, r, g, b int }
+	var buckets [4096]bucket
+	var best *bucket
+	for i := 0; i < len(small.Pix); i += 4 {
+		p := small.Pix[i : i+4]
+		if p[3] < 128 {
+			continue
+		}
+		k := &buckets[int(p[0]>>4)<<8|int(p[1]>>4)<<4|int(p[2]>>4)]
+		k.n++
+		k.r += int(p[0])
+		k.g += int(p[1])
+		k.b += int(p[2])
+		if best == nil || k.n > best.n {
+			best = k
+		}
+	}
+	if best == nil {
+		return color.NRGBA{}
+	}
+	return color.NRGBA{uint8(best.r / best.n), uint8(best.g / best.n), uint8(best.b / best.n), 255}
+}
+
+func (info *AppInfo) setIcon(icon image.Image) {
+	info.Icon = icon
+	if icon != nil {
+		c := DominantColor(icon)
+		info.IconColor = fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+}
@@ -0,0 +1,113 @@
+package appfile
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"testing"
+)
+
+func uniformImage(w, h int, c color.Color) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), image.NewUniform(c), image.ZP, draw.Src)
+	return img
+}
+
+func TestResizeIcon(t *testing.T) {
+	red := color.NRGBA{R: 255, A: 255}
+	for _, size := range [][2]int{{48, 48}, {29, 40}, {300, 300}} {
+		img := ResizeIcon(uniformImage(120, 120, red), size[0], size[1])
+		if img.Bounds().Dx() != size[0] || img.Bounds().Dy() != size[1] {
+			t.Errorf("got %v want %v", img.Bounds().Size(), size)
+		}
+		if c := img.NRGBAAt(size[0]/2, size[1]/2); c != red {
+			t.Errorf("got %v want %v", c, red)
+		}
+	}
+}
+
+func TestResizeIconAverages(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	img.SetNRGBA(0, 0, color.NRGBA{A: 255})
+	img.SetNRGBA(1, 0, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+
+	c := ResizeIcon(img, 1, 1).NRGBAAt(0, 0)
+	if c.R < 126 || c.R > 129 || c.A != 255 {
+		t.Errorf("got %v want mid gray", c)
+	}
+}
+
+func TestRoundCorners(t *testing.T) {
+	img := RoundCorners(uniformImage(100, 100, color.NRGBA{B: 255, A: 255}), IosCornerRadius)
+	if a := img.NRGBAAt(0, 0).A; a != 0 {
+		t.Errorf("got %v want %v", a, 0)
+	}
+	if a := img.NRGBAAt(50, 50).A; a != 255 {
+		t.Errorf("got %v want %v", a, 255)
+	}
+	if a := img.NRGBAAt(50, 0).A; a != 255 {
+		t.Errorf("got %v want %v", a, 255)
+	}
+	if a := img.NRGBAAt(6, 6).A; a == 0 || a == 255 {
+		t.Errorf("got %v want partial coverage", a)
+	}
+}
+
+func TestEncodeIcon(t *testing.T) {
+	img := uniformImage(8, 8, color.NRGBA{G: 255, A: 128})
+
+	b, err := IconBytes(img, "png")
+	if err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	if _, err := png.Decode(bytes.NewReader(b)); err != nil {
+		t.Errorf("got %v want no error", err)
+	}
+
+	b, err = IconBytes(img, "JPG")
+	if err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(b)); err != nil {
+		t.Errorf("got %v want no error", err)
+	}
+
+	if _, err := IconBytes(img, "webp"); err != ErrUnsupportedFormat {
+		t.Errorf("got %v want %v", err, ErrUnsupportedFormat)
+	}
+	RegisterIconEncoder("webp", func(w io.Writer, img image.Image) error {
+		_, err := w.Write([]byte("RIFF"))
+		return err
+	})
+	defer func() {
+		iconEncodersMu.Lock()
+		delete(iconEncoders, "webp")
+		iconEncodersMu.Unlock()
+	}()
+	if b, err := IconBytes(img, "webp"); err != nil || string(b) != "RIFF" {
+		t.Errorf("got %q, %v want %q", b, err, "RIFF")
+	}
+}
+
+func TestDominantColor(t *testing.T) {
+	blue := color.NRGBA{B: 200, A: 255}
+	img := RoundCorners(uniformImage(64, 64, blue), IosCornerRadius)
+	draw.Draw(img, image.Rect(0, 0, 16, 16), image.NewUniform(color.White), image.ZP, draw.Src)
+
+	if c := DominantColor(img); c != blue {
+		t.Errorf("got %v want %v", c, blue)
+	}
+	if c := DominantColor(image.NewNRGBA(image.Rect(0, 0, 4, 4))); c != (color.NRGBA{}) {
+		t.Errorf("got %v want %v", c, color.NRGBA{})
+	}
+
+	info := new(AppInfo)
+	info.setIcon(img)
+	if info.IconColor != "#0000c8" {
+		t.Errorf("got %v want %v", info.IconColor, "#0000c8")
+	}
+}
@@ -0,0 +1,129 @@
+package appfile
+
+import (
+	"archive/zip"
+	"image"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/andrianbdn/iospng"
+)
+
+// ExtractIcon writes the app icon to w in format, e.g. "png". An empty
+// format, or the format the icon is stored in, copies the file without
+// decoding it; iOS icons are converted from Apple's optimized PNG on the
+// fly. Only .apk and .ipa files are supported.
+func (p *Parser) ExtractIcon(w io.Writer, format string) (err error) {
+	defer recoverPanic(&err)
+	f, optimized, err := p.iconFile()
+	if err != nil {
+		return err
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return writeImage(f.Name, rc, optimized, w, format)
+}
+
+// writeImage writes the image file name read from r to w in format.
+func writeImage(name string, r io.Reader, optimized bool, w io.Writer, format string) error {
+	if optimized {
+		src := r
+		pr, pw := io.Pipe()
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			var err error
+			defer func() { pw.CloseWithError(err) }()
+			defer recoverPanic(&err)
+			err = iospng.PngRevertOptimization(src, pw)
+		}()
+		defer func() {
+			pr.Close()
+			<-done
+		}()
+		r = pr
+	}
+
+	format = strings.ToLower(format)
+	if format == "jpg" {
+		format = "jpeg"
+	}
+	stored := strings.ToLower(strings.TrimPrefix(path.Ext(name), "."))
+	if stored == "jpg" {
+		stored = "jpeg"
+	}
+	if format == "" || format == stored {
+		_, err := io.Copy(w, r)
+		return err
+	}
+
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return err
+	}
+	return EncodeIcon(w, img, format)
+}
+
+// Icon decodes the app icon. Only .apk and .ipa files are supported.
+func (p *Parser) Icon() (_ image.Image, err error) {
+	defer recoverPanic(&err)
+	f, optimized, err := p.iconFile()
+	if err != nil {
+		return nil, err
+	}
+	if optimized {
+		return parseIpaIcon(f)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	img, _, err := image.Decode(rc)
+	return img, err
+}
+
+// SaveIcon writes the app icon to the file name, in the format its
+// extension names.
+func (p *Parser) SaveIcon(name string) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	err = p.ExtractIcon(f, strings.TrimPrefix(filepath.Ext(name), "."))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(name)
+	}
+	return err
+}
+
+// iconFile returns the icon of the archive and whether it is an optimized
+// iOS PNG.
+func (p *Parser) iconFile() (*zip.File, bool, error) {
+	switch strings.ToLower(filepath.Ext(p.name)) {
+	case androidExt:
+		manifest, err := p.androidManifest()
+		if err != nil {
+			return nil, false, err
+		}
+		if f := p.resources().imageFile(manifest.Application.Icon); f != nil {
+			return f, false, nil
+		}
+	case iosExt:
+		for _, f := range p.reader.File {
+			if strings.Contains(f.Name, "AppIcon60x60") {
+				return f, true, nil
+			}
+		}
+	}
+	return nil, false, ErrNoIcon
+}
@@ -0,0 +1,84 @@
+package appfile
+
+import "strings"
+
+// SigningIdentity is who signs the Android and iOS builds of one app: the
+// SHA-256 fingerprint of the APK signing certificate and the Apple Team
+// ID. Tracked across releases, it shows when ownership of either platform
+// moves, e.g. to another team or a new upload key.
+type SigningIdentity struct {
+	AndroidSigner string `json:"android_signer,omitempty"`
+	IosTeamId     string `json:"ios_team_id,omitempty"`
+}
+
+// NewSigningIdentity returns the identity of the Android and iOS builds
+// of one app. Either may be nil, which leaves that platform unknown.
+func NewSigningIdentity(android, ios *AppInfo) SigningIdentity {
+	var id SigningIdentity
+	if android != nil {
+		id.AndroidSigner = AndroidSigningIdentity(android).AndroidSigner
+	}
+	if ios != nil {
+		id.IosTeamId = IosSigningIdentity(ios).IosTeamId
+	}
+	return id
+}
+
+// AndroidSigningIdentity returns the identity with the fingerprint of the
+// signer certificate of info.
+func AndroidSigningIdentity(info *AppInfo) SigningIdentity {
+	var signer string
+	if info.Android != nil && info.Android.Signer != nil {
+		signer = info.Android.Signer.SHA256
+	}
+	return ParseSigningIdentity(signer, "")
+}
+
+// IosSigningIdentity returns the identity with the Team ID of the profile
+// of info.
+func IosSigningIdentity(info *AppInfo) SigningIdentity {
+	var teamId string
+	if info.Ios != nil && info.Ios.Profile != nil {
+		teamId = info.Ios.Profile.TeamId
+	}
+	return ParseSigningIdentity("", teamId)
+}
+
+// ParseSigningIdentity returns the canonical identity of an Android signer
+// fingerprint and a Team ID, so identities can be compared with ==. The
+// fingerprint may be in any of the usual notations, such as the SHA256 of
+// a Certificate, "AA:BB:…" as printed by keytool or lower case hex as
+// printed by apksigner. An empty argument leaves that platform unknown.
+func ParseSigningIdentity(androidSigner, teamId string) SigningIdentity {
+	fingerprint := strings.NewReplacer(":", "", " ", "").Replace(androidSigner)
+	return SigningIdentity{
+		AndroidSigner: strings.ToUpper(fingerprint),
+		IosTeamId:     strings.ToUpper(strings.TrimSpace(teamId)),
+	}
+}
+
+// SigningIdentityChange flags the platforms whose signer differs between
+// two releases.
+type SigningIdentityChange struct {
+	Prev    SigningIdentity `json:"prev"`
+	Next    SigningIdentity `json:"next"`
+	Android bool            `json:"android"`
+	Ios     bool            `json:"ios"`
+}
+
+// DiffSigningIdentity compares the identities of two releases of an app.
+// A platform unknown in either release is not flagged.
+func DiffSigningIdentity(prev, next SigningIdentity) *SigningIdentityChange {
+	changed := func(a, b string) bool { return a != "" && b != "" && a != b }
+	return &SigningIdentityChange{
+		Prev:    prev,
+		Next:    next,
+		Android: changed(prev.AndroidSigner, next.AndroidSigner),
+		Ios:     changed(prev.IosTeamId, next.IosTeamId),
+	}
+}
+
+// Empty reports whether neither signer changed.
+func (c *SigningIdentityChange) Empty() bool {
+	return !c.Android && !c.Ios
+}
@@ -0,0 +1,34 @@
+package appfile
+
+import "testing"
+
+func TestSigningIdentity(t *testing.T) {
+	got := ParseSigningIdentity("ab:cd:ef", " ab12cd34ef ")
+	if want := (SigningIdentity{AndroidSigner: "ABCDEF", IosTeamId: "AB12CD34EF"}); got != want {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+	if got != ParseSigningIdentity("ABCDEF", "AB12CD34EF") {
+		t.Errorf("got %+v want equal identities", got)
+	}
+
+	apk := &AppInfo{Android: &AndroidInfo{Signer: &Certificate{SHA256: "ABCDEF"}}}
+	ipa := &AppInfo{Ios: &IosInfo{Profile: &ProvisioningProfile{TeamId: "AB12CD34EF"}}}
+	if id := NewSigningIdentity(apk, ipa); id != got {
+		t.Errorf("got %+v want %+v", id, got)
+	}
+	if id := NewSigningIdentity(apk, nil); id != (SigningIdentity{AndroidSigner: "ABCDEF"}) {
+		t.Errorf("got %+v want the android signer only", id)
+	}
+	if id := IosSigningIdentity(apk); id != (SigningIdentity{}) {
+		t.Errorf("got %+v want an unknown identity", id)
+	}
+
+	next := ParseSigningIdentity("abcdef", "ZZ99YY88XX")
+	d := DiffSigningIdentity(got, next)
+	if d.Android || !d.Ios || d.Empty() {
+		t.Errorf("got %+v want ios change", d)
+	}
+	if d := DiffSigningIdentity(got, ParseSigningIdentity("", "AB12CD34EF")); !d.Empty() {
+		t.Errorf("got %+v want empty", d)
+	}
+}
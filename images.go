@@ -0,0 +1,122 @@
+package appfile
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"io"
+	"path"
+	"strings"
+)
+
+// Kinds of ImageFile.
+const (
+	ImageLaunch     = "launch"     // launch images and splash screens
+	ImageScreenshot = "screenshot" // marketing screenshots bundled with the app
+)
+
+// ImageFile is an image other than the icon found in the archive.
+type ImageFile struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"` // the entry, for ExtractImage
+	Size int64  `json:"size"`
+}
+
+// Images lists the launch images and marketing screenshots under the
+// paths apps and build tools put them: legacy LaunchImage and Default
+// PNGs and the images of the launch storyboard of an .ipa, splash and
+// launch_screen drawables of an .apk, and directories named screenshots
+// or ending in it, like fastlane's phoneScreenshots. Images of asset
+// catalogs are compiled into Assets.car and not listed.
+func (p *Parser) Images() []ImageFile {
+	var launchDir string
+	if name := p.launchStoryboard(); name != "" {
+		launchDir = name + ".storyboardc/"
+	}
+	var images []ImageFile
+	for _, f := range p.reader.File {
+		if kind := imageKind(f.Name, launchDir); kind != "" {
+			images = append(images, ImageFile{Kind: kind, Name: f.Name, Size: int64(f.UncompressedSize64)})
+		}
+	}
+	return images
+}
+
+func (p *Parser) launchStoryboard() (name string) {
+	// An Info.plist the decoder panics on leaves the storyboard unlisted.
+	defer func() {
+		if recover() != nil {
+			name = ""
+		}
+	}()
+	var f *zip.File
+	for _, file := range p.reader.File {
+		if reInfoPlist.MatchString(file.Name) {
+			f = file
+			break
+		}
+	}
+	if f == nil {
+		return ""
+	}
+	var plist struct {
+		UILaunchStoryboardName string `plist:"UILaunchStoryboardName"`
+	}
+	if readZipPlist(f, &plist) != nil || plist.UILaunchStoryboardName == "" {
+		return ""
+	}
+	return path.Dir(f.Name) + "/" + plist.UILaunchStoryboardName
+}
+
+func imageKind(name, launchDir string) string {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".png", ".jpg", ".jpeg", ".webp":
+	default:
+		return ""
+	}
+	dir, base := path.Split(name)
+	for _, d := range strings.Split(strings.ToLower(dir), "/") {
+		if strings.HasSuffix(d, "screenshots") {
+			return ImageScreenshot
+		}
+	}
+
+	base = strings.ToLower(base)
+	switch {
+	case launchDir != "" && strings.HasPrefix(name, launchDir):
+		return ImageLaunch
+	case strings.HasPrefix(name, "Payload/") && strings.Count(name, "/") == 2 &&
+		(strings.HasPrefix(base, "launchimage") || strings.HasPrefix(base, "default")):
+		return ImageLaunch
+	case strings.HasPrefix(name, "res/") &&
+		(strings.HasPrefix(base, "splash") || strings.HasPrefix(base, "launch_screen") || strings.HasPrefix(base, "launch_image")):
+		return ImageLaunch
+	}
+	return ""
+}
+
+// ExtractImage writes the image name of Images to w in format, as
+// ExtractIcon does.
+func (p *Parser) ExtractImage(name string, w io.Writer, format string) (err error) {
+	defer recoverPanic(&err)
+	for _, f := range p.reader.File {
+		if f.Name == name {
+			return extractImage(f, w, format)
+		}
+	}
+	return ErrNoEntry
+}
+
+// extractImage converts Apple's optimized PNGs, which Xcode writes for the
+// PNGs of a bundle, as ExtractIcon does.
+func extractImage(f *zip.File, w io.Writer, format string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	br := bufio.NewReader(rc)
+	head, _ := br.Peek(16)
+	optimized := len(head) == 16 && bytes.Equal(head[12:], []byte("CgBI"))
+	return writeImage(f.Name, br, optimized, w, format)
+}
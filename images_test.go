@@ -0,0 +1,75 @@
+package appfile
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/follyxing/appfile-info/fixture"
+)
+
+func TestImages(t *testing.T) {
+	var img bytes.Buffer
+	png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 2, 4)))
+	name := filepath.Join(t.TempDir(), "test.ipa")
+	writeZip(t, name, map[string][]byte{
+		"Payload/Example.app/Info.plist": fixture.Plist(map[string]interface{}{
+			"CFBundleIdentifier":     "com.example.app",
+			"UILaunchStoryboardName": "LaunchScreen",
+		}),
+		"Payload/Example.app/LaunchImage-568h@2x.png":                  img.Bytes(),
+		"Payload/Example.app/LaunchScreen.storyboardc/splash.png":      img.Bytes(),
+		"Payload/Example.app/Screenshots/home.jpg":                     img.Bytes(),
+		"Payload/Example.app/Main.storyboardc/background.png":          img.Bytes(),
+		"Payload/Example.app/PlugIns/Widget.appex/Default.png":         img.Bytes(),
+		"Payload/Example.app/fastlane/phoneScreenshots/en-US/1_en.png": img.Bytes(),
+	})
+
+	p, err := OpenParser(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	var got []string
+	for _, f := range p.Images() {
+		got = append(got, f.Kind+" "+f.Name)
+	}
+	want := []string{
+		"launch Payload/Example.app/LaunchImage-568h@2x.png",
+		"launch Payload/Example.app/LaunchScreen.storyboardc/splash.png",
+		"screenshot Payload/Example.app/Screenshots/home.jpg",
+		"screenshot Payload/Example.app/fastlane/phoneScreenshots/en-US/1_en.png",
+	}
+	sort.Strings(got)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	var out bytes.Buffer
+	if err := p.ExtractImage("Payload/Example.app/LaunchImage-568h@2x.png", &out, ""); err != nil || !bytes.Equal(out.Bytes(), img.Bytes()) {
+		t.Errorf("got %d bytes, %v want %d bytes", out.Len(), err, img.Len())
+	}
+	if err := p.ExtractImage("Payload/Example.app/missing.png", &out, ""); err != ErrNoEntry {
+		t.Errorf("got %v want %v", err, ErrNoEntry)
+	}
+}
+
+func TestImageKind(t *testing.T) {
+	for name, want := range map[string]string{
+		"res/drawable-xxhdpi-v4/splash.png":            ImageLaunch,
+		"res/drawable/launch_screen.webp":              ImageLaunch,
+		"assets/screenshots/tablet.png":                ImageScreenshot,
+		"res/drawable-xxhdpi-v4/ic_launcher.png":       "",
+		"assets/screenshots/readme.txt":                "",
+		"Payload/Example.app/LaunchScreen.storyboardc": "",
+	} {
+		if got := imageKind(name, ""); got != want {
+			t.Errorf("%s: got %q want %q", name, got, want)
+		}
+	}
+}
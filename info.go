@@ -0,0 +1,668 @@
+package appfile
+
+import (
+	"fmt"
+	"image"
+	"time"
+)
+
+// SchemaVersion is the version of the AppInfo model. It is bumped whenever
+// a field is removed or changes meaning; new fields do not bump it.
+const SchemaVersion = 2
+
+const (
+	PlatformAndroid = "android"
+	PlatformIOS     = "ios"
+	PlatformTizen   = "tizen"
+	PlatformWeb     = "web"
+	PlatformDebian  = "debian"
+)
+
+// AppInfo holds the metadata shared by all platforms. Exactly one of
+// Android, Ios, Tizen, Web and Debian is set, depending on Platform.
+type AppInfo struct {
+	SchemaVersion int         `json:"schema_version"`
+	Platform      string      `json:"platform"`
+	Name          string      `json:"name"`
+	BundleId      string      `json:"bundle_id"`
+	Version       string      `json:"version"`
+	Build         string      `json:"build"`
+	Icon          image.Image `json:"-"`
+	IconColor     string      `json:"icon_color,omitempty"` // dominant icon color, #rrggbb
+	Size          int64       `json:"size"`
+	Environment   string      `json:"environment"` // debug, staging or production, see BuildEnvironment
+	// Category is the android:appCategory, e.g. productivity, or the iOS
+	// LSApplicationCategoryType, e.g. public.app-category.games.
+	Category    string `json:"category,omitempty"`
+	Description string `json:"description,omitempty"` // android:description
+	// Container is the path of the package within the .zip or .tar.gz it
+	// was found in.
+	Container string `json:"container,omitempty"`
+	// Role and Parent relate the packages of an archive read with
+	// ParseCatalog: Parent is the BundleId of the primary app a companion,
+	// App Clip or test belongs to.
+	Role   string `json:"role,omitempty"`
+	Parent string `json:"parent,omitempty"`
+
+	Android *AndroidInfo `json:"android,omitempty"`
+	Ios     *IosInfo     `json:"ios,omitempty"`
+	Tizen   *TizenInfo   `json:"tizen,omitempty"`
+	Web     *WebInfo     `json:"web,omitempty"`
+	Debian  *DebianInfo  `json:"debian,omitempty"`
+
+	// Library is set for library artifacts rather than apps.
+	Library *LibraryInfo `json:"library,omitempty"`
+	// Test is set for test artifacts, which do not belong in a release
+	// catalog.
+	Test *TestInfo `json:"test,omitempty"`
+
+	GoogleServices *GoogleServices `json:"google_services,omitempty"`
+	Hybrid         *HybridInfo     `json:"hybrid,omitempty"`
+	Integrity      *IntegrityInfo  `json:"integrity,omitempty"`
+	Billing        *BillingInfo    `json:"billing,omitempty"`
+	Tracking       *TrackingInfo   `json:"tracking,omitempty"`
+
+	// Provenance holds the results of the verifiers registered with
+	// WithProvenanceVerifier, in order.
+	Provenance []Provenance `json:"provenance,omitempty"`
+
+	// Hosts lists the hosts of URLs in the app, see WithURLScan.
+	Hosts []string `json:"hosts,omitempty"`
+
+	// Warnings describes problems found in an artifact that parsed, such
+	// as packaging a store would reject.
+	Warnings []string `json:"warnings,omitempty"`
+
+	// Extras carries metadata without a dedicated field, keyed by a
+	// namespaced name such as "vendor.build_id".
+	Extras map[string]interface{} `json:"extras,omitempty"`
+}
+
+func newAppInfo(platform string) *AppInfo {
+	info := &AppInfo{SchemaVersion: SchemaVersion, Platform: platform}
+	switch platform {
+	case PlatformAndroid:
+		info.Android = new(AndroidInfo)
+	case PlatformIOS:
+		info.Ios = new(IosInfo)
+	case PlatformTizen:
+		info.Tizen = new(TizenInfo)
+	case PlatformWeb:
+		info.Web = new(WebInfo)
+	case PlatformDebian:
+		info.Debian = new(DebianInfo)
+	}
+	return info
+}
+
+func (info *AppInfo) warn(format string, args ...interface{}) {
+	info.Warnings = append(info.Warnings, fmt.Sprintf(format, args...))
+}
+
+// SetExtra stores v under key in Extras.
+func (info *AppInfo) SetExtra(key string, v interface{}) {
+	if info.Extras == nil {
+		info.Extras = make(map[string]interface{})
+	}
+	info.Extras[key] = v
+}
+
+type AndroidInfo struct {
+	Debug            bool     `json:"debug"`
+	MinSdkVersion    string   `json:"min_sdk_version,omitempty"`
+	TargetSdkVersion string   `json:"target_sdk_version,omitempty"`
+	Permissions      []string `json:"permissions,omitempty"`
+	// DefinedPermissions are the <permission> elements the app declares.
+	// Other apps need signature ones to be signed with the same key.
+	DefinedPermissions []Permission `json:"defined_permissions,omitempty"`
+	// RequiredFeatures and OptionalFeatures are the <uses-feature> names;
+	// Google Play hides the app from devices lacking a required one.
+	RequiredFeatures []string `json:"required_features,omitempty"`
+	OptionalFeatures []string `json:"optional_features,omitempty"`
+	// ABIs are the lib/<abi> directories holding native libraries.
+	ABIs             []string `json:"abis,omitempty"`
+	MainActivity     string   `json:"main_activity,omitempty"`
+	ApplicationClass string   `json:"application_class,omitempty"`
+	ProcessName      string   `json:"process_name,omitempty"`
+	LabelSource      string   `json:"label_source,omitempty"` // where Name came from, see LabelSourceManifest
+
+	// SharedUserId changes upgrade behavior: adding, removing or changing
+	// it breaks updates of installed apps.
+	SharedUserId      string `json:"shared_user_id,omitempty"`
+	InstallLocation   string `json:"install_location,omitempty"` // auto, internalOnly, preferExternal
+	CompileSdkVersion string `json:"compile_sdk_version,omitempty"`
+
+	Backup *BackupInfo `json:"backup,omitempty"`
+
+	Resources *ResourceStats `json:"resources,omitempty"`
+	Screens   *ScreenSupport `json:"screens,omitempty"`
+
+	// RoundIcon is the android:roundIcon and Banner the android:banner
+	// drawable of TV apps, when they are bitmaps.
+	RoundIcon image.Image `json:"-"`
+	Banner    image.Image `json:"-"`
+
+	// ThemeColor is the colorPrimary of the application theme.
+	// NotificationIcon and NotificationColor are the FCM notification
+	// defaults from the manifest meta-data.
+	ThemeColor        string      `json:"theme_color,omitempty"`
+	NotificationIcon  image.Image `json:"-"`
+	NotificationColor string      `json:"notification_color,omitempty"`
+
+	// IsInstantApp is set for Google Play Instant builds, which must not be
+	// published to the standard track.
+	IsInstantApp bool `json:"instant_app"`
+
+	// FeatureModules lists the dynamic feature modules of .aab and .apks
+	// files.
+	FeatureModules []FeatureModule `json:"feature_modules,omitempty"`
+
+	// ExpansionFiles is set when the app bundles an OBB downloader and
+	// AssetDelivery when it uses Play Asset Delivery. AssetPacks lists the
+	// asset packs of .aab and .apks files.
+	ExpansionFiles bool     `json:"expansion_files"`
+	AssetDelivery  bool     `json:"asset_delivery"`
+	AssetPacks     []string `json:"asset_packs,omitempty"`
+
+	// Kotlin is set when the app contains Kotlin metadata. AndroidX and
+	// SupportLibrary tell which of the two Jetpack namespaces it uses; apps
+	// in the middle of a migration have both.
+	Kotlin         bool `json:"kotlin"`
+	AndroidX       bool `json:"androidx"`
+	SupportLibrary bool `json:"support_library"`
+
+	Visibility *PackageVisibility `json:"visibility,omitempty"`
+	Wear       *WearInfo          `json:"wear,omitempty"`
+	Auto       *AutoInfo          `json:"auto,omitempty"`
+
+	// Widgets, Shortcuts and Tiles are the app widget receivers, the
+	// static shortcuts of shortcuts.xml and the Quick Settings tile
+	// services.
+	Widgets   []AppWidget         `json:"widgets,omitempty"`
+	Shortcuts []Shortcut          `json:"shortcuts,omitempty"`
+	Tiles     []QuickSettingsTile `json:"tiles,omitempty"`
+
+	// ForegroundServices are the services with a foregroundServiceType.
+	ForegroundServices []ForegroundService `json:"foreground_services,omitempty"`
+
+	Providers []ContentProvider `json:"providers,omitempty"`
+
+	// PrivilegedComponents are the accessibility services, device admin
+	// receivers and VPN services, which need a security review.
+	PrivilegedComponents []PrivilegedComponent `json:"privileged_components,omitempty"`
+
+	// NativeLibs are the ELF files under lib/. PageSize16K is set when
+	// every 64-bit library can load on devices with 16 KB pages, which
+	// Google Play requires for apps targeting Android 15 and later.
+	NativeLibs  []NativeLib `json:"native_libs,omitempty"`
+	PageSize16K bool        `json:"page_size_16k"`
+
+	// BaselineProfile is the ART profile format version of the baseline
+	// profile, e.g. 010, when the app ships one. StartupInitializers are
+	// the androidx.startup Initializer classes.
+	BaselineProfile     string   `json:"baseline_profile,omitempty"`
+	StartupInitializers []string `json:"startup_initializers,omitempty"`
+
+	// Obfuscated is a heuristic: at least half of the app's own classes
+	// have one or two letter names. Release builds without it were likely
+	// not minified.
+	Obfuscated bool `json:"obfuscated"`
+
+	// Signer is the certificate of the first signer, from the v3 or v2
+	// signature scheme block or else the v1 JAR signature.
+	Signer *Certificate `json:"signer,omitempty"`
+}
+
+// LibraryInfo describes an Android .aar or an Apple .framework or
+// .xcframework bundle. NativeLibs are the jni/<abi>/*.so files of an AAR
+// and Archs its ABIs, or the architectures of a framework.
+type LibraryInfo struct {
+	Kind       string         `json:"kind"` // aar, framework, xcframework
+	ClassesJar bool           `json:"classes_jar,omitempty"`
+	NativeLibs []string       `json:"native_libs,omitempty"`
+	Archs      []string       `json:"archs,omitempty"`
+	Slices     []LibrarySlice `json:"slices,omitempty"`
+}
+
+// TestInfo describes an Android instrumentation or test-only APK, or an
+// iOS XCTest runner. Runner and TargetPackage are the android:name and
+// android:targetPackage of the <instrumentation>; Bundles are the .xctest
+// bundles of a runner.
+type TestInfo struct {
+	Kind          string   `json:"kind"` // instrumentation, test_only, xctest
+	Runner        string   `json:"runner,omitempty"`
+	TargetPackage string   `json:"target_package,omitempty"`
+	Bundles       []string `json:"bundles,omitempty"`
+}
+
+// LibrarySlice is a library of an XCFramework, e.g. ios-arm64_x86_64-
+// simulator.
+type LibrarySlice struct {
+	Identifier string   `json:"identifier"`
+	Platform   string   `json:"platform"`          // ios, macos, tvos, watchos
+	Variant    string   `json:"variant,omitempty"` // simulator, maccatalyst
+	Path       string   `json:"path"`
+	Archs      []string `json:"archs"`
+}
+
+// HybridInfo describes an Expo, Capacitor or React Native app. OTAUpdates
+// is set when its Updater can download JavaScript updates from UpdateURL,
+// or the updater's service, without a store review. Expo only applies
+// updates built for the same RuntimeVersion; CodePush serves the
+// deployment its DeploymentKey names.
+type HybridInfo struct {
+	Framework      string `json:"framework"`             // expo, capacitor, react-native
+	Updater        string `json:"updater,omitempty"`     // expo-updates, codepush, appflow, capgo
+	SDKVersion     string `json:"sdk_version,omitempty"` // Expo SDK
+	RuntimeVersion string `json:"runtime_version,omitempty"`
+	OTAUpdates     bool   `json:"ota_updates"`
+	UpdateURL      string `json:"update_url,omitempty"`
+	Channel        string `json:"channel,omitempty"`
+	DeploymentKey  string `json:"deployment_key,omitempty"`
+}
+
+// IntegrityInfo lists the device attestation services an app calls, e.g.
+// play-integrity, and the root or jailbreak detection it ships, e.g.
+// rootbeer. Apps with Detection may not run on rooted test devices.
+type IntegrityInfo struct {
+	Attestation []string `json:"attestation,omitempty"`
+	Detection   []string `json:"detection,omitempty"`
+}
+
+// Provenance is the result of a ProvenanceVerifier.
+type Provenance struct {
+	Verifier string `json:"verifier"` // e.g. slsa or sigstore
+	Verified bool   `json:"verified"`
+	Builder  string `json:"builder,omitempty"` // e.g. the SLSA builder.id
+	Source   string `json:"source,omitempty"`  // e.g. the repository and commit built
+	Error    string `json:"error,omitempty"`   // why it is not verified
+}
+
+// BillingInfo is the in-app purchase capability of an app: the Android
+// com.android.vending.BILLING permission and the billing libraries it
+// ships, e.g. play-billing or storekit. LibraryVersion is the Play Billing
+// Library version.
+type BillingInfo struct {
+	Permission     bool     `json:"permission"`
+	Libraries      []string `json:"libraries,omitempty"`
+	LibraryVersion string   `json:"library_version,omitempty"`
+}
+
+// TrackingInfo is how an app identifies users for advertising, for
+// checking store privacy declarations. AdIDPermission is the Android
+// com.google.android.gms.permission.AD_ID, AdvertisingID is set when the
+// app reads the Android advertising ID or the iOS IDFA and ATT when it
+// requests App Tracking Transparency authorization. UsageDescription is
+// the NSUserTrackingUsageDescription.
+type TrackingInfo struct {
+	AdIDPermission   bool   `json:"ad_id_permission"`
+	AdvertisingID    bool   `json:"advertising_id"`
+	ATT              bool   `json:"att"`
+	UsageDescription string `json:"usage_description,omitempty"`
+}
+
+// GoogleServices is the Firebase project an app is configured for, from
+// google-services.json on Android and GoogleService-Info.plist on iOS. The
+// API key itself is not kept.
+type GoogleServices struct {
+	AppId         string `json:"app_id"`
+	ProjectId     string `json:"project_id,omitempty"`
+	ProjectNumber string `json:"project_number,omitempty"`
+	SenderId      string `json:"sender_id,omitempty"` // GCM/FCM sender id
+	ApiKey        bool   `json:"api_key"`
+	DatabaseURL   string `json:"database_url,omitempty"`
+	StorageBucket string `json:"storage_bucket,omitempty"`
+}
+
+// ScreenSupport holds <supports-screens> and <compatible-screens>. Apps
+// with compatible screens can only be installed on the listed size and
+// density pairs. DensitySplits are the densities of the configuration
+// splits of a .apks, or of the split an .apk is.
+type ScreenSupport struct {
+	Small                   bool               `json:"small"`
+	Normal                  bool               `json:"normal"`
+	Large                   bool               `json:"large"`
+	XLarge                  bool               `json:"xlarge"`
+	AnyDensity              bool               `json:"any_density"`
+	Resizeable              bool               `json:"resizeable"`
+	RequiresSmallestWidthDp int                `json:"requires_smallest_width_dp,omitempty"`
+	LargestWidthLimitDp     int                `json:"largest_width_limit_dp,omitempty"`
+	CompatibleScreens       []CompatibleScreen `json:"compatible_screens,omitempty"`
+	DensitySplits           []string           `json:"density_splits,omitempty"`
+}
+
+type CompatibleScreen struct {
+	Size    string `json:"size"`    // small, normal, large, xlarge
+	Density string `json:"density"` // ldpi, mdpi, ..., or the dpi
+}
+
+// ResourceStats summarizes resources.arsc. Types counts the resources of
+// each type, e.g. "string", and Configurations the distinct resource
+// configurations. FileSize is the uncompressed size of the files resources
+// reference, Size that plus TableSize.
+type ResourceStats struct {
+	Types          map[string]int `json:"types"`
+	Configurations int            `json:"configurations"`
+	Densities      []string       `json:"densities,omitempty"` // mdpi, xxhdpi, anydpi, ...
+	Locales        []string       `json:"locales,omitempty"`   // en, pt-rBR, ...
+	TableSize      int64          `json:"table_size"`
+	FileSize       int64          `json:"file_size"`
+	Size           int64          `json:"size"`
+}
+
+// BackupInfo describes what Android backs up. FullBackupContent and
+// DataExtractionRules are the rule files, or the raw manifest values when
+// they do not reference one.
+type BackupInfo struct {
+	AllowBackup         bool         `json:"allow_backup"`
+	FullBackupContent   string       `json:"full_backup_content,omitempty"`
+	DataExtractionRules string       `json:"data_extraction_rules,omitempty"`
+	Rules               []BackupRule `json:"rules,omitempty"`
+}
+
+type BackupRule struct {
+	Source       string `json:"source"` // full-backup-content, cloud-backup, device-transfer
+	Action       string `json:"action"` // include, exclude
+	Domain       string `json:"domain"`
+	Path         string `json:"path,omitempty"`
+	RequireFlags string `json:"require_flags,omitempty"`
+}
+
+// FeatureModule is a Play Feature Delivery module.
+type FeatureModule struct {
+	Name       string   `json:"name"`
+	Title      string   `json:"title,omitempty"`
+	Instant    bool     `json:"instant,omitempty"`
+	Delivery   []string `json:"delivery"` // install-time or conditional, and on-demand
+	Conditions []string `json:"conditions,omitempty"`
+}
+
+// TizenInfo describes a .tpk or .wgt package. Profile is the device
+// profile it targets, e.g. wearable.
+type TizenInfo struct {
+	ApiVersion string   `json:"api_version,omitempty"`
+	Profile    string   `json:"profile,omitempty"`
+	Privileges []string `json:"privileges,omitempty"`
+}
+
+// WebInfo describes a browser extension (.crx) or a progressive web app
+// manifest. The BundleId of extensions is their id, that of web apps the
+// manifest id or start_url.
+type WebInfo struct {
+	Extension       bool     `json:"extension"`
+	ManifestVersion int      `json:"manifest_version,omitempty"`
+	Permissions     []string `json:"permissions,omitempty"` // extension and host permissions
+
+	StartURL   string `json:"start_url,omitempty"`
+	Scope      string `json:"scope,omitempty"`
+	Display    string `json:"display,omitempty"` // fullscreen, standalone, minimal-ui, browser
+	ThemeColor string `json:"theme_color,omitempty"`
+}
+
+// DebianInfo holds control fields of a .deb package, a desktop Linux
+// build or a Cydia package for jailbroken devices (Architecture
+// iphoneos-arm).
+type DebianInfo struct {
+	Architecture string `json:"architecture,omitempty"`
+	Maintainer   string `json:"maintainer,omitempty"`
+	Section      string `json:"section,omitempty"`
+	Depends      string `json:"depends,omitempty"`
+}
+
+type IosInfo struct {
+	Profile *ProvisioningProfile `json:"profile,omitempty"`
+	// BundleProfiles are the profiles of app extensions, watch apps and
+	// App Clips.
+	BundleProfiles []BundleProfile `json:"bundle_profiles,omitempty"`
+
+	// The toolchain that built the app: Xcode is DTXcode as a version,
+	// e.g. 14.2, SDKName the SDK such as iphoneos16.2 and
+	// BuildMachineOSBuild the macOS build of the build machine.
+	Xcode               string `json:"xcode,omitempty"`
+	XcodeBuild          string `json:"xcode_build,omitempty"`
+	SDKName             string `json:"sdk_name,omitempty"`
+	PlatformVersion     string `json:"platform_version,omitempty"`
+	BuildMachineOSBuild string `json:"build_machine_os_build,omitempty"`
+
+	// Orientations are the UISupportedInterfaceOrientations, e.g.
+	// UIInterfaceOrientationPortrait. IpadOrientations are the ~ipad
+	// variant, which defaults to the same list.
+	Orientations       []string `json:"orientations,omitempty"`
+	IpadOrientations   []string `json:"ipad_orientations,omitempty"`
+	RequiresFullScreen bool     `json:"requires_full_screen"`
+	LaunchStoryboard   string   `json:"launch_storyboard,omitempty"`
+
+	// DeviceFamilies are the UIDeviceFamily values as device classes, see
+	// DevicePhone. RequiredCapabilities are the
+	// UIRequiredDeviceCapabilities, e.g. arm64 or nfc.
+	MinimumOSVersion     string   `json:"minimum_os_version,omitempty"`
+	DeviceFamilies       []string `json:"device_families,omitempty"`
+	RequiredCapabilities []string `json:"required_capabilities,omitempty"`
+
+	// AppClip is set for App Clips, whose Info.plist has NSAppClip.
+	// CompanionAppBundleId is the WKCompanionAppBundleIdentifier of watch
+	// apps, the iPhone app they belong to.
+	AppClip              bool   `json:"app_clip,omitempty"`
+	CompanionAppBundleId string `json:"companion_app_bundle_id,omitempty"`
+
+	// Executable is the CFBundleExecutable and ExecutableSize its size.
+	// ExecutableFound is set if it is a Mach-O binary in the .app.
+	Executable      string `json:"executable,omitempty"`
+	ExecutableSize  int64  `json:"executable_size,omitempty"`
+	ExecutableFound bool   `json:"executable_found"`
+
+	Binaries []IosBinary `json:"binaries,omitempty"`
+
+	// FairPlay is set for App Store purchased IPAs, which carry SC_Info
+	// sinf files or encrypted binaries. They cannot be re-signed or
+	// distributed.
+	FairPlay bool `json:"fairplay"`
+	// Simulator is set for builds for the Simulator, by DTPlatformName
+	// or the slices of the main executable, which do not install on
+	// devices.
+	Simulator bool `json:"simulator"`
+
+	// MacCatalyst is set for Mac Catalyst builds, by UIDeviceFamily 6, an
+	// LSMinimumSystemVersion, which is MinimumMacOSVersion, or the slices
+	// of the main executable. IpadOnMac is set for arm64 iPhone and iPad
+	// builds that Apple silicon Macs run as "Designed for iPad", unless
+	// they require capabilities Macs lack or the App Store Connect
+	// availability is turned off.
+	MacCatalyst         bool   `json:"mac_catalyst"`
+	MinimumMacOSVersion string `json:"minimum_macos_version,omitempty"`
+	IpadOnMac           bool   `json:"ipad_on_mac"`
+
+	// VisionOS is set for native Apple Vision Pro builds, by the xros
+	// DTPlatformName, UIDeviceFamily 7 or scene roles only visionOS has.
+	// DefaultSceneRole is the
+	// UIApplicationPreferredDefaultSceneSessionRole, e.g.
+	// UIWindowSceneSessionRoleVolumetricApplication; ImmersiveSpace is set
+	// if the scene manifest configures an immersive space.
+	VisionOS         bool   `json:"vision_os"`
+	DefaultSceneRole string `json:"default_scene_role,omitempty"`
+	ImmersiveSpace   bool   `json:"immersive_space"`
+
+	// SwiftSupport and Symbols are set when the IPA has the SwiftSupport/
+	// and Symbols/ folders App Store exports add.
+	SwiftSupport bool `json:"swift_support"`
+	Symbols      bool `json:"symbols"`
+
+	// OnDemandResources lists the On-Demand Resources tags, which are
+	// downloaded after install.
+	OnDemandResources []OnDemandTag `json:"on_demand_resources,omitempty"`
+}
+
+// OnDemandTag is an On-Demand Resources tag and the asset packs holding
+// its resources. Size is that of the packs embedded in the IPA; App Store
+// builds host them separately and report zero.
+type OnDemandTag struct {
+	Tag        string   `json:"tag"`
+	AssetPacks []string `json:"asset_packs"`
+	Size       int64    `json:"size"`
+}
+
+// AutoInfo is the car support of an app. Uses are the capabilities of
+// its Android Auto descriptor, e.g. media, template or sms; AutomotiveOS is
+// set when it declares android.hardware.type.automotive.
+// CarAppCategories are the Car App Library categories, e.g. navigation.
+type AutoInfo struct {
+	Descriptor       string   `json:"descriptor,omitempty"` // e.g. @xml/automotive_app_desc
+	Uses             []string `json:"uses,omitempty"`
+	AutomotiveOS     bool     `json:"automotive_os"`
+	CarAppCategories []string `json:"car_app_categories,omitempty"`
+	MinCarAPILevel   int      `json:"min_car_api_level,omitempty"`
+}
+
+// PackageVisibility is what other apps an app can see on Android 11 and
+// later: the Packages, Intents and provider authorities of its <queries>,
+// or every app with the QUERY_ALL_PACKAGES permission, which Google Play
+// restricts.
+type PackageVisibility struct {
+	QueryAllPackages bool          `json:"query_all_packages"`
+	Packages         []string      `json:"packages,omitempty"`
+	Intents          []QueryIntent `json:"intents,omitempty"`
+	Providers        []string      `json:"providers,omitempty"`
+}
+
+// QueryIntent is an <intent> of <queries>: apps handling Action with the
+// data.
+type QueryIntent struct {
+	Action     string   `json:"action"`
+	Categories []string `json:"categories,omitempty"`
+	Schemes    []string `json:"schemes,omitempty"`
+	Hosts      []string `json:"hosts,omitempty"`
+	MimeTypes  []string `json:"mime_types,omitempty"`
+}
+
+// WearInfo is how a Wear OS app installs. Standalone apps install on
+// watches without a phone app; the others RequiresCompanion. WatchFeature
+// is set when the app declares android.hardware.type.watch, WatchOnly when
+// it requires it, and WearableLibrary when it requires the
+// com.google.android.wearable shared library.
+type WearInfo struct {
+	Standalone        bool `json:"standalone"`
+	RequiresCompanion bool `json:"requires_companion"`
+	WatchFeature      bool `json:"watch_feature"`
+	WatchOnly         bool `json:"watch_only"`
+	WearableLibrary   bool `json:"wearable_library"`
+}
+
+// AppWidget is a home screen widget. Provider is its appwidget-provider
+// resource and Configure the activity run when it is added.
+type AppWidget struct {
+	Name        string `json:"name"`
+	Label       string `json:"label,omitempty"`
+	Description string `json:"description,omitempty"`
+	Provider    string `json:"provider,omitempty"`
+	Configure   string `json:"configure,omitempty"`
+}
+
+// Shortcut is a static launcher shortcut of Activity.
+type Shortcut struct {
+	Id         string `json:"id"`
+	ShortLabel string `json:"short_label,omitempty"`
+	LongLabel  string `json:"long_label,omitempty"`
+	Enabled    bool   `json:"enabled"`
+	Activity   string `json:"activity"`
+}
+
+// QuickSettingsTile is a TileService. Active tiles are only updated when
+// they request it; Toggleable tiles are on/off switches.
+type QuickSettingsTile struct {
+	Name       string `json:"name"`
+	Label      string `json:"label,omitempty"`
+	Toggleable bool   `json:"toggleable"`
+	Active     bool   `json:"active"`
+}
+
+// ForegroundService is a service and its foreground service types, e.g.
+// location or dataSync.
+type ForegroundService struct {
+	Name  string   `json:"name"`
+	Types []string `json:"types"`
+}
+
+// ContentProvider is a <provider>. Permission guards both reads and
+// writes; ReadPermission and WritePermission one of them.
+// GrantUriPermissions lets the app grant access to single URIs.
+type ContentProvider struct {
+	Name                string   `json:"name"`
+	Authorities         []string `json:"authorities"`
+	Exported            bool     `json:"exported"`
+	Permission          string   `json:"permission,omitempty"`
+	ReadPermission      string   `json:"read_permission,omitempty"`
+	WritePermission     string   `json:"write_permission,omitempty"`
+	GrantUriPermissions bool     `json:"grant_uri_permissions"`
+}
+
+// PrivilegedComponent is a component with a high-risk capability, see
+// ComponentAccessibility. Config is its configuration resource;
+// Capabilities are the can* flags of an accessibility service, or the
+// policies of a device admin, e.g. wipe-data. Packages limits an
+// accessibility service to the events of those apps.
+type PrivilegedComponent struct {
+	Kind         string   `json:"kind"`
+	Name         string   `json:"name"`
+	Label        string   `json:"label,omitempty"`
+	Permission   string   `json:"permission,omitempty"`
+	Config       string   `json:"config,omitempty"`
+	Description  string   `json:"description,omitempty"`
+	Capabilities []string `json:"capabilities,omitempty"`
+	Packages     []string `json:"packages,omitempty"`
+}
+
+// NativeLib is a native library of an APK. Size is uncompressed and
+// Needed lists the DT_NEEDED libraries it links against. Align is the
+// smallest alignment of its loadable segments; Stored libraries are
+// uncompressed and must also start on a 16 KB boundary within the APK.
+type NativeLib struct {
+	Path       string   `json:"path"`
+	ABI        string   `json:"abi"`
+	Size       int64    `json:"size"`
+	Soname     string   `json:"soname,omitempty"`
+	Needed     []string `json:"needed,omitempty"`
+	Stripped   bool     `json:"stripped"`
+	Is64Bit    bool     `json:"is_64bit"`
+	Align      uint64   `json:"align"`
+	Stored     bool     `json:"stored"`
+	Aligned16K bool     `json:"aligned_16k"`
+}
+
+// IosBinary is a Mach-O file of the app: the main executable, a framework,
+// dylib or app extension. Path is relative to the .app directory.
+type IosBinary struct {
+	Path      string   `json:"path"`
+	Archs     []string `json:"archs"`
+	Bitcode   bool     `json:"bitcode"`
+	Stripped  bool     `json:"stripped"`  // no local or debug symbols
+	Encrypted bool     `json:"encrypted"` // FairPlay encrypted
+	Simulator bool     `json:"simulator"` // x86_64 or arm64-simulator slices only
+	// MacCatalyst is set if all slices are built for Mac Catalyst.
+	MacCatalyst bool `json:"mac_catalyst"`
+}
+
+// BundleProfile is the provisioning profile of a bundle nested in the app.
+// Path is the bundle relative to the .app directory, e.g.
+// PlugIns/Widget.appex.
+type BundleProfile struct {
+	Path string `json:"path"`
+	*ProvisioningProfile
+}
+
+// ProvisioningProfile describes an embedded.mobileprovision.
+type ProvisioningProfile struct {
+	Name               string        `json:"name"`
+	UUID               string        `json:"uuid"`
+	TeamId             string        `json:"team_id,omitempty"`
+	TeamName           string        `json:"team_name,omitempty"`
+	Platform           []string      `json:"platform,omitempty"`
+	SigningType        string        `json:"signing_type"` // development, ad-hoc, enterprise, app-store
+	ExpirationDate     time.Time     `json:"expiration_date"`
+	Expired            bool          `json:"expired"` // at parse time, see WithClock
+	ProvisionedDevices []string      `json:"provisioned_devices,omitempty"`
+	Certificates       []Certificate `json:"certificates,omitempty"`
+
+	Entitlements map[string]interface{} `json:"entitlements,omitempty"`
+	// Data is the decoded profile plist.
+	Data []byte `json:"-"`
+}
@@ -0,0 +1,49 @@
+package appfile
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAppInfoJSON(t *testing.T) {
+	info := newAppInfo(PlatformAndroid)
+	info.BundleId = "com.example.helloworld"
+	info.Android.Permissions = []string{"android.permission.INTERNET"}
+	info.SetExtra("vendor.build_id", "1234")
+
+	b, err := json.Marshal(info)
+	if err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+
+	if m["schema_version"] != float64(SchemaVersion) {
+		t.Errorf("got %v want %v", m["schema_version"], SchemaVersion)
+	}
+	if m["bundle_id"] != "com.example.helloworld" {
+		t.Errorf("got %v want %v", m["bundle_id"], "com.example.helloworld")
+	}
+	if _, ok := m["ios"]; ok {
+		t.Errorf("got ios want omitted")
+	}
+	android, _ := m["android"].(map[string]interface{})
+	if perms, _ := android["permissions"].([]interface{}); len(perms) != 1 {
+		t.Errorf("got %v want %v", android["permissions"], info.Android.Permissions)
+	}
+	extras, _ := m["extras"].(map[string]interface{})
+	if extras["vendor.build_id"] != "1234" {
+		t.Errorf("got %v want %v", extras["vendor.build_id"], "1234")
+	}
+}
+
+func TestNewAppInfo(t *testing.T) {
+	if info := newAppInfo(PlatformIOS); info.Ios == nil || info.Android != nil {
+		t.Errorf("got %+v want only Ios set", info)
+	}
+	if info := newAppInfo(PlatformAndroid); info.Android == nil || info.Ios != nil {
+		t.Errorf("got %+v want only Android set", info)
+	}
+}
@@ -0,0 +1,44 @@
+package appfile
+
+// Attestation services and root or jailbreak detection SDKs, see
+// IntegrityInfo. DetectionCustom is a hand-written check for su binaries
+// or Cydia rather than a known SDK.
+const (
+	AttestationPlayIntegrity = "play-integrity"
+	AttestationSafetyNet     = "safetynet"
+	AttestationAppAttest     = "app-attest"
+	AttestationDeviceCheck   = "devicecheck"
+
+	DetectionRootBeer         = "rootbeer"
+	DetectionFreeRASP         = "freerasp"
+	DetectionJailMonkey       = "jail-monkey"
+	DetectionFlutterJailbreak = "flutter-jailbreak-detection"
+	DetectionIOSSecuritySuite = "ios-security-suite"
+	DetectionDTTJailbreak     = "dtt-jailbreak-detection"
+	DetectionCustom           = "custom"
+)
+
+// RootDetection reports whether the app likely refuses to run, or reports
+// the device, when it is rooted or jailbroken.
+func (i *IntegrityInfo) RootDetection() bool {
+	return i != nil && len(i.Detection) > 0
+}
+
+// newIntegrityInfo returns the integrity libraries of s, or nil. The
+// custom check is dropped when an SDK was found, since the SDKs do the
+// same checks.
+func newIntegrityInfo(s sdkScan) *IntegrityInfo {
+	i := &IntegrityInfo{Attestation: s[markerAttestation], Detection: s[markerDetection]}
+	if len(i.Detection) > 1 {
+		for n, d := range i.Detection {
+			if d == DetectionCustom {
+				i.Detection = append(i.Detection[:n], i.Detection[n+1:]...)
+				break
+			}
+		}
+	}
+	if len(i.Attestation) == 0 && len(i.Detection) == 0 {
+		return nil
+	}
+	return i
+}
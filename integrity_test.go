@@ -0,0 +1,74 @@
+package appfile
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestScanApkIntegrity(t *testing.T) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"classes.dex":  "dex\n035\x00Lcom/google/android/play/core/integrity/IntegrityManager;",
+		"classes2.dex": "dex\n035\x00Lcom/scottyab/rootbeer/RootBeer;/system/xbin/su",
+		"assets/a.txt": "Lcom/gantix/JailMonkey/JailMonkeyModule;",
+	} {
+		f, _ := w.Create(name)
+		f.Write([]byte(content))
+	}
+	w.Close()
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sdks, err := scanApkSDKs(reader.File)
+	if err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	got := newIntegrityInfo(sdks)
+	want := &IntegrityInfo{Attestation: []string{AttestationPlayIntegrity}, Detection: []string{DetectionRootBeer}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+	if !got.RootDetection() {
+		t.Errorf("got %v want %v", got.RootDetection(), true)
+	}
+}
+
+func TestScanIpaIntegrity(t *testing.T) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"Payload/App.app/App":                      "\xcf\xfa\xed\xfeDCAppAttestService/Applications/Cydia.app",
+		"Payload/App.app/Info.plist":               "IOSSecuritySuite",
+		"Payload/App.app/Frameworks/A.framework/A": "\xcf\xfa\xed\xfeDCDevice",
+	} {
+		f, _ := w.Create(name)
+		f.Write([]byte(content))
+	}
+	w.Close()
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sdks, err := scanIpaSDKs(reader.File)
+	if err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	got := newIntegrityInfo(sdks)
+	if !reflect.DeepEqual(got.Detection, []string{DetectionCustom}) {
+		t.Errorf("got %v want %v", got.Detection, []string{DetectionCustom})
+	}
+	if len(got.Attestation) != 2 {
+		t.Errorf("got %v want app-attest and devicecheck", got.Attestation)
+	}
+
+	var none *IntegrityInfo
+	if none.RootDetection() || newIntegrityInfo(sdkScan{}) != nil {
+		t.Errorf("got detection want none")
+	}
+}
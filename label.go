@@ -0,0 +1,52 @@
+package appfile
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/shogo82148/androidbinary"
+)
+
+// Where AppInfo.Name of an APK comes from.
+const (
+	LabelSourceManifest = "manifest" // a literal android:label
+	LabelSourceResource = "resource" // the label resource in the default configuration
+	LabelSourceLocale   = "locale"   // a localized variant of the label resource
+	LabelSourcePackage  = "package"  // the package name, for apps without a label
+)
+
+// apkLabel returns a literal android:label, or else the label resource in
+// the default configuration or any other, preferring English, and finally
+// the package name.
+func apkLabel(res *apkResources, manifest *androidManifest) (string, string) {
+	raw := manifest.Application.Label
+	if raw != "" && !androidbinary.IsResID(raw) {
+		return raw, LabelSourceManifest
+	}
+
+	if t, id, ok := res.lookup(raw); ok {
+		var locales []arscResource
+		for _, r := range t.resources[id] {
+			if r.config.isDefault() {
+				if s := t.resolveString(r.arscEntry); s != "" {
+					return s, LabelSourceResource
+				}
+			} else if r.config.locale() != "" {
+				locales = append(locales, r)
+			}
+		}
+		sort.SliceStable(locales, func(i, j int) bool {
+			li, lj := locales[i].config.locale(), locales[j].config.locale()
+			if ei, ej := strings.HasPrefix(li, "en"), strings.HasPrefix(lj, "en"); ei != ej {
+				return ei
+			}
+			return li < lj
+		})
+		for _, r := range locales {
+			if s := t.resolveString(r.arscEntry); s != "" {
+				return s, LabelSourceLocale
+			}
+		}
+	}
+	return manifest.Package, LabelSourcePackage
+}
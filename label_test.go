@@ -0,0 +1,52 @@
+package appfile
+
+import "testing"
+
+func TestApkLabel(t *testing.T) {
+	reader, err := getAppZipReader("testdata/helloworld.apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	xmlFile, err := getAndroidManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, manifest, err := parseApkFile(xmlFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := newApkResources(reader.File)
+
+	for _, tt := range []struct {
+		manifest *androidManifest
+		want     string
+		source   string
+	}{
+		{manifest, "HelloWorld", LabelSourceResource},
+		{&androidManifest{Package: "com.example", Application: androidApplication{Label: "Literal"}}, "Literal", LabelSourceManifest},
+		{&androidManifest{Package: "com.example", Application: androidApplication{Label: "@0x7F06FFFF"}}, "com.example", LabelSourcePackage},
+	} {
+		name, source := apkLabel(res, tt.manifest)
+		if name != tt.want || source != tt.source {
+			t.Errorf("got %v, %v want %v, %v", name, source, tt.want, tt.source)
+		}
+	}
+}
+
+func TestApkLabelLocale(t *testing.T) {
+	locale := func(l string) arscConfig {
+		return arscConfig{16, 0, 0, 0, 0, 0, 0, 0, l[0], l[1], 0, 0, 0, 0, 0, 0}
+	}
+	str := func(i uint32) *arscEntry { return &arscEntry{value: arscValue{typ: resValueString, data: i}} }
+	res := &apkResources{loaded: true, table: &arscTable{
+		strings: []string{"Hallo", "Hello"},
+		resources: map[uint32][]arscResource{
+			0x7f060000: {{locale("de"), str(0)}, {locale("en"), str(1)}},
+		},
+	}}
+	manifest := &androidManifest{Package: "com.example", Application: androidApplication{Label: "@0x7F060000"}}
+	name, source := apkLabel(res, manifest)
+	if name != "Hello" || source != LabelSourceLocale {
+		t.Errorf("got %v, %v want %v, %v", name, source, "Hello", LabelSourceLocale)
+	}
+}
@@ -0,0 +1,207 @@
+package appfile
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	aarExt         = ".aar"
+	frameworkExt   = ".framework"
+	xcframeworkExt = ".xcframework"
+)
+
+// Library kinds, see LibraryInfo.
+const (
+	LibraryAar         = "aar"
+	LibraryFramework   = "framework"
+	LibraryXCFramework = "xcframework"
+)
+
+type xcframeworkPlist struct {
+	AvailableLibraries []struct {
+		LibraryIdentifier        string   `plist:"LibraryIdentifier"`
+		LibraryPath              string   `plist:"LibraryPath"`
+		SupportedArchitectures   []string `plist:"SupportedArchitectures"`
+		SupportedPlatform        string   `plist:"SupportedPlatform"`
+		SupportedPlatformVariant string   `plist:"SupportedPlatformVariant"`
+	} `plist:"AvailableLibraries"`
+}
+
+// parseAar reads an Android library. Its manifest is plain XML, not the
+// binary XML of APKs.
+func parseAar(_ string, reader *zip.Reader, o *options) (*AppInfo, error) {
+	var errs stageErrors
+	end := o.startStage(StageManifest)
+	manifest, err := decodeAarManifest(reader)
+	end(err)
+	if err != nil {
+		errs.add(StageManifest, err)
+		return nil, errs.err()
+	}
+
+	info := newAndroidAppInfo(manifest)
+	info.Name = manifest.Package
+	info.Library = &LibraryInfo{Kind: LibraryAar}
+	for _, f := range reader.File {
+		switch parts := strings.Split(f.Name, "/"); {
+		case f.Name == "classes.jar":
+			info.Library.ClassesJar = true
+		case len(parts) == 3 && parts[0] == "jni" && path.Ext(parts[2]) == ".so":
+			info.Library.NativeLibs = append(info.Library.NativeLibs, f.Name)
+			info.Library.Archs = appendUnique(info.Library.Archs, parts[1])
+		}
+	}
+	return info, nil
+}
+
+func decodeAarManifest(reader *zip.Reader) (*androidManifest, error) {
+	f := findZipFile(reader.File, "AndroidManifest.xml")
+	if f == nil {
+		return nil, ErrNoManifest
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	manifest := new(androidManifest)
+	if err := xml.NewDecoder(rc).Decode(manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// parseFramework reads a .framework or .xcframework directory. iOS
+// frameworks are flat; macOS ones keep their files in Versions/Current.
+func parseFramework(name string, _ io.ReaderAt, _ int64, o *options) (*AppInfo, error) {
+	var errs stageErrors
+	info := newAppInfo(PlatformIOS)
+	info.Name = strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+	info.Library = new(LibraryInfo)
+
+	end := o.startStage(StageManifest)
+	var bin string
+	var err error
+	if filepath.Ext(name) == xcframeworkExt {
+		err = readXCFramework(name, info)
+	} else {
+		bin, err = readFramework(name, info)
+	}
+	end(err)
+	if err != nil {
+		errs.add(StageManifest, err)
+		return nil, errs.err()
+	}
+
+	if bin != "" {
+		end = o.startStage(StageBinary)
+		b, err := readMachOFile(bin)
+		end(err)
+		errs.add(StageBinary, err)
+		if b != nil {
+			info.Library.Archs = b.Archs
+		}
+	}
+
+	filepath.Walk(name, func(_ string, fi os.FileInfo, err error) error {
+		if err == nil && fi.Mode().IsRegular() {
+			info.Size += fi.Size()
+		}
+		return nil
+	})
+	return info, errs.err()
+}
+
+// readFramework returns the path of the framework binary.
+func readFramework(dir string, info *AppInfo) (string, error) {
+	plistName, binDir := filepath.Join(dir, "Info.plist"), dir
+	if _, err := os.Stat(plistName); os.IsNotExist(err) {
+		binDir = filepath.Join(dir, "Versions", "Current")
+		plistName = filepath.Join(binDir, "Resources", "Info.plist")
+	}
+	buf, err := ioutil.ReadFile(plistName)
+	if err != nil {
+		return "", err
+	}
+	p := new(iosPlist)
+	if err := decodePlist(buf, p); err != nil {
+		return "", entryError(plistName, err)
+	}
+
+	info.Library.Kind = LibraryFramework
+	if p.CFBundleName != "" {
+		info.Name = p.CFBundleName
+	}
+	info.BundleId = p.CFBundleIdentifier
+	info.Version = p.CFBundleShortVersion
+	info.Build = p.CFBundleVersion
+
+	exec := p.CFBundleExecutable
+	if exec == "" {
+		exec = strings.TrimSuffix(filepath.Base(dir), frameworkExt)
+	}
+	return filepath.Join(binDir, exec), nil
+}
+
+func readMachOFile(name string) (*IosBinary, error) {
+	buf, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return parseMachO(buf)
+}
+
+// readXCFramework lists the slices of an XCFramework. The bundle id and
+// version are those of its first framework slice.
+func readXCFramework(dir string, info *AppInfo) error {
+	buf, err := ioutil.ReadFile(filepath.Join(dir, "Info.plist"))
+	if err != nil {
+		return err
+	}
+	p := new(xcframeworkPlist)
+	if err := decodePlist(buf, p); err != nil {
+		return entryError(filepath.Join(dir, "Info.plist"), err)
+	}
+
+	info.Library.Kind = LibraryXCFramework
+	for _, l := range p.AvailableLibraries {
+		info.Library.Slices = append(info.Library.Slices, LibrarySlice{
+			Identifier: l.LibraryIdentifier,
+			Platform:   l.SupportedPlatform,
+			Variant:    l.SupportedPlatformVariant,
+			Path:       l.LibraryPath,
+			Archs:      l.SupportedArchitectures,
+		})
+		for _, a := range l.SupportedArchitectures {
+			info.Library.Archs = appendUnique(info.Library.Archs, a)
+		}
+
+		if info.BundleId != "" || path.Ext(l.LibraryPath) != frameworkExt {
+			continue
+		}
+		fp := new(iosPlist)
+		buf, err := ioutil.ReadFile(filepath.Join(dir, l.LibraryIdentifier, l.LibraryPath, "Info.plist"))
+		if err == nil && decodePlist(buf, fp) == nil {
+			info.BundleId = fp.CFBundleIdentifier
+			info.Version = fp.CFBundleShortVersion
+			info.Build = fp.CFBundleVersion
+		}
+	}
+	return nil
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
@@ -0,0 +1,155 @@
+package appfile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseAar(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "lib-release.aar")
+	writeZip(t, name, map[string][]byte{
+		"AndroidManifest.xml": []byte(`<?xml version="1.0" encoding="utf-8"?>
+<manifest xmlns:android="http://schemas.android.com/apk/res/android" package="com.example.lib">
+	<uses-sdk android:minSdkVersion="21" />
+	<uses-permission android:name="android.permission.INTERNET" />
+</manifest>`),
+		"classes.jar":                  []byte("PK"),
+		"jni/arm64-v8a/libnative.so":   nil,
+		"jni/armeabi-v7a/libnative.so": nil,
+		"res/values/values.xml":        nil,
+	})
+
+	info, err := NewAppParser(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Platform != PlatformAndroid || info.BundleId != "com.example.lib" || info.Android.MinSdkVersion != "21" {
+		t.Errorf("got %v %v %v", info.Platform, info.BundleId, info.Android.MinSdkVersion)
+	}
+	if !reflect.DeepEqual(info.Android.Permissions, []string{"android.permission.INTERNET"}) {
+		t.Errorf("got %v", info.Android.Permissions)
+	}
+	if info.Library == nil || info.Library.Kind != LibraryAar || !info.Library.ClassesJar {
+		t.Fatalf("got %+v", info.Library)
+	}
+	archs := map[string]bool{}
+	for _, a := range info.Library.Archs {
+		archs[a] = true
+	}
+	if len(info.Library.NativeLibs) != 2 || !archs["arm64-v8a"] || !archs["armeabi-v7a"] {
+		t.Errorf("got %v %v", info.Library.NativeLibs, info.Library.Archs)
+	}
+}
+
+const frameworkPlist = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>CFBundleExecutable</key>
+	<string>helloworld</string>
+	<key>CFBundleIdentifier</key>
+	<string>com.example.Kit</string>
+	<key>CFBundleName</key>
+	<string>Kit</string>
+	<key>CFBundleShortVersionString</key>
+	<string>3.1.0</string>
+	<key>CFBundleVersion</key>
+	<string>42</string>
+</dict>
+</plist>`
+
+func writeFramework(t *testing.T, dir string) {
+	reader, err := getAppZipReader("testdata/helloworld.ipa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bin, err := readZipFile(findZipFile(reader.File, "Payload/helloworld.app/helloworld"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "Info.plist"), []byte(frameworkPlist), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "helloworld"), bin, 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseFramework(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "Kit.framework")
+	writeFramework(t, name)
+
+	info, err := NewAppParser(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Name != "Kit" || info.BundleId != "com.example.Kit" || info.Version != "3.1.0" || info.Build != "42" {
+		t.Errorf("got %v %v %v %v", info.Name, info.BundleId, info.Version, info.Build)
+	}
+	if info.Library.Kind != LibraryFramework || !reflect.DeepEqual(info.Library.Archs, []string{"armv7"}) {
+		t.Errorf("got %+v", info.Library)
+	}
+	if info.Size == 0 {
+		t.Errorf("got %v want the bundle size", info.Size)
+	}
+}
+
+func TestParseXCFramework(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "Kit.xcframework")
+	writeFramework(t, filepath.Join(name, "ios-arm64", "Kit.framework"))
+	if err := ioutil.WriteFile(filepath.Join(name, "Info.plist"), []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict>
+	<key>AvailableLibraries</key>
+	<array>
+		<dict>
+			<key>LibraryIdentifier</key>
+			<string>ios-arm64</string>
+			<key>LibraryPath</key>
+			<string>Kit.framework</string>
+			<key>SupportedArchitectures</key>
+			<array><string>arm64</string></array>
+			<key>SupportedPlatform</key>
+			<string>ios</string>
+		</dict>
+		<dict>
+			<key>LibraryIdentifier</key>
+			<string>ios-arm64_x86_64-simulator</string>
+			<key>LibraryPath</key>
+			<string>Kit.framework</string>
+			<key>SupportedArchitectures</key>
+			<array><string>arm64</string><string>x86_64</string></array>
+			<key>SupportedPlatform</key>
+			<string>ios</string>
+			<key>SupportedPlatformVariant</key>
+			<string>simulator</string>
+		</dict>
+	</array>
+	<key>CFBundlePackageType</key>
+	<string>XFWK</string>
+</dict>
+</plist>`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := NewAppParser(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Name != "Kit" || info.BundleId != "com.example.Kit" || info.Version != "3.1.0" {
+		t.Errorf("got %v %v %v", info.Name, info.BundleId, info.Version)
+	}
+	if info.Library.Kind != LibraryXCFramework || !reflect.DeepEqual(info.Library.Archs, []string{"arm64", "x86_64"}) {
+		t.Errorf("got %+v", info.Library)
+	}
+	want := LibrarySlice{Identifier: "ios-arm64_x86_64-simulator", Platform: "ios", Variant: "simulator", Path: "Kit.framework", Archs: []string{"arm64", "x86_64"}}
+	if len(info.Library.Slices) != 2 || !reflect.DeepEqual(info.Library.Slices[1], want) {
+		t.Errorf("got %+v", info.Library.Slices)
+	}
+}
@@ -0,0 +1,45 @@
+package appfile
+
+import "strings"
+
+// macMissingCapabilities are UIRequiredDeviceCapabilities no Mac has,
+// which keep an iPhone or iPad app off Apple silicon Macs.
+var macMissingCapabilities = map[string]bool{
+	"telephony":     true,
+	"sms":           true,
+	"gps":           true,
+	"nfc":           true,
+	"arkit":         true,
+	"magnetometer":  true,
+	"gyroscope":     true,
+	"accelerometer": true,
+}
+
+// checkMac sets how an app runs on a Mac: as a Mac Catalyst build, or as
+// an iPhone or iPad app on Apple silicon.
+func checkMac(i *IosInfo) {
+	for _, f := range i.DeviceFamilies {
+		i.MacCatalyst = i.MacCatalyst || f == DeviceDesktop
+	}
+	i.MacCatalyst = i.MacCatalyst || i.MinimumMacOSVersion != ""
+
+	var arm64 bool
+	for _, b := range i.Binaries {
+		if strings.Contains(b.Path, "/") {
+			continue
+		}
+		i.MacCatalyst = i.MacCatalyst || b.MacCatalyst
+		for _, a := range b.Archs {
+			arm64 = arm64 || a == "arm64" || a == "arm64e"
+		}
+	}
+	if i.MacCatalyst || i.Simulator || !arm64 {
+		return
+	}
+	for _, c := range i.RequiredCapabilities {
+		if macMissingCapabilities[c] {
+			return
+		}
+	}
+	i.IpadOnMac = true
+}
@@ -0,0 +1,34 @@
+package appfile
+
+import "testing"
+
+func TestCheckMac(t *testing.T) {
+	catalyst, err := parseMachO(thinMachO(0x0100000c, machoPlatformMacCatalyst))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !catalyst.MacCatalyst {
+		t.Errorf("got %v want %v", catalyst.MacCatalyst, true)
+	}
+	device := IosBinary{Path: "App", Archs: []string{"arm64"}}
+
+	for _, tt := range []struct {
+		name               string
+		info               IosInfo
+		macCatalyst, onMac bool
+	}{
+		{"ipad app", IosInfo{DeviceFamilies: []string{DevicePhone, DeviceTablet}, Binaries: []IosBinary{device}}, false, true},
+		{"device family 6", IosInfo{DeviceFamilies: []string{DeviceTablet, DeviceDesktop}, Binaries: []IosBinary{device}}, true, false},
+		{"LSMinimumSystemVersion", IosInfo{MinimumMacOSVersion: "10.15", Binaries: []IosBinary{device}}, true, false},
+		{"catalyst slices", IosInfo{Binaries: []IosBinary{{Path: "App", Archs: []string{"arm64"}, MacCatalyst: true}}}, true, false},
+		{"telephony", IosInfo{RequiredCapabilities: []string{"telephony"}, Binaries: []IosBinary{device}}, false, false},
+		{"armv7 only", IosInfo{Binaries: []IosBinary{{Path: "App", Archs: []string{"armv7"}}}}, false, false},
+		{"simulator", IosInfo{Simulator: true, Binaries: []IosBinary{device}}, false, false},
+	} {
+		i := tt.info
+		checkMac(&i)
+		if i.MacCatalyst != tt.macCatalyst || i.IpadOnMac != tt.onMac {
+			t.Errorf("%s: got %v %v want %v %v", tt.name, i.MacCatalyst, i.IpadOnMac, tt.macCatalyst, tt.onMac)
+		}
+	}
+}
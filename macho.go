@@ -0,0 +1,193 @@
+package appfile
+
+import (
+	"archive/zip"
+	"bytes"
+	"debug/macho"
+	"path"
+	"strings"
+)
+
+// Mach-O symbol type bits, see <mach-o/nlist.h>, and load commands, see
+// <mach-o/loader.h>.
+const (
+	machoNStab = 0xe0
+	machoNType = 0x0e
+	machoNExt  = 0x01
+	machoNSect = 0x0e
+
+	machoLoadEncryptionInfo   = 0x21
+	machoLoadEncryptionInfo64 = 0x2c
+	machoLoadBuildVersion     = 0x32
+)
+
+// machoPlatformMacCatalyst is the LC_BUILD_VERSION platform of Mac
+// Catalyst code.
+const machoPlatformMacCatalyst = 6
+
+// Simulator platforms of LC_BUILD_VERSION.
+var machoSimulatorPlatforms = map[uint32]bool{
+	7:  true, // iOS
+	8:  true, // tvOS
+	9:  true, // watchOS
+	12: true, // visionOS
+}
+
+// isIpaBinary reports whether name is where an IPA keeps executable code:
+// the main executable, frameworks, dylibs and app extensions.
+func isIpaBinary(name string) bool {
+	parts := strings.Split(name, "/")
+	if len(parts) < 3 || parts[0] != "Payload" || !strings.HasSuffix(parts[1], ".app") {
+		return false
+	}
+	base := parts[len(parts)-1]
+	switch rest := parts[2 : len(parts)-1]; len(rest) {
+	case 0:
+		return path.Ext(base) == ""
+	case 1:
+		return rest[0] == "Frameworks" && path.Ext(base) == ".dylib"
+	case 2:
+		return (rest[0] == "Frameworks" && path.Ext(rest[1]) == ".framework" ||
+			rest[0] == "PlugIns" && path.Ext(rest[1]) == ".appex") &&
+			base == strings.TrimSuffix(rest[1], path.Ext(rest[1]))
+	}
+	return false
+}
+
+// isFairPlayFile reports whether name is App Store DRM data: the SC_Info
+// sinf and supp files or iTunesMetadata.plist of purchased apps.
+func isFairPlayFile(name string) bool {
+	if name == "iTunesMetadata.plist" {
+		return true
+	}
+	parts := strings.Split(name, "/")
+	return len(parts) == 4 && parts[0] == "Payload" && parts[2] == "SC_Info" && path.Ext(parts[3]) == ".sinf"
+}
+
+// parseIpaBinaries inspects the Mach-O files of an IPA. Files that are not
+// Mach-O, such as resources without an extension, are skipped.
+func parseIpaBinaries(files []*zip.File) ([]IosBinary, error) {
+	var binaries []IosBinary
+	for _, f := range files {
+		if !isIpaBinary(f.Name) {
+			continue
+		}
+		buf, err := readZipFile(f)
+		if err != nil {
+			return nil, err
+		}
+		if !isMachO(buf) {
+			continue
+		}
+		b, err := parseMachO(buf)
+		if err != nil {
+			continue
+		}
+		b.Path = strings.SplitN(f.Name, "/", 3)[2]
+		binaries = append(binaries, *b)
+	}
+	return binaries, nil
+}
+
+// parseMachO reads a thin or universal binary. It has bitcode if any
+// slice does and is stripped, for the Simulator or for Mac Catalyst if all
+// are.
+func parseMachO(buf []byte) (*IosBinary, error) {
+	var slices []*macho.File
+	if fat, err := macho.NewFatFile(bytes.NewReader(buf)); err == nil {
+		for _, a := range fat.Arches {
+			slices = append(slices, a.File)
+		}
+	} else if err != macho.ErrNotFat {
+		return nil, err
+	} else {
+		f, err := macho.NewFile(bytes.NewReader(buf))
+		if err != nil {
+			return nil, err
+		}
+		slices = append(slices, f)
+	}
+
+	b := &IosBinary{Stripped: true, Simulator: len(slices) > 0, MacCatalyst: len(slices) > 0}
+	for _, f := range slices {
+		b.Archs = append(b.Archs, machoArch(f))
+		b.Bitcode = b.Bitcode || f.Segment("__LLVM") != nil
+		b.Stripped = b.Stripped && isStripped(f)
+		b.Encrypted = b.Encrypted || isEncrypted(f)
+		b.Simulator = b.Simulator && isSimulator(f)
+		b.MacCatalyst = b.MacCatalyst && machoPlatform(f) == machoPlatformMacCatalyst
+	}
+	return b, nil
+}
+
+// isStripped reports whether f lacks local and debug symbols; `strip`
+// keeps only the external symbols needed for dynamic linking.
+func isStripped(f *macho.File) bool {
+	if f.Symtab == nil {
+		return true
+	}
+	for _, s := range f.Symtab.Syms {
+		if s.Type&machoNStab != 0 {
+			return false
+		}
+		if s.Type&machoNExt == 0 && s.Type&machoNType == machoNSect {
+			return false
+		}
+	}
+	return true
+}
+
+// isEncrypted reports whether f has an encryption info load command with a
+// nonzero cryptid, i.e. FairPlay encrypted text.
+func isEncrypted(f *macho.File) bool {
+	for _, l := range f.Loads {
+		raw := l.Raw()
+		if len(raw) < 20 {
+			continue
+		}
+		cmd := f.ByteOrder.Uint32(raw)
+		if (cmd == machoLoadEncryptionInfo || cmd == machoLoadEncryptionInfo64) && f.ByteOrder.Uint32(raw[16:]) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// isSimulator reports whether f is built for a Simulator: its build
+// version names a simulator platform, which tells arm64-simulator slices
+// from device ones. Older binaries without one are device builds unless
+// they are Intel code.
+func isSimulator(f *macho.File) bool {
+	if platform := machoPlatform(f); platform != 0 {
+		return machoSimulatorPlatforms[platform]
+	}
+	return f.Cpu == macho.CpuAmd64 || f.Cpu == macho.Cpu386
+}
+
+// machoPlatform returns the platform of the LC_BUILD_VERSION of f, or 0.
+func machoPlatform(f *macho.File) uint32 {
+	for _, l := range f.Loads {
+		raw := l.Raw()
+		if len(raw) >= 12 && f.ByteOrder.Uint32(raw) == machoLoadBuildVersion {
+			return f.ByteOrder.Uint32(raw[8:])
+		}
+	}
+	return 0
+}
+
+func machoArch(f *macho.File) string {
+	switch f.Cpu {
+	case macho.CpuArm64:
+		if f.SubCpu&0xff == 2 {
+			return "arm64e"
+		}
+		return "arm64"
+	case macho.CpuArm:
+		return "armv7"
+	case macho.CpuAmd64:
+		return "x86_64"
+	case macho.Cpu386:
+		return "i386"
+	}
+	return strings.TrimPrefix(strings.ToLower(f.Cpu.String()), "cpu")
+}
@@ -0,0 +1,52 @@
+package appfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIsIpaBinary(t *testing.T) {
+	for name, want := range map[string]bool{
+		"Payload/App.app/App":                                 true,
+		"Payload/App.app/PkgInfo":                             true,
+		"Payload/App.app/Info.plist":                          false,
+		"Payload/App.app/Frameworks/libswiftCore.dylib":       true,
+		"Payload/App.app/Frameworks/Kit.framework/Kit":        true,
+		"Payload/App.app/Frameworks/Kit.framework/Info.plist": false,
+		"Payload/App.app/PlugIns/Widget.appex/Widget":         true,
+		"Payload/App.app/en.lproj/Main.storyboardc/Info":      false,
+		"SwiftSupport/iphoneos/libswiftCore.dylib":            false,
+	} {
+		if got := isIpaBinary(name); got != want {
+			t.Errorf("%v: got %v want %v", name, got, want)
+		}
+	}
+}
+
+func TestParseIpaBinaries(t *testing.T) {
+	reader, err := getAppZipReader("testdata/helloworld.ipa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := parseIpaBinaries(reader.File)
+	if err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	want := []IosBinary{{Path: "helloworld", Archs: []string{"armv7"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+}
+
+func TestIsFairPlayFile(t *testing.T) {
+	for name, want := range map[string]bool{
+		"iTunesMetadata.plist":                   true,
+		"Payload/App.app/SC_Info/App.sinf":       true,
+		"Payload/App.app/SC_Info/Manifest.plist": false,
+		"Payload/App.app/App":                    false,
+	} {
+		if got := isFairPlayFile(name); got != want {
+			t.Errorf("%v: got %v want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package appfile
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+var ErrNoManifest = errors.New("AndroidManifest.xml not found")
+
+// WriteManifestXML writes the AndroidManifest.xml of an .apk, .aab or .apks
+// file as indented XML text, like `aapt2 dump xmltree`. References to the
+// app's resources are shown by name, e.g. @string/app_name.
+func WriteManifestXML(name string, w io.Writer) (err error) {
+	defer recoverPanic(&err)
+	file, _, reader, err := openZipFile(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return writeManifestXML(name, reader, w)
+}
+
+func writeManifestXML(name string, reader *zip.Reader, w io.Writer) error {
+	decoder, files, err := openManifestXML(name, reader)
+	if err != nil {
+		return err
+	}
+	var res *apkResources
+	if files != nil {
+		res = newApkResources(files)
+	}
+	return writeIndentedXML(w, decoder, res)
+}
+
+// openManifestXML returns a decoder of the manifest of the .apk, .aab or
+// .apks file name and the files of the APK whose resources its references
+// name, which an .aab has none of.
+func openManifestXML(name string, reader *zip.Reader) (*xml.Decoder, []*zip.File, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case aabExt:
+		f := findZipFile(reader.File, "base/manifest/AndroidManifest.xml")
+		if f == nil {
+			return nil, nil, ErrNoManifest
+		}
+		buf, err := readZipFile(f)
+		if err != nil {
+			return nil, nil, err
+		}
+		decoder, err := newProtoXMLDecoder(buf)
+		return decoder, nil, err
+	case apksExt:
+		_, base, err := parseApksFile(reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		if reader, err = zip.NewReader(bytes.NewReader(base), int64(len(base))); err != nil {
+			return nil, nil, err
+		}
+	}
+	f := findZipFile(reader.File, "AndroidManifest.xml")
+	if f == nil {
+		return nil, nil, ErrNoManifest
+	}
+	xmlFile, err := readZipAndroidXML(f)
+	if err != nil {
+		return nil, nil, err
+	}
+	return xml.NewDecoder(xmlFile.Reader()), reader.File, nil
+}
+
+// rawTokens reads the remaining raw tokens of d, copied so they outlive
+// it.
+func rawTokens(d *xml.Decoder) ([]xml.Token, error) {
+	var tokens []xml.Token
+	for {
+		t, err := d.RawToken()
+		if err == io.EOF {
+			return tokens, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, xml.CopyToken(t))
+	}
+}
+
+// tokenList replays tokens read by rawTokens, see xml.NewTokenDecoder.
+type tokenList []xml.Token
+
+func (l *tokenList) Token() (xml.Token, error) {
+	if len(*l) == 0 {
+		return nil, io.EOF
+	}
+	t := (*l)[0]
+	*l = (*l)[1:]
+	return t, nil
+}
+
+// writeIndentedXML copies the raw tokens of d, keeping namespace prefixes.
+// Names in namespaces declared by URI, as in App Bundles, are written with
+// the declared prefix.
+func writeIndentedXML(w io.Writer, d *xml.Decoder, res *apkResources) error {
+	bw := bufio.NewWriter(w)
+	prefixes := make(map[string]string)
+	qname := func(n xml.Name) string {
+		if n.Space == "" {
+			return n.Local
+		}
+		if p, ok := prefixes[n.Space]; ok {
+			return p + ":" + n.Local
+		}
+		return n.Space + ":" + n.Local
+	}
+
+	depth := 0
+	open := false // a start tag is waiting for ">" or "/>"
+	for {
+		t, err := d.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		switch t := t.(type) {
+		case xml.StartElement:
+			if open {
+				bw.WriteString(">\n")
+			}
+			for _, a := range t.Attr {
+				if a.Name.Space == "xmlns" {
+					prefixes[a.Value] = a.Name.Local
+				}
+			}
+			bw.WriteString(strings.Repeat("  ", depth) + "<" + qname(t.Name))
+			for _, a := range t.Attr {
+				value := a.Value
+				if res != nil {
+					value = res.refName(value)
+				}
+				bw.WriteString(" " + qname(a.Name) + `="`)
+				xml.EscapeText(bw, []byte(value))
+				bw.WriteString(`"`)
+			}
+			open = true
+			depth++
+		case xml.EndElement:
+			depth--
+			if open {
+				bw.WriteString("/>\n")
+			} else {
+				bw.WriteString(strings.Repeat("  ", depth) + "</" + qname(t.Name) + ">\n")
+			}
+			open = false
+		case xml.CharData:
+			if text := strings.TrimSpace(string(t)); text != "" {
+				if open {
+					bw.WriteString(">\n")
+					open = false
+				}
+				bw.WriteString(strings.Repeat("  ", depth))
+				xml.EscapeText(bw, []byte(text))
+				bw.WriteString("\n")
+			}
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,81 @@
+package appfile
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteManifestXML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteManifestXML("testdata/helloworld.apk", &buf); err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	want := `<manifest xmlns:android="http://schemas.android.com/apk/res/android" android:versionCode="1" android:versionName="1.0" package="com.example.helloworld" platformBuildVersionCode="24" platformBuildVersionName="7.0">
+  <uses-sdk android:minSdkVersion="15" android:targetSdkVersion="24"/>
+  <application android:theme="@style/AppTheme" android:label="@string/app_name" android:icon="@mipmap/ic_launcher" android:debuggable="true" android:allowBackup="true" android:supportsRtl="true">
+    <activity android:name="com.example.helloworld.MainActivity">
+      <intent-filter>
+        <action android:name="android.intent.action.MAIN"/>
+        <category android:name="android.intent.category.LAUNCHER"/>
+      </intent-filter>
+    </activity>
+  </application>
+</manifest>
+`
+	if buf.String() != want {
+		t.Errorf("got %v want %v", buf.String(), want)
+	}
+
+	if err := WriteManifestXML("testdata/helloworld.ipa", &buf); err != ErrNoManifest {
+		t.Errorf("got %v want %v", err, ErrNoManifest)
+	}
+}
+
+func TestWriteManifestXMLAab(t *testing.T) {
+	dir, err := ioutil.TempDir("", "appfile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "app.aab")
+	writeZip(t, name, map[string][]byte{
+		"base/manifest/AndroidManifest.xml": pbElement("", "manifest", [][3]string{
+			{"xmlns", "android", androidNS},
+			{"xmlns", "dist", distNS},
+			{"", "package", "com.example.bundle"},
+			{androidNS, "versionCode", "42"},
+		},
+			pbElement(distNS, "module", [][3]string{{distNS, "instant", "true"}}),
+		),
+	})
+
+	var buf bytes.Buffer
+	if err := WriteManifestXML(name, &buf); err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	want := `<manifest xmlns:android="http://schemas.android.com/apk/res/android" xmlns:dist="http://schemas.android.com/apk/distribution" package="com.example.bundle" android:versionCode="42">
+  <dist:module dist:instant="true"/>
+</manifest>
+`
+	if buf.String() != want {
+		t.Errorf("got %v want %v", buf.String(), want)
+	}
+
+	p, err := OpenParser(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+	for i := 0; i < 2; i++ {
+		buf.Reset()
+		if err := p.WriteManifestXML(&buf); err != nil || buf.String() != want {
+			t.Errorf("got %v %v want %v", buf.String(), err, want)
+		}
+	}
+	if info, err := p.Manifest(); err != nil || info.BundleId != "com.example.bundle" || info.Build != "42" {
+		t.Errorf("got %v %v want %v", info, err, "com.example.bundle")
+	}
+}
@@ -0,0 +1,93 @@
+package appfile
+
+import (
+	"archive/zip"
+	"strings"
+)
+
+// What a library found by sdkMarker is used for.
+const (
+	markerAttestation = iota
+	markerDetection
+	markerBilling
+	markerTracking
+)
+
+// sdkMarker is a library recognized by the classes and symbols it leaves
+// in dex files and Mach-O binaries.
+type sdkMarker struct {
+	name    string
+	kind    int
+	markers [][]byte
+}
+
+var apkSDKMarkers = []sdkMarker{
+	{AttestationPlayIntegrity, markerAttestation, [][]byte{[]byte("Lcom/google/android/play/core/integrity/")}},
+	{AttestationSafetyNet, markerAttestation, [][]byte{[]byte("Lcom/google/android/gms/safetynet/")}},
+	{DetectionRootBeer, markerDetection, [][]byte{[]byte("Lcom/scottyab/rootbeer/")}},
+	{DetectionFreeRASP, markerDetection, [][]byte{[]byte("Lcom/aheaditec/talsec/")}},
+	{DetectionJailMonkey, markerDetection, [][]byte{[]byte("Lcom/gantix/JailMonkey/")}},
+	{DetectionFlutterJailbreak, markerDetection, [][]byte{[]byte("Lappmire/be/flutterjailbreakdetection/")}},
+	{DetectionCustom, markerDetection, [][]byte{[]byte("/system/xbin/su"), []byte("/system/bin/su\x00")}},
+	{BillingPlay, markerBilling, [][]byte{[]byte("Lcom/android/billingclient/api/BillingClient;"), []byte("Lcom/android/vending/billing/IInAppBillingService;")}},
+	{BillingAmazon, markerBilling, [][]byte{[]byte("Lcom/amazon/device/iap/PurchasingService;")}},
+	{BillingRevenueCat, markerBilling, [][]byte{[]byte("Lcom/revenuecat/purchases/")}},
+	{trackingAdvertisingID, markerTracking, [][]byte{[]byte("Lcom/google/android/gms/ads/identifier/AdvertisingIdClient;")}},
+}
+
+var ipaSDKMarkers = []sdkMarker{
+	{AttestationAppAttest, markerAttestation, [][]byte{[]byte("DCAppAttestService")}},
+	{AttestationDeviceCheck, markerAttestation, [][]byte{[]byte("DCDevice")}},
+	{DetectionIOSSecuritySuite, markerDetection, [][]byte{[]byte("IOSSecuritySuite")}},
+	{DetectionDTTJailbreak, markerDetection, [][]byte{[]byte("DTTJailbreakDetection")}},
+	{DetectionFreeRASP, markerDetection, [][]byte{[]byte("TalsecRuntime")}},
+	{DetectionJailMonkey, markerDetection, [][]byte{[]byte("JailMonkey")}},
+	{DetectionCustom, markerDetection, [][]byte{[]byte("/Applications/Cydia.app"), []byte("cydia://")}},
+	{BillingStoreKit, markerBilling, [][]byte{[]byte("/System/Library/Frameworks/StoreKit.framework/StoreKit")}},
+	{BillingRevenueCat, markerBilling, [][]byte{[]byte("RevenueCat")}},
+	{trackingAdvertisingID, markerTracking, [][]byte{[]byte("ASIdentifierManager")}},
+	{trackingATT, markerTracking, [][]byte{[]byte("ATTrackingManager")}},
+}
+
+// sdkScan holds the names of the libraries found, by kind.
+type sdkScan map[int][]string
+
+func (s sdkScan) scan(buf []byte, markers []sdkMarker) {
+	for _, m := range markers {
+		if containsAny(buf, m.markers) {
+			s[m.kind] = appendUnique(s[m.kind], m.name)
+		}
+	}
+}
+
+// scanApkSDKs looks for the apkSDKMarkers in the dex files among files.
+func scanApkSDKs(files []*zip.File) (sdkScan, error) {
+	s := make(sdkScan)
+	for _, f := range files {
+		if !strings.HasSuffix(f.Name, ".dex") {
+			continue
+		}
+		buf, err := readZipFile(f)
+		if err != nil {
+			return nil, err
+		}
+		s.scan(buf, apkSDKMarkers)
+	}
+	return s, nil
+}
+
+// scanIpaSDKs looks for the ipaSDKMarkers in the binaries of an IPA.
+func scanIpaSDKs(files []*zip.File) (sdkScan, error) {
+	s := make(sdkScan)
+	for _, f := range files {
+		if !isIpaBinary(f.Name) {
+			continue
+		}
+		buf, err := readZipFile(f)
+		if err != nil {
+			return nil, err
+		}
+		s.scan(buf, ipaSDKMarkers)
+	}
+	return s, nil
+}
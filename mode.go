@@ -0,0 +1,61 @@
+package appfile
+
+import "errors"
+
+// StageWarnings is the stage of the errors strict mode makes of warnings.
+const StageWarnings = "warnings"
+
+// Mode is how NewAppParser treats anomalies: failed stages that still
+// allow a result, such as a missing icon or an unverified profile, and
+// AppInfo.Warnings.
+type Mode int
+
+const (
+	// ModeDefault returns the AppInfo together with a ParseError.
+	ModeDefault Mode = iota
+	// ModeStrict fails the parse on any failed stage and promotes
+	// warnings to errors. Unknown manifest and plist keys are not
+	// anomalies; they are ignored in every mode.
+	ModeStrict
+	// ModeLenient returns the AppInfo without error and adds the failed
+	// stages to its Warnings.
+	ModeLenient
+)
+
+// WithMode sets how anomalies are reported.
+func WithMode(m Mode) Option {
+	return func(o *options) {
+		o.mode = m
+	}
+}
+
+func (o *options) applyMode(info *AppInfo, err error) (*AppInfo, error) {
+	if info == nil {
+		return nil, err
+	}
+	switch o.mode {
+	case ModeStrict:
+		var errs stageErrors
+		var perr *ParseError
+		if errors.As(err, &perr) {
+			errs = perr.Errors
+		} else if err != nil {
+			return nil, err
+		}
+		for _, w := range info.Warnings {
+			errs.add(StageWarnings, errors.New(w))
+		}
+		if len(errs) > 0 {
+			return nil, errs.err()
+		}
+	case ModeLenient:
+		var perr *ParseError
+		if errors.As(err, &perr) {
+			for _, e := range perr.Errors {
+				info.warn("%v", e)
+			}
+			return info, nil
+		}
+	}
+	return info, err
+}
@@ -0,0 +1,36 @@
+package appfile
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestApplyMode(t *testing.T) {
+	iconErr := func() error {
+		var errs stageErrors
+		errs.add(StageIcon, ErrNoIcon)
+		return errs.err()
+	}
+
+	o := newOptions(nil)
+	info, err := o.applyMode(&AppInfo{}, iconErr())
+	if info == nil || !errors.Is(err, ErrNoIcon) {
+		t.Errorf("default: got %v, %v want info and %v", info, err, ErrNoIcon)
+	}
+
+	o = newOptions([]Option{WithMode(ModeLenient)})
+	info, err = o.applyMode(&AppInfo{}, iconErr())
+	if err != nil || info == nil || !reflect.DeepEqual(info.Warnings, []string{"icon_decode: icon not found"}) {
+		t.Errorf("lenient: got %+v, %v want warning and no error", info, err)
+	}
+
+	o = newOptions([]Option{WithMode(ModeStrict)})
+	info, err = o.applyMode(&AppInfo{Warnings: []string{"Symbols/ is missing"}}, iconErr())
+	if info != nil || err == nil || err.Error() != "icon_decode: icon not found; warnings: Symbols/ is missing" {
+		t.Errorf("strict: got %+v, %v want no info", info, err)
+	}
+	if info, err = o.applyMode(&AppInfo{}, nil); info == nil || err != nil {
+		t.Errorf("strict: got %+v, %v want info", info, err)
+	}
+}
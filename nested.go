@@ -0,0 +1,229 @@
+package appfile
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+const (
+	zipExt   = ".zip"
+	tgzExt   = ".tgz"
+	tarGzExt = ".tar.gz"
+)
+
+var errNoPackage = errors.New("no app package in archive")
+
+// The containers look up the format of the package they hold, so they are
+// registered once formats is initialized.
+func init() {
+	formats[zipExt] = zipFormat(parseNestedZip)
+	formats[tgzExt] = formatFunc(parseNestedTar)
+	formats[tarGzExt] = formatFunc(parseNestedTar)
+}
+
+// IsContainer reports whether name has the extension of an archive that
+// NewAppParser looks for an app package in: .zip, .tgz or .tar.gz.
+func IsContainer(name string) bool {
+	switch formatExt(name) {
+	case zipExt, tgzExt, tarGzExt:
+		return true
+	}
+	return false
+}
+
+// ContainsApp reports whether name is a container holding a single app
+// package, which NewAppParser parses. It reads the archive's listing, so
+// build logs, dSYM zips and source tarballs can be told apart from
+// artifacts.
+func ContainsApp(name string) bool {
+	if !IsContainer(name) {
+		return false
+	}
+	if formatExt(name) == zipExt {
+		r, err := zip.OpenReader(name)
+		if err != nil {
+			return false
+		}
+		defer r.Close()
+		return len(nestedPackages(&r.Reader)) == 1
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	var packages int
+	err = walkNestedTar(f, func(string, io.Reader) error {
+		packages++
+		return nil
+	})
+	return err == nil && packages == 1
+}
+
+// packageFormat returns the format of an archive entry that is an app
+// package, such as an .apk or .ipa, and nil for anything else, including
+// other containers and macOS resource forks.
+func packageFormat(name string) Format {
+	base := path.Base(name)
+	if strings.HasPrefix(name, "__MACOSX/") || strings.HasPrefix(base, "._") {
+		return nil
+	}
+	if IsContainer(base) {
+		return nil
+	}
+	ext := formatExt(base)
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	if _, ok := formats[ext].(dirFormat); ok {
+		return nil
+	}
+	return formats[ext]
+}
+
+// parseNestedZip parses the single app package in a .zip, the layout CI
+// systems commonly upload build artifacts in.
+func parseNestedZip(_ string, reader *zip.Reader, o *options) (*AppInfo, error) {
+	packages := nestedPackages(reader)
+	if len(packages) != 1 {
+		var errs stageErrors
+		names := make([]string, len(packages))
+		for i, f := range packages {
+			names[i] = f.Name
+		}
+		errs.add(StageZipRead, nestedError(names))
+		return nil, errs.err()
+	}
+	return parseNestedZipEntry(packages[0], o)
+}
+
+// nestedPackages returns the app packages in reader.
+func nestedPackages(reader *zip.Reader) []*zip.File {
+	var packages []*zip.File
+	for _, f := range reader.File {
+		if !strings.HasSuffix(f.Name, "/") && packageFormat(f.Name) != nil {
+			packages = append(packages, f)
+		}
+	}
+	return packages
+}
+
+func parseNestedZipEntry(f *zip.File, o *options) (*AppInfo, error) {
+	rc, err := f.Open()
+	if err != nil {
+		var errs stageErrors
+		errs.add(StageZipRead, err)
+		return nil, errs.err()
+	}
+	defer rc.Close()
+	return parseNestedEntry(f.Name, rc, o)
+}
+
+// parseNestedTar is parseNestedZip for a .tar.gz.
+func parseNestedTar(_ string, r io.ReaderAt, size int64, o *options) (*AppInfo, error) {
+	var errs stageErrors
+	end := o.startStage(StageZipRead)
+	tmp, entry, err := extractNestedTar(io.NewSectionReader(r, 0, size), o)
+	end(err)
+	if err != nil {
+		errs.add(StageZipRead, err)
+		return nil, errs.err()
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	return parseNestedFile(entry, tmp, o)
+}
+
+// extractNestedTar copies the single app package of a .tar.gz to a
+// temporary file, which the caller must remove.
+func extractNestedTar(r io.Reader, o *options) (*os.File, string, error) {
+	var tmp *os.File
+	var packages []string
+	err := walkNestedTar(r, func(entry string, r io.Reader) error {
+		packages = append(packages, entry)
+		if tmp != nil {
+			return nil
+		}
+		var err error
+		tmp, err = o.spool(entry, r)
+		return err
+	})
+	if err == nil && len(packages) != 1 {
+		err = nestedError(packages)
+	}
+	if err != nil {
+		if tmp != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+		return nil, "", err
+	}
+	return tmp, packages[0], nil
+}
+
+// walkNestedTar calls fn with every app package in the .tar.gz r, in
+// archive order.
+func walkNestedTar(r io.Reader, fn func(entry string, r io.Reader) error) error {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(zr)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if h.Typeflag == tar.TypeReg && packageFormat(h.Name) != nil {
+			if err := fn(h.Name, tr); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// nestedError explains why an archive with packages, which are not one,
+// cannot be parsed.
+func nestedError(packages []string) error {
+	if len(packages) == 0 {
+		return errNoPackage
+	}
+	return fmt.Errorf("archive contains %d app packages: %s", len(packages), strings.Join(packages, ", "))
+}
+
+func parseNestedEntry(entry string, r io.Reader, o *options) (*AppInfo, error) {
+	tmp, err := o.spool(entry, r)
+	if err != nil {
+		var errs stageErrors
+		errs.add(StageZipRead, err)
+		return nil, errs.err()
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	return parseNestedFile(entry, tmp, o)
+}
+
+// parseNestedFile parses tmp, extracted from the archive entry entry, and
+// records the entry in Container.
+func parseNestedFile(entry string, tmp *os.File, o *options) (*AppInfo, error) {
+	stat, err := tmp.Stat()
+	if err != nil {
+		var errs stageErrors
+		errs.add(StageZipRead, err)
+		return nil, errs.err()
+	}
+	info, err := o.parseFormat(packageFormat(entry), tmp.Name(), tmp, stat.Size())
+	if info != nil {
+		info.Container = entry
+	}
+	return info, err
+}
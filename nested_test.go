@@ -0,0 +1,95 @@
+package appfile
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseNested(t *testing.T) {
+	dir, err := ioutil.TempDir("", "appfile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	aab := filepath.Join(dir, "app.aab")
+	writeZip(t, aab, map[string][]byte{
+		"base/manifest/AndroidManifest.xml": pbElement("", "manifest", [][3]string{{"", "package", "com.example.nested"}}),
+	})
+	inner, err := ioutil.ReadFile(aab)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name := filepath.Join(dir, "artifact.zip")
+	writeZip(t, name, map[string][]byte{
+		"build/outputs/app.aab":            inner,
+		"__MACOSX/build/outputs/._app.aab": []byte("resource fork"),
+		"mapping.txt":                      nil,
+	})
+	info, err := NewAppParser(name)
+	if err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	if info.BundleId != "com.example.nested" || info.Container != "build/outputs/app.aab" {
+		t.Errorf("got %v %v want %v %v", info.BundleId, info.Container, "com.example.nested", "build/outputs/app.aab")
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	tw.WriteHeader(&tar.Header{Name: "dist/app.aab", Mode: 0644, Size: int64(len(inner)), Typeflag: tar.TypeReg})
+	tw.Write(inner)
+	tw.Close()
+	zw.Close()
+	name = filepath.Join(dir, "artifact.tar.gz")
+	if err := ioutil.WriteFile(name, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	info, err = NewAppParser(name)
+	if err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	if info.BundleId != "com.example.nested" || info.Container != "dist/app.aab" {
+		t.Errorf("got %v %v want %v %v", info.BundleId, info.Container, "com.example.nested", "dist/app.aab")
+	}
+
+	if !ContainsApp(name) || IsSupported(name) {
+		t.Errorf("got %v %v want a container with an app", ContainsApp(name), IsSupported(name))
+	}
+
+	name = filepath.Join(dir, "empty.zip")
+	writeZip(t, name, map[string][]byte{"mapping.txt": nil})
+	if ContainsApp(name) {
+		t.Errorf("got %v want %v", true, false)
+	}
+	if _, err := NewAppParser(name); !errors.Is(err, errNoPackage) {
+		t.Errorf("got %v want %v", err, errNoPackage)
+	}
+	name = filepath.Join(dir, "two.zip")
+	writeZip(t, name, map[string][]byte{"a.aab": inner, "b.aab": inner})
+	if _, err := NewAppParser(name); err == nil {
+		t.Errorf("got no error want one for two packages")
+	}
+
+	// A gzipped file that is no tarball is not a container.
+	name = filepath.Join(dir, "app.aab.gz")
+	buf.Reset()
+	zw = gzip.NewWriter(&buf)
+	zw.Write(inner)
+	zw.Close()
+	if err := ioutil.WriteFile(name, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if IsContainer(name) || ContainsApp(name) {
+		t.Errorf("got a container want %v not to be one", name)
+	}
+	if _, err := NewAppParser(name); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("got %v want %v", err, ErrUnknownFormat)
+	}
+}
@@ -0,0 +1,24 @@
+package appfile
+
+import "context"
+
+// Notifier is told about every completed parse, including cache hits.
+// Notification errors are reported to hooks as the "notify" stage and do
+// not fail the parse.
+type Notifier interface {
+	Notify(ctx context.Context, name string, info *AppInfo) error
+}
+
+// WithNotifier registers n to be called once a parse produced a result.
+func WithNotifier(n Notifier) Option {
+	return func(o *options) {
+		o.notifiers = append(o.notifiers, n)
+	}
+}
+
+func (o *options) notify(name string, info *AppInfo) {
+	for _, n := range o.notifiers {
+		end := o.startStage(StageNotify)
+		end(n.Notify(o.ctx, name, info))
+	}
+}
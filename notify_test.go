@@ -0,0 +1,49 @@
+package appfile
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type recordingNotifier struct {
+	names []string
+	err   error
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, name string, info *AppInfo) error {
+	n.names = append(n.names, name+" "+info.BundleId)
+	return n.err
+}
+
+type errorHook struct {
+	errs map[string]error
+}
+
+func (h *errorHook) StartStage(ctx context.Context, stage string) (context.Context, func(error)) {
+	return ctx, func(err error) {
+		h.errs[stage] = err
+	}
+}
+
+func TestWithNotifier(t *testing.T) {
+	n := &recordingNotifier{err: errors.New("unreachable")}
+	h := &errorHook{errs: make(map[string]error)}
+	NewAppParser("testdata/helloworld.apk", WithNotifier(n), WithHook(h))
+
+	want := "testdata/helloworld.apk com.example.helloworld"
+	if len(n.names) != 1 || n.names[0] != want {
+		t.Errorf("got %v want %v", n.names, want)
+	}
+	if h.errs[StageNotify] != n.err {
+		t.Errorf("got %v want %v", h.errs[StageNotify], n.err)
+	}
+}
+
+func TestWithNotifierNoResult(t *testing.T) {
+	n := new(recordingNotifier)
+	NewAppParser("testdata/missing.apk", WithNotifier(n))
+	if len(n.names) != 0 {
+		t.Errorf("got %v want none", n.names)
+	}
+}
@@ -0,0 +1,57 @@
+package appfile
+
+import (
+	"archive/zip"
+	"sort"
+	"strings"
+)
+
+// onDemandResourcesPlist is the OnDemandResources.plist Xcode writes into
+// apps using On-Demand Resources.
+type onDemandResourcesPlist struct {
+	Tags map[string]struct {
+		AssetPacks []string `plist:"NSAssetPacks"`
+	} `plist:"NSBundleResourceRequestTags"`
+}
+
+func parseIpaOnDemandResources(f *zip.File, files []*zip.File) ([]OnDemandTag, error) {
+	if f == nil {
+		return nil, nil
+	}
+	var p onDemandResourcesPlist
+	if err := readZipPlist(f, &p); err != nil {
+		return nil, err
+	}
+
+	packs := make(map[string][]string, len(p.Tags))
+	for tag, t := range p.Tags {
+		packs[tag] = t.AssetPacks
+	}
+	return onDemandTags(packs, files), nil
+}
+
+// onDemandTags sizes the asset packs of each tag by the <pack>.assetpack
+// directories in files. Store builds do not embed the packs; their sizes
+// are zero.
+func onDemandTags(packs map[string][]string, files []*zip.File) []OnDemandTag {
+	sizes := make(map[string]int64)
+	for _, f := range files {
+		for _, dir := range strings.Split(f.Name, "/") {
+			if strings.HasSuffix(dir, ".assetpack") {
+				sizes[strings.TrimSuffix(dir, ".assetpack")] += int64(f.UncompressedSize64)
+				break
+			}
+		}
+	}
+
+	tags := make([]OnDemandTag, 0, len(packs))
+	for tag, ids := range packs {
+		t := OnDemandTag{Tag: tag, AssetPacks: ids}
+		for _, id := range ids {
+			t.Size += sizes[id]
+		}
+		tags = append(tags, t)
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Tag < tags[j].Tag })
+	return tags
+}
@@ -0,0 +1,32 @@
+package appfile
+
+import (
+	"archive/zip"
+	"reflect"
+	"testing"
+)
+
+func TestOnDemandTags(t *testing.T) {
+	file := func(name string, size uint64) *zip.File {
+		return &zip.File{FileHeader: zip.FileHeader{Name: name, UncompressedSize64: size}}
+	}
+	files := []*zip.File{
+		file("Payload/App.app/Info.plist", 100),
+		file("Payload/App.app/OnDemandResources/com.example.pack-1.assetpack/level1.png", 2000),
+		file("Payload/App.app/OnDemandResources/com.example.pack-1.assetpack/Info.plist", 50),
+		file("Payload/App.app/OnDemandResources/com.example.pack-2.assetpack/level2.png", 3000),
+	}
+	got := onDemandTags(map[string][]string{
+		"level2": {"com.example.pack-2"},
+		"level1": {"com.example.pack-1"},
+		"store":  {"com.example.pack-3"},
+	}, files)
+	want := []OnDemandTag{
+		{Tag: "level1", AssetPacks: []string{"com.example.pack-1"}, Size: 2050},
+		{Tag: "level2", AssetPacks: []string{"com.example.pack-2"}, Size: 3000},
+		{Tag: "store", AssetPacks: []string{"com.example.pack-3"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+}
@@ -0,0 +1,66 @@
+package appfile
+
+import (
+	"context"
+	"time"
+)
+
+// Option configures NewAppParser.
+type Option func(*options)
+
+type options struct {
+	ctx   context.Context
+	hooks []Hook
+	cache Cache
+	now   func() time.Time
+
+	progress func(stage string, done, total int64)
+	stage    string        // the innermost running stage
+	running  []func(error) // the ends of the running stages, innermost last
+	panics   bool
+
+	scanURLs  bool
+	mode      Mode
+	framework *frameworkResources
+
+	tolerantZip bool
+	recoverZip  bool
+	password    string
+
+	spoolDir string
+	maxSize  int64
+
+	verifiers []ProvenanceVerifier
+	notifiers []Notifier
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{ctx: context.Background(), now: time.Now}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithContext sets the context handed to hooks, e.g. to parent trace spans.
+func WithContext(ctx context.Context) Option {
+	return func(o *options) {
+		o.ctx = ctx
+	}
+}
+
+// WithClock sets the clock the Expired flags of profiles and certificates
+// and expiry warnings are judged against, e.g. for tests or an audit
+// backdated to a release day.
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+// WithHook registers a hook that is notified around every parse stage.
+func WithHook(h Hook) Option {
+	return func(o *options) {
+		o.hooks = append(o.hooks, h)
+	}
+}
@@ -0,0 +1,33 @@
+// Package otelhook wraps appfile parse stages in OpenTelemetry spans.
+package otelhook
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/follyxing/appfile-info"
+
+// Hook starts one span per parse stage. Stages nest under the "parse" span,
+// which itself is a child of the span in the context given to
+// appfile.WithContext, if any.
+type Hook struct {
+	tracer trace.Tracer
+}
+
+func New(tp trace.TracerProvider) *Hook {
+	return &Hook{tracer: tp.Tracer(instrumentationName)}
+}
+
+func (h *Hook) StartStage(ctx context.Context, stage string) (context.Context, func(err error)) {
+	ctx, span := h.tracer.Start(ctx, "appfile."+stage)
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
@@ -9,25 +9,28 @@ import (
 	"image"
 	"image/png"
 	"io"
-	"io/ioutil"
 	"log"
 	"os"
-	"path/filepath"
 	"regexp"
-	"strconv"
 	"strings"
 	"time"
 
-	"github.com/follyxing/go-plist"
 	"github.com/andrianbdn/iospng"
 	"github.com/fullsailor/pkcs7"
-	"github.com/shogo82148/androidbinary"
-	"github.com/shogo82148/androidbinary/apk"
 )
 
 var (
-	reInfoPlist = regexp.MustCompile(`Payload/[^/]+/Info\.plist`)
-	ErrNoIcon   = errors.New("icon not found")
+	reInfoPlist              = regexp.MustCompile(`Payload/[^/]+/Info\.plist`)
+	reGoogleServicesPlist    = regexp.MustCompile(`^Payload/[^/]+/GoogleService-Info\.plist$`)
+	reOnDemandResourcesPlist = regexp.MustCompile(`^Payload/[^/]+/OnDemandResources\.plist$`)
+	ErrNoIcon                = errors.New("icon not found")
+	// ErrUnknownFormat is returned for files no registered format parses.
+	ErrUnknownFormat = errors.New("unknown platform")
+	// ErrProfileUnverified is returned with profiles whose signature does
+	// not verify; their contents are still decoded.
+	ErrProfileUnverified = errors.New("profile signature not verified")
+	// ErrEntryTooLarge is returned for archive entries above maxEntrySize.
+	ErrEntryTooLarge = errors.New("zip entry too large")
 )
 
 const (
@@ -35,32 +38,59 @@ const (
 	androidExt = ".apk"
 )
 
-type AppInfo struct {
-	Name                     string
-	BundleId                 string
-	Version                  string
-	Build                    string
-	Icon                     image.Image
-	Size                     int64
-	ApkDebug                 bool
-	IosPlatform              []string
-	IosSigningType           string
-	IosSigningExpirationDate string
-	IosProvisionedDevices    []string
+type androidManifest struct {
+	Package         string                  `xml:"package,attr"`
+	Split           string                  `xml:"split,attr"`
+	SandboxVersion  string                  `xml:"targetSandboxVersion,attr"`
+	SharedUserId    string                  `xml:"sharedUserId,attr"`
+	InstallLocation string                  `xml:"installLocation,attr"`
+	CompileSdk      string                  `xml:"compileSdkVersion,attr"`
+	PlatformBuild   string                  `xml:"platformBuildVersionCode,attr"`
+	VersionName     string                  `xml:"versionName,attr"`
+	VersionCode     string                  `xml:"versionCode,attr"`
+	UsesSdk         androidUsesSdk          `xml:"uses-sdk"`
+	UsesPermissions []androidUsesPermission `xml:"uses-permission"`
+	UsesFeatures    []androidUsesFeature    `xml:"uses-feature"`
+	Permissions     []androidPermission     `xml:"permission"`
+	Queries         []androidQueries        `xml:"queries"`
+	SupportsScreens androidSupportsScreens  `xml:"supports-screens"`
+	// CompatibleScreens are <screen> elements of <compatible-screens>.
+	CompatibleScreens []androidScreen          `xml:"compatible-screens>screen"`
+	Application       androidApplication       `xml:"application"`
+	Instrumentations  []androidInstrumentation `xml:"instrumentation"`
+	Module            *androidDistModule       `xml:"module"`
 }
 
-type androidManifest struct {
-	Package     string             `xml:"package,attr"`
-	VersionName string             `xml:"versionName,attr"`
-	VersionCode string             `xml:"versionCode,attr"`
-	Application androidApplication `xml:"application"`
+type androidUsesSdk struct {
+	MinSdkVersion    string `xml:"minSdkVersion,attr"`
+	TargetSdkVersion string `xml:"targetSdkVersion,attr"`
+}
+
+type androidUsesPermission struct {
+	Name string `xml:"name,attr"`
+}
+
+type androidUsesFeature struct {
+	Name     string `xml:"name,attr"`
+	Required string `xml:"required,attr"`
 }
 type iosProfile struct {
-	Platform             []string               `plist:"Platform"`
-	ProvisionedDevices   []string               `plist:"ProvisionedDevices"`
-	ProvisionsAllDevices bool                   `plist:"ProvisionsAllDevices"`
-	ExpirationDate       time.Time              `plist:"ExpirationDate"`
-	Entitlements         iosProfileEntitlements `plist:"Entitlements"`
+	Name                  string                 `plist:"Name"`
+	UUID                  string                 `plist:"UUID"`
+	TeamIdentifier        []string               `plist:"TeamIdentifier"`
+	TeamName              string                 `plist:"TeamName"`
+	Platform              []string               `plist:"Platform"`
+	ProvisionedDevices    []string               `plist:"ProvisionedDevices"`
+	ProvisionsAllDevices  bool                   `plist:"ProvisionsAllDevices"`
+	ExpirationDate        time.Time              `plist:"ExpirationDate"`
+	DeveloperCertificates [][]byte               `plist:"DeveloperCertificates"`
+	Entitlements          iosProfileEntitlements `plist:"Entitlements"`
+}
+
+// iosProfileRaw keeps the entitlements in full, iosProfile only the keys
+// used to tell the signing type.
+type iosProfileRaw struct {
+	Entitlements map[string]interface{} `plist:"Entitlements"`
 }
 
 type iosProfileEntitlements struct {
@@ -70,7 +100,48 @@ type iosProfileEntitlements struct {
 }
 
 type androidApplication struct {
-	Debuggable string `xml:"debuggable,attr"`
+	Name                string                 `xml:"name,attr"`
+	Label               string                 `xml:"label,attr"`
+	Icon                string                 `xml:"icon,attr"`
+	Process             string                 `xml:"process,attr"`
+	Debuggable          string                 `xml:"debuggable,attr"`
+	TestOnly            string                 `xml:"testOnly,attr"`
+	Theme               string                 `xml:"theme,attr"`
+	RoundIcon           string                 `xml:"roundIcon,attr"`
+	Banner              string                 `xml:"banner,attr"`
+	AllowBackup         string                 `xml:"allowBackup,attr"`
+	FullBackupContent   string                 `xml:"fullBackupContent,attr"`
+	DataExtractionRules string                 `xml:"dataExtractionRules,attr"`
+	AppCategory         string                 `xml:"appCategory,attr"`
+	Description         string                 `xml:"description,attr"`
+	Activities          []androidActivity      `xml:"activity"`
+	ActivityAliases     []androidActivityAlias `xml:"activity-alias"`
+	MetaData            []androidMetaData      `xml:"meta-data"`
+	Receivers           []androidComponent     `xml:"receiver"`
+	UsesLibraries       []androidUsesLibrary   `xml:"uses-library"`
+	Services            []androidComponent     `xml:"service"`
+	Providers           []androidProvider      `xml:"provider"`
+}
+
+type androidActivity struct {
+	Name          string                `xml:"name,attr"`
+	Enabled       string                `xml:"enabled,attr"`
+	IntentFilters []androidIntentFilter `xml:"intent-filter"`
+	MetaData      []androidMetaData     `xml:"meta-data"`
+}
+
+type androidActivityAlias struct {
+	androidActivity
+	TargetActivity string `xml:"targetActivity,attr"`
+}
+
+type androidIntentFilter struct {
+	Actions    []androidName `xml:"action"`
+	Categories []androidName `xml:"category"`
+}
+
+type androidName struct {
+	Name string `xml:"name,attr"`
 }
 type iosPlist struct {
 	CFBundleName         string `plist:"CFBundleName"`
@@ -78,82 +149,409 @@ type iosPlist struct {
 	CFBundleVersion      string `plist:"CFBundleVersion"`
 	CFBundleShortVersion string `plist:"CFBundleShortVersionString"`
 	CFBundleIdentifier   string `plist:"CFBundleIdentifier"`
+	CFBundleExecutable   string `plist:"CFBundleExecutable"`
+	CodePushKey          string `plist:"CodePushDeploymentKey"`
+	CodePushServerURL    string `plist:"CodePushServerURL"`
+	DTXcode              string `plist:"DTXcode"`
+	DTXcodeBuild         string `plist:"DTXcodeBuild"`
+	DTSDKName            string `plist:"DTSDKName"`
+	DTPlatformName       string `plist:"DTPlatformName"`
+	DTPlatformVersion    string `plist:"DTPlatformVersion"`
+	BuildMachineOSBuild  string `plist:"BuildMachineOSBuild"`
+
+	UISupportedInterfaceOrientations     []string `plist:"UISupportedInterfaceOrientations"`
+	UISupportedInterfaceOrientationsIpad []string `plist:"UISupportedInterfaceOrientations~ipad"`
+	UIRequiresFullScreen                 bool     `plist:"UIRequiresFullScreen"`
+	UILaunchStoryboardName               string   `plist:"UILaunchStoryboardName"`
+
+	NSUserTrackingUsageDescription string `plist:"NSUserTrackingUsageDescription"`
+	LSApplicationCategoryType      string `plist:"LSApplicationCategoryType"`
+
+	MinimumOSVersion       string `plist:"MinimumOSVersion"`
+	UIDeviceFamily         []int  `plist:"UIDeviceFamily"`
+	LSMinimumSystemVersion string `plist:"LSMinimumSystemVersion"`
+	// UIRequiredDeviceCapabilities is an array of capabilities or a
+	// dictionary of capabilities to booleans.
+	UIRequiredDeviceCapabilities interface{} `plist:"UIRequiredDeviceCapabilities"`
+
+	UIApplicationSceneManifest iosSceneManifest `plist:"UIApplicationSceneManifest"`
+
+	NSAppClip                      interface{} `plist:"NSAppClip"`
+	WKCompanionAppBundleIdentifier string      `plist:"WKCompanionAppBundleIdentifier"`
+}
+
+func NewAppParser(name string, opts ...Option) (info *AppInfo, err error) {
+	o := newOptions(opts)
+	end := o.startStage(StageParse)
+	defer func() { end(err) }()
+	return o.parseNamed(name, name)
+}
+
+// parseNamed parses the file file, reporting it to notifiers as name.
+func (o *options) parseNamed(file, name string) (*AppInfo, error) {
+	info, err := parseCached(file, o)
+	if info != nil {
+		if info.Environment == "" {
+			info.Environment = info.BuildEnvironment()
+		}
+		o.verifyProvenance(file, info)
+		o.notify(name, info)
+	}
+	return info, err
 }
 
-func NewAppParser(name string) (*AppInfo, error) {
+func parseCached(name string, o *options) (info *AppInfo, err error) {
+	// Bundle directories such as .xcframeworks have no single file to hash.
+	// Framework resources and passwords are not part of the key.
+	if fi, err := os.Stat(name); o.cache == nil || o.framework != nil || o.password != "" || err == nil && fi.IsDir() {
+		return o.applyExpiry(o.applyMode(parseAppFile(name, o)))
+	}
+
+	end := o.startStage(StageHash)
+	key, err := hashFile(name, o)
+	end(err)
+	if err != nil {
+		var errs stageErrors
+		errs.add(StageHash, err)
+		return nil, errs.err()
+	}
+	if o.scanURLs {
+		key += "+urls"
+	}
+	switch o.mode {
+	case ModeStrict:
+		key += "+strict"
+	case ModeLenient:
+		key += "+lenient"
+	}
+	if o.tolerantZip {
+		key += "+tolerant"
+	}
+	if o.recoverZip {
+		key += "+recover"
+	}
+	if info, ok := o.cache.Get(o.ctx, key); ok {
+		return o.applyExpiry(info, nil)
+	}
+
+	info, err = o.applyMode(parseAppFile(name, o))
+	if err == nil {
+		o.cache.Set(o.ctx, key, info)
+	}
+	return o.applyExpiry(info, err)
+}
+
+// parseAppFile parses name with the format registered for it, see
+// RegisterFormat.
+func parseAppFile(name string, o *options) (*AppInfo, error) {
+	end := o.startStage(StageZipRead)
 	file, err := os.Open(name)
+	var stat os.FileInfo
+	if err == nil {
+		if stat, err = file.Stat(); err != nil {
+			file.Close()
+		}
+	}
+	end(err)
 	if err != nil {
-		return nil, err
+		var errs stageErrors
+		errs.add(StageZipRead, err)
+		return nil, errs.err()
 	}
 	defer file.Close()
 
-	stat, err := file.Stat()
-	if err != nil {
-		return nil, err
+	var r io.ReaderAt = file
+	if o.progress != nil && !stat.IsDir() {
+		c := newProgressCounter(o, stat.Size())
+		defer c.finish()
+		r = progressReaderAt{file, c}
+	}
+	f := lookupFormat(name, stat, file)
+	if f == nil {
+		return nil, ErrUnknownFormat
+	}
+	info, err := o.parseFormat(f, name, r, stat.Size())
+	if info != nil {
+		info.sortSets()
+		if !stat.IsDir() {
+			info.Size = stat.Size()
+		}
 	}
+	return info, err
+}
 
-	reader, err := zip.NewReader(file, stat.Size())
-	if err != nil {
-		return nil, err
+func parseAab(_ string, reader *zip.Reader, o *options) (*AppInfo, error) {
+	var errs stageErrors
+	end := o.startStage(StageManifest)
+	info, err := parseAabFile(reader)
+	end(err)
+	errs.add(StageManifest, err)
+	return info, errs.err()
+}
+
+func parseApks(reader *zip.Reader, o *options) (*AppInfo, error) {
+	var errs stageErrors
+	end := o.startStage(StageManifest)
+	info, base, err := parseApksFile(reader)
+	end(err)
+	errs.add(StageManifest, err)
+	if info == nil {
+		return nil, errs.err()
 	}
 
-	var xmlFile, plistFile, iosIconFile, profileFile *zip.File
+	end = o.startStage(StageIcon)
+	baseReader, manifest, err := openNestedApk(base)
+	if err == nil {
+		var icon image.Image
+		icon, info.Name, info.Android.LabelSource, err = apkIconAndLabel(newApkResources(baseReader.File), manifest)
+		info.setIcon(icon)
+		info.Android.Signer = apkSigner(bytes.NewReader(base), int64(len(base)), baseReader.File)
+	}
+	end(err)
+	errs.add(StageIcon, err)
+	return info, errs.err()
+}
+
+// parseIpa returns no AppInfo if Info.plist or the provisioning profile
+// cannot be read, but runs the icon stage anyway so the returned
+// ParseError has every cause.
+func parseIpa(reader *zip.Reader, o *options) (*AppInfo, error) {
+	var plistFile, iconFile, profileFile, googleFile, odrFile *zip.File
 	for _, f := range reader.File {
 		switch {
-		case f.Name == "AndroidManifest.xml":
-			xmlFile = f
 		case reInfoPlist.MatchString(f.Name):
 			plistFile = f
+		case reGoogleServicesPlist.MatchString(f.Name):
+			googleFile = f
+		case reOnDemandResourcesPlist.MatchString(f.Name):
+			odrFile = f
 		case strings.Contains(f.Name, "AppIcon60x60"):
-			iosIconFile = f
-		case strings.Contains(f.Name, "embedded.mobileprovision"):
+			iconFile = f
+		case strings.HasSuffix(f.Name, "/"+profileFileName) && strings.Count(f.Name, "/") == 2:
 			profileFile = f
 		}
 	}
 
-	ext := filepath.Ext(stat.Name())
+	var errs stageErrors
+	end := o.startStage(StageManifest)
+	info, err := parseIpaFile(plistFile)
+	end(err)
+	errs.add(StageManifest, err)
 
-	if ext == androidExt {
-		info, err := parseApkFile(xmlFile)
-		icon, label, err := parseApkIconAndLabel(name)
-		info.Name = label
-		info.Icon = icon
-		info.Size = stat.Size()
-		return info, err
+	end = o.startStage(StageProfile)
+	profile, err := parseIpaProfile(profileFile)
+	if profile == nil && info != nil && info.Ios.Simulator {
+		err = fmt.Errorf("%w: %v", ErrSimulatorBuild, err)
 	}
-
-	if ext == iosExt {
-		info, err := parseIpaFile(plistFile)
-		profileInfo, err := parseIpaProfile(profileFile)
-		if err != nil {
-			return nil, err
+	var bundleProfiles []BundleProfile
+	if profile != nil {
+		var nestedErr error
+		bundleProfiles, nestedErr = parseIpaNestedProfiles(reader.File)
+		if err == nil {
+			err = nestedErr
 		}
-		icon, err := parseIpaIcon(iosIconFile)
-		info.Icon = icon
-		info.Size = stat.Size()
-		info.IosPlatform = profileInfo.IosPlatform
-		info.IosSigningType = profileInfo.IosSigningType
-		info.IosSigningExpirationDate = profileInfo.IosSigningExpirationDate
-		info.IosProvisionedDevices = profileInfo.IosProvisionedDevices
-		return info, err
+	}
+	end(err)
+	errs.add(StageProfile, err)
+
+	end = o.startStage(StageIcon)
+	icon, err := parseIpaIcon(iconFile)
+	end(err)
+	errs.add(StageIcon, err)
+
+	// Without a profile, e.g. in unsigned Simulator builds, the rest is
+	// still read; the mode decides whether the result is returned.
+	if info == nil {
+		return nil, errs.err()
+	}
+	info.setIcon(icon)
+	info.Ios.Profile = profile
+	info.Ios.BundleProfiles = bundleProfiles
+
+	end = o.startStage(StageBinary)
+	binaries, err := parseIpaBinaries(reader.File)
+	if err == nil {
+		var sdks sdkScan
+		sdks, err = scanIpaSDKs(reader.File)
+		info.Integrity = newIntegrityInfo(sdks)
+		info.Billing = newIpaBilling(sdks)
+		info.Tracking = newIpaTracking(sdks, info)
+	}
+	end(err)
+	errs.add(StageBinary, err)
+	info.Ios.Binaries = binaries
+	for _, b := range binaries {
+		info.Ios.FairPlay = info.Ios.FairPlay || b.Encrypted
+	}
+	for _, f := range reader.File {
+		info.Ios.FairPlay = info.Ios.FairPlay || isFairPlayFile(f.Name)
+	}
+	info.Test = newIpaTestInfo(reader.File)
+	checkExecutable(info, reader.File)
+	checkSimulator(info)
+	checkMac(info.Ios)
+	checkVision(info.Ios)
+
+	checkProfiles(info)
+	checkIpaSupportFolders(reader.File, info)
+	if google, err := parseIpaGoogleServices(googleFile); err == nil {
+		info.GoogleServices = google
+	}
+	if tags, err := parseIpaOnDemandResources(odrFile, reader.File); err == nil {
+		info.Ios.OnDemandResources = tags
+	}
+	info.Hybrid = parseIpaHybrid(reader.File, info.Hybrid)
+	if o.scanURLs {
+		end = o.startStage(StageURLScan)
+		info.Hosts, err = scanIpaHosts(reader.File)
+		end(err)
+		errs.add(StageURLScan, err)
+	}
+	return info, errs.err()
+}
+
+// parseApk returns no AppInfo only if the manifest cannot be read. Later
+// stages add their errors to the returned ParseError.
+func parseApk(_ string, reader *zip.Reader, o *options) (*AppInfo, error) {
+	var errs stageErrors
+	res := newApkResources(reader.File)
+	xmlFile := findZipFile(reader.File, "AndroidManifest.xml")
+
+	end := o.startStage(StageManifest)
+	if o.framework != nil {
+		var err error
+		res.framework, err = o.framework.load()
+		errs.add(StageManifest, err)
+	}
+	info, manifest, err := parseApkFile(xmlFile)
+	if err == nil {
+		parseBackupRules(res, info.Android.Backup)
+		info.Android.ABIs = apkABIs(reader.File)
+		info.Android.BaselineProfile = parseBaselineProfile(reader.File)
+	}
+	end(err)
+	errs.add(StageManifest, err)
+	if info == nil {
+		return nil, errs.err()
 	}
 
-	return nil, errors.New("unknown platform")
+	// Each scan runs even when another failed, so a bad classes.dex does
+	// not hide the native libraries.
+	end = o.startStage(StageBinary)
+	var binErrs stageErrors
+	binErrs.add(StageBinary, scanDexFiles(reader.File, info.Android))
+	info.Android.NativeLibs, err = parseApkNativeLibs(reader.File)
+	binErrs.add(StageBinary, err)
+	info.Android.PageSize16K = pageSize16KReady(info.Android.NativeLibs)
+	sdks, err := scanApkSDKs(reader.File)
+	binErrs.add(StageBinary, err)
+	info.Integrity = newIntegrityInfo(sdks)
+	info.Billing = newApkBilling(sdks, reader.File, info.Android)
+	info.Tracking = newApkTracking(sdks, info)
+	end(binErrs.err())
+	errs = append(errs, binErrs...)
+
+	// The icon and label are resolved from reader rather than by reopening
+	// name, which may have been repaired, recovered or decrypted.
+	end = o.startStage(StageIcon)
+	icon, label, source, err := apkIconAndLabel(res, manifest)
+	end(err)
+	errs.add(StageIcon, err)
+	info.Name, info.Android.LabelSource = label, source
+	info.Description = res.stringValue(info.Description)
+	info.setIcon(icon)
+	info.Android.RoundIcon = res.image(manifest.Application.RoundIcon)
+	info.Android.Banner = res.image(manifest.Application.Banner)
+	info.Android.Resources = resourceStats(res)
+	parseApkTheme(res, manifest, info.Android)
+	for i, p := range info.Android.DefinedPermissions {
+		info.Android.DefinedPermissions[i].Label = res.stringValue(p.Label)
+		info.Android.DefinedPermissions[i].Description = res.stringValue(p.Description)
+	}
+	parseApkComponents(res, manifest, info.Android)
+	parsePrivilegedComponents(res, manifest, info.Android)
+	parseApkAuto(res, manifest, info.Android)
+	info.GoogleServices = parseApkGoogleServices(res)
+	info.Hybrid = parseApkHybrid(reader.File, res, manifest)
+
+	if o.scanURLs {
+		end = o.startStage(StageURLScan)
+		info.Hosts, err = scanApkHosts(reader.File, res)
+		end(err)
+		errs.add(StageURLScan, err)
+	}
+	return info, errs.err()
 }
 
-func parseAndroidManifest(xmlFile *zip.File) (*androidManifest, error) {
-	rc, err := xmlFile.Open()
+func openZipFile(name string) (*os.File, os.FileInfo, *zip.Reader, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, nil, err
+	}
+
+	reader, err := zip.NewReader(file, stat.Size())
+	if err != nil {
+		file.Close()
+		return nil, nil, nil, err
+	}
+	return file, stat, reader, nil
+}
+
+// maxEntrySize bounds the archive entries read into memory; the binary XML
+// and plist decoders need random access, so their entries are buffered.
+const maxEntrySize = 256 << 20
+
+// initialEntryBuffer caps the buffer readZipFile starts with; the header's
+// uncompressed size is untrusted until the data is read.
+const initialEntryBuffer = 1 << 20
+
+// readZipFile reads f into a buffer that grows with the data, starting at
+// its uncompressed size up to initialEntryBuffer. archive/zip checks the
+// size against the data.
+func readZipFile(f *zip.File) ([]byte, error) {
+	if f.UncompressedSize64 > maxEntrySize {
+		return nil, fmt.Errorf("%s: %w", f.Name, ErrEntryTooLarge)
+	}
+	rc, err := f.Open()
 	if err != nil {
 		return nil, err
 	}
 	defer rc.Close()
 
-	buf, err := ioutil.ReadAll(rc)
-	if err != nil {
+	n := f.UncompressedSize64
+	if n > initialEntryBuffer {
+		n = initialEntryBuffer
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, n))
+	if _, err := buf.ReadFrom(io.LimitReader(rc, maxEntrySize+1)); err != nil {
 		return nil, err
 	}
+	if buf.Len() > maxEntrySize {
+		return nil, fmt.Errorf("%s: %w", f.Name, ErrEntryTooLarge)
+	}
+	return buf.Bytes(), nil
+}
+
+func parseAndroidManifest(xmlFile *zip.File) (*androidManifest, error) {
+	buf, err := readZipFile(xmlFile)
+	if err == nil {
+		var manifest *androidManifest
+		if manifest, err = decodeAndroidManifest(buf); err == nil {
+			return manifest, nil
+		}
+	}
+	return nil, entryError(xmlFile.Name, err)
+}
 
-	xmlContent, err := androidbinary.NewXMLFile(bytes.NewReader(buf))
+// decodeAndroidManifest decodes a binary AndroidManifest.xml.
+func decodeAndroidManifest(buf []byte) (*androidManifest, error) {
+	xmlContent, err := decodeAndroidXML(buf)
 	if err != nil {
 		return nil, err
 	}
@@ -166,42 +564,146 @@ func parseAndroidManifest(xmlFile *zip.File) (*androidManifest, error) {
 	return manifest, nil
 }
 
-func parseApkFile(xmlFile *zip.File) (*AppInfo, error) {
+func parseApkFile(xmlFile *zip.File) (*AppInfo, *androidManifest, error) {
 	if xmlFile == nil {
-		return nil, errors.New("AndroidManifest.xml not found")
+		return nil, nil, errors.New("AndroidManifest.xml not found")
 	}
 
 	manifest, err := parseAndroidManifest(xmlFile)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+	return newAndroidAppInfo(manifest), manifest, nil
+}
 
-	info := new(AppInfo)
+func newAndroidAppInfo(manifest *androidManifest) *AppInfo {
+	info := newAppInfo(PlatformAndroid)
 	info.BundleId = manifest.Package
 	info.Version = manifest.VersionName
 	info.Build = manifest.VersionCode
-	info.ApkDebug = manifest.Application.Debuggable == "true"
+	info.Android.Debug = manifest.Application.Debuggable == "true"
+	info.Category = androidCategory(manifest.Application.AppCategory)
+	info.Description = manifest.Application.Description
+	info.Android.MinSdkVersion = manifest.UsesSdk.MinSdkVersion
+	info.Android.TargetSdkVersion = manifest.UsesSdk.TargetSdkVersion
+	info.Android.MainActivity = manifest.launcherActivity()
+	info.Android.ApplicationClass = manifest.className(manifest.Application.Name)
+	info.Android.SharedUserId = manifest.SharedUserId
+	info.Android.InstallLocation = installLocation(manifest.InstallLocation)
+	info.Android.CompileSdkVersion = manifest.CompileSdk
+	if info.Android.CompileSdkVersion == "" {
+		info.Android.CompileSdkVersion = manifest.PlatformBuild
+	}
+	info.Android.Backup = &BackupInfo{
+		AllowBackup:         manifest.Application.AllowBackup != "false",
+		FullBackupContent:   manifest.Application.FullBackupContent,
+		DataExtractionRules: manifest.Application.DataExtractionRules,
+	}
+	info.Android.IsInstantApp = manifest.SandboxVersion == "2" || (manifest.Module != nil && manifest.Module.Instant == "true")
+	info.Android.ProcessName = manifest.Application.Process
+	if info.Android.ProcessName == "" {
+		info.Android.ProcessName = manifest.Package
+	} else if strings.HasPrefix(info.Android.ProcessName, ":") {
+		info.Android.ProcessName = manifest.Package + info.Android.ProcessName
+	}
+	for _, p := range manifest.UsesPermissions {
+		info.Android.Permissions = append(info.Android.Permissions, p.Name)
+	}
+	for _, f := range manifest.UsesFeatures {
+		// <uses-feature android:glEsVersion> has no name.
+		if f.Name == "" {
+			continue
+		}
+		if f.Required == "false" {
+			info.Android.OptionalFeatures = append(info.Android.OptionalFeatures, f.Name)
+		} else {
+			info.Android.RequiredFeatures = append(info.Android.RequiredFeatures, f.Name)
+		}
+	}
+	info.Android.DefinedPermissions = definedPermissions(manifest)
+	info.Android.Visibility = newPackageVisibility(manifest, info.Android.Permissions)
+	info.Android.Wear = newWearInfo(manifest, info)
+	info.Test = newAndroidTestInfo(manifest)
+	info.Android.StartupInitializers = startupInitializers(manifest)
+	parseForegroundServices(manifest, info)
+	parseProviders(manifest, info)
+	info.Android.Screens = newScreenSupport(manifest)
+	if d := densitySplit(manifest.Split); d != "" {
+		info.Android.Screens.DensitySplits = []string{d}
+	}
+	return info
+}
 
-	return info, nil
+// installLocation returns the name of an android:installLocation value,
+// which binary manifests store as a number.
+func installLocation(v string) string {
+	switch v {
+	case "0":
+		return "auto"
+	case "1":
+		return "internalOnly"
+	case "2":
+		return "preferExternal"
+	}
+	return v
 }
 
-func parseApkIconAndLabel(name string) (image.Image, string, error) {
-	pkg, err := apk.OpenFile(name)
-	if err != nil {
-		return nil, "", err
+// className resolves a manifest class name such as ".MainActivity" against
+// the package.
+func (m *androidManifest) className(name string) string {
+	if strings.HasPrefix(name, ".") {
+		return m.Package + name
 	}
-	defer pkg.Close()
+	if name != "" && !strings.Contains(name, ".") {
+		return m.Package + "." + name
+	}
+	return name
+}
 
-	icon, _ := pkg.Icon(&androidbinary.ResTableConfig{
-		Density: 720,
-	})
-	if icon == nil {
-		return nil, "", ErrNoIcon
+// launcherActivity returns the activity started from the home screen, the
+// first enabled one handling MAIN/LAUNCHER. Aliases resolve to their target.
+func (m *androidManifest) launcherActivity() string {
+	for _, a := range m.Application.Activities {
+		if a.isLauncher() {
+			return m.className(a.Name)
+		}
 	}
+	for _, a := range m.Application.ActivityAliases {
+		if a.isLauncher() {
+			return m.className(a.TargetActivity)
+		}
+	}
+	return ""
+}
 
-	label, _ := pkg.Label(nil)
+func (a *androidActivity) isLauncher() bool {
+	if a.Enabled == "false" {
+		return false
+	}
+	for _, f := range a.IntentFilters {
+		var main, launcher bool
+		for _, action := range f.Actions {
+			main = main || action.Name == "android.intent.action.MAIN"
+		}
+		for _, category := range f.Categories {
+			launcher = launcher || category.Name == "android.intent.category.LAUNCHER"
+		}
+		if main && launcher {
+			return true
+		}
+	}
+	return false
+}
 
-	return icon, label, nil
+// apkIconAndLabel resolves the launcher icon and the label of an APK from
+// its own resources.
+func apkIconAndLabel(res *apkResources, manifest *androidManifest) (image.Image, string, string, error) {
+	label, source := apkLabel(res, manifest)
+	icon := res.image(manifest.Application.Icon)
+	if icon == nil {
+		return nil, label, source, ErrNoIcon
+	}
+	return icon, label, source, nil
 }
 
 func parseIpaFile(plistFile *zip.File) (*AppInfo, error) {
@@ -209,24 +711,22 @@ func parseIpaFile(plistFile *zip.File) (*AppInfo, error) {
 		return nil, errors.New("info.plist not found")
 	}
 
-	rc, err := plistFile.Open()
+	buf, err := readZipFile(plistFile)
 	if err != nil {
-		return nil, err
-	}
-	defer rc.Close()
-
-	buf, err := ioutil.ReadAll(rc)
-	if err != nil {
-		return nil, err
+		return nil, entryError(plistFile.Name, err)
 	}
+	info, err := decodeInfoPlist(buf)
+	return info, entryError(plistFile.Name, err)
+}
 
+// decodeInfoPlist decodes the Info.plist of an app.
+func decodeInfoPlist(buf []byte) (*AppInfo, error) {
 	p := new(iosPlist)
-	decoder := plist.NewDecoder(bytes.NewReader(buf))
-	if err := decoder.Decode(p); err != nil {
+	if err := decodePlist(buf, p); err != nil {
 		return nil, err
 	}
 
-	info := new(AppInfo)
+	info := newAppInfo(PlatformIOS)
 	if p.CFBundleDisplayName == "" {
 		info.Name = p.CFBundleName
 	} else {
@@ -235,10 +735,53 @@ func parseIpaFile(plistFile *zip.File) (*AppInfo, error) {
 	info.BundleId = p.CFBundleIdentifier
 	info.Version = p.CFBundleShortVersion
 	info.Build = p.CFBundleVersion
+	info.Category = p.LSApplicationCategoryType
+	info.Ios.Xcode = xcodeVersion(p.DTXcode)
+	info.Ios.XcodeBuild = p.DTXcodeBuild
+	info.Ios.SDKName = p.DTSDKName
+	info.Ios.PlatformVersion = p.DTPlatformVersion
+	info.Ios.Simulator = strings.HasSuffix(p.DTPlatformName, "simulator")
+	info.Ios.BuildMachineOSBuild = p.BuildMachineOSBuild
+	info.Ios.Orientations = p.UISupportedInterfaceOrientations
+	info.Ios.IpadOrientations = p.UISupportedInterfaceOrientationsIpad
+	if info.Ios.IpadOrientations == nil {
+		info.Ios.IpadOrientations = p.UISupportedInterfaceOrientations
+	}
+	info.Ios.RequiresFullScreen = p.UIRequiresFullScreen
+	info.Ios.LaunchStoryboard = p.UILaunchStoryboardName
+	info.Ios.MinimumOSVersion = p.MinimumOSVersion
+	info.Ios.Executable = p.CFBundleExecutable
+	for _, f := range p.UIDeviceFamily {
+		info.Ios.DeviceFamilies = append(info.Ios.DeviceFamilies, iosDeviceFamily(f))
+	}
+	info.Ios.RequiredCapabilities = requiredCapabilities(p.UIRequiredDeviceCapabilities)
+	info.Ios.MinimumMacOSVersion = p.LSMinimumSystemVersion
+	info.Ios.VisionOS = strings.HasPrefix(p.DTPlatformName, "xr")
+	info.Ios.DefaultSceneRole = p.UIApplicationSceneManifest.UIApplicationPreferredDefaultSceneSessionRole
+	_, info.Ios.ImmersiveSpace = p.UIApplicationSceneManifest.UISceneConfigurations[sceneRoleImmersiveSpace]
+	info.Ios.AppClip = p.NSAppClip != nil
+	info.Ios.CompanionAppBundleId = p.WKCompanionAppBundleIdentifier
+	info.Hybrid = codePushHybrid(p.CodePushKey, p.CodePushServerURL)
+	if p.NSUserTrackingUsageDescription != "" {
+		info.Tracking = &TrackingInfo{UsageDescription: p.NSUserTrackingUsageDescription}
+	}
 
 	return info, nil
 }
 
+// xcodeVersion turns DTXcode, such as 1420 or 0941, into 14.2 or 9.4.1.
+func xcodeVersion(v string) string {
+	v = strings.TrimLeft(v, "0")
+	if len(v) < 3 {
+		return v
+	}
+	major, minor, patch := v[:len(v)-2], v[len(v)-2:len(v)-1], v[len(v)-1:]
+	if patch == "0" {
+		return major + "." + minor
+	}
+	return major + "." + minor + "." + patch
+}
+
 func parseIpaIcon(iconFile *zip.File) (image.Image, error) {
 	if iconFile == nil {
 		return nil, ErrNoIcon
@@ -251,12 +794,20 @@ func parseIpaIcon(iconFile *zip.File) (image.Image, error) {
 	defer rc.Close()
 
 	var w bytes.Buffer
-	iospng.PngRevertOptimization(rc, &w)
+	revertErr := iospng.PngRevertOptimization(rc, &w)
 
-	return png.Decode(bytes.NewReader(w.Bytes()))
+	img, err := png.Decode(bytes.NewReader(w.Bytes()))
+	if err != nil {
+		// a failed revert explains the decode error better
+		if revertErr != nil {
+			err = fmt.Errorf("iospng: %w", revertErr)
+		}
+		return nil, entryError(iconFile.Name, err)
+	}
+	return img, nil
 }
 
-func parseIpaProfile(porfileFile *zip.File) (*AppInfo, error) {
+func parseIpaProfile(porfileFile *zip.File) (*ProvisioningProfile, error) {
 	//# if ProvisionedDevices: !nil & "get-task-allow": true -> development
 	//# if ProvisionedDevices: !nil & "get-task-allow": false -> ad-hoc
 	//# if ProvisionedDevices: nil & "ProvisionsAllDevices": "true" -> enterprise
@@ -265,18 +816,27 @@ func parseIpaProfile(porfileFile *zip.File) (*AppInfo, error) {
 		return nil, errors.New("profile not found")
 	}
 
-	rc, err := porfileFile.Open()
+	b, err := readZipFile(porfileFile)
 	if err != nil {
-		return nil, errors.New("profile not found")
+		return nil, entryError(porfileFile.Name, fmt.Errorf("failed to read pkcs7 data: %w", err))
 	}
-	defer rc.Close()
-	profileData, err := loadPKCS7Content(rc)
+	profile, err := decodeProfile(b)
+	return profile, entryError(porfileFile.Name, err)
+}
+
+// decodeProfile decodes a provisioning profile, returning it with
+// ErrProfileUnverified when its signature does not verify.
+func decodeProfile(b []byte) (*ProvisioningProfile, error) {
+	profileData, err := loadPKCS7Content(b)
 	if err != nil {
 		log.Printf(err.Error())
 	}
-	decoder := plist.NewDecoder(bytes.NewReader(profileData))
+	verifyErr := err
+	if !errors.Is(verifyErr, ErrProfileUnverified) {
+		verifyErr = nil
+	}
 	profile := new(iosProfile)
-	if err := decoder.Decode(profile); err != nil {
+	if err := decodePlist(profileData, profile); err != nil {
 		log.Printf(err.Error())
 		return nil, err
 	}
@@ -299,26 +859,34 @@ func parseIpaProfile(porfileFile *zip.File) (*AppInfo, error) {
 			signing = "app-store"
 		}
 	}
-	appInfo := AppInfo{}
-	appInfo.IosPlatform = profile.Platform
-	appInfo.IosProvisionedDevices = profile.ProvisionedDevices
-	appInfo.IosSigningType = signing
-	appInfo.IosSigningExpirationDate = strconv.FormatInt(profile.ExpirationDate.Unix(), 10)
-	return &appInfo, nil
+	p := new(ProvisioningProfile)
+	p.Name = profile.Name
+	p.UUID = profile.UUID
+	if len(profile.TeamIdentifier) > 0 {
+		p.TeamId = profile.TeamIdentifier[0]
+	}
+	p.TeamName = profile.TeamName
+	p.Platform = profile.Platform
+	p.SigningType = signing
+	p.ExpirationDate = profile.ExpirationDate
+	p.ProvisionedDevices = profile.ProvisionedDevices
+	p.Certificates = parseCertificates(profile.DeveloperCertificates)
+	raw := new(iosProfileRaw)
+	if err := decodePlist(profileData, raw); err == nil {
+		p.Entitlements = raw.Entitlements
+	}
+	p.Data = profileData
+	return p, verifyErr
 
 }
 
-func loadPKCS7Content(r io.Reader) ([]byte, error) {
-	b, err := ioutil.ReadAll(r)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read pkcs7 data: %s", err)
-	}
+func loadPKCS7Content(b []byte) ([]byte, error) {
 	msg, err := pkcs7.Parse(b)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse pkcs7: %s", err)
 	}
 	if err := msg.Verify(); err != nil {
-		return nil, fmt.Errorf("failed to verify: %s", err)
+		return msg.Content, fmt.Errorf("%w: %s", ErrProfileUnverified, err)
 	}
 	return msg.Content, nil
 }
@@ -3,8 +3,11 @@ package appfile
 import (
 	"archive/zip"
 	"bytes"
+	"errors"
 	"image/png"
 	"os"
+	"reflect"
+	"runtime"
 	"strings"
 	"testing"
 )
@@ -67,7 +70,7 @@ func TestParseApkFile(t *testing.T) {
 	if err != nil {
 		t.Errorf("got %v want no error", err)
 	}
-	apk, err := parseApkFile(xmlFile)
+	apk, _, err := parseApkFile(xmlFile)
 	if err != nil {
 		t.Errorf("got %v want no error", err)
 	}
@@ -80,22 +83,99 @@ func TestParseApkFile(t *testing.T) {
 	if apk.Build != "1" {
 		t.Errorf("got %v want %v", apk.Build, "1")
 	}
+	if apk.Platform != PlatformAndroid {
+		t.Errorf("got %v want %v", apk.Platform, PlatformAndroid)
+	}
+	if apk.Android.MinSdkVersion != "15" {
+		t.Errorf("got %v want %v", apk.Android.MinSdkVersion, "15")
+	}
+	if apk.Android.TargetSdkVersion != "24" {
+		t.Errorf("got %v want %v", apk.Android.TargetSdkVersion, "24")
+	}
+	if !apk.Android.Debug {
+		t.Errorf("got %v want %v", apk.Android.Debug, true)
+	}
+	if apk.Android.MainActivity != "com.example.helloworld.MainActivity" {
+		t.Errorf("got %v want %v", apk.Android.MainActivity, "com.example.helloworld.MainActivity")
+	}
+	if apk.Android.ProcessName != "com.example.helloworld" {
+		t.Errorf("got %v want %v", apk.Android.ProcessName, "com.example.helloworld")
+	}
+	if apk.Android.CompileSdkVersion != "24" {
+		t.Errorf("got %v want %v", apk.Android.CompileSdkVersion, "24")
+	}
+	if apk.Android.SharedUserId != "" || apk.Android.InstallLocation != "" {
+		t.Errorf("got %q %q want empty", apk.Android.SharedUserId, apk.Android.InstallLocation)
+	}
+	if apk.Android.Backup == nil || !apk.Android.Backup.AllowBackup {
+		t.Errorf("got %+v want allow_backup", apk.Android.Backup)
+	}
+	if apk.Android.IsInstantApp {
+		t.Errorf("got %v want %v", apk.Android.IsInstantApp, false)
+	}
+}
+
+func TestInstallLocation(t *testing.T) {
+	for v, want := range map[string]string{"": "", "1": "internalOnly", "2": "preferExternal", "auto": "auto"} {
+		if got := installLocation(v); got != want {
+			t.Errorf("got %v want %v", got, want)
+		}
+	}
 }
 
-func TestParseApkIconAndLabel(t *testing.T) {
-	icon, label, err := parseApkIconAndLabel("testdata/helloworld.apk")
+func TestLauncherActivity(t *testing.T) {
+	launcher := []androidIntentFilter{{
+		Actions:    []androidName{{"android.intent.action.MAIN"}},
+		Categories: []androidName{{"android.intent.category.LAUNCHER"}},
+	}}
+	m := &androidManifest{Package: "com.example"}
+	m.Application.Activities = []androidActivity{
+		{Name: ".Splash", Enabled: "false", IntentFilters: launcher},
+		{Name: "Settings"},
+	}
+	m.Application.ActivityAliases = []androidActivityAlias{
+		{androidActivity{Name: ".Alias", IntentFilters: launcher}, ".Home"},
+	}
+	if got := m.launcherActivity(); got != "com.example.Home" {
+		t.Errorf("got %v want %v", got, "com.example.Home")
+	}
+	if got := m.className("Settings"); got != "com.example.Settings" {
+		t.Errorf("got %v want %v", got, "com.example.Settings")
+	}
+}
+
+func TestApkIconAndLabel(t *testing.T) {
+	reader, err := getAppZipReader("testdata/helloworld.apk")
 	if err != nil {
-		t.Errorf("got %v want no error", err)
+		t.Fatal(err)
+	}
+	xmlFile, err := getAndroidManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, manifest, err := parseApkFile(xmlFile)
+	if err != nil {
+		t.Fatal(err)
 	}
-	buf := new(bytes.Buffer)
-	if err := png.Encode(buf, icon); err != nil {
+	icon, label, source, err := apkIconAndLabel(newApkResources(reader.File), manifest)
+	if err != nil {
 		t.Errorf("got %v want no error", err)
 	}
-	if len(buf.Bytes()) != 10223 {
-		t.Errorf("got %v want %v", len(buf.Bytes()), 10223)
+	// The launcher icon at the highest density.
+	rc, err := findZipFile(reader.File, "res/mipmap-xxxhdpi-v4/ic_launcher.png").Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	want, err := png.Decode(rc)
+	if err != nil {
+		t.Fatal(err)
 	}
-	if label != "HelloWorld" {
-		t.Errorf("got %v want %v", label, "HelloWorld")
+	if icon == nil || !reflect.DeepEqual(icon, want) {
+		t.Errorf("got %v want the xxxhdpi launcher icon", icon)
+	}
+	if label != "HelloWorld" || source != LabelSourceResource {
+		t.Errorf("got %v, %v want %v, %v", label, source, "HelloWorld", LabelSourceResource)
 	}
 }
 
@@ -132,6 +212,12 @@ func TestParseIpaFile(t *testing.T) {
 	if ipa.Build != "1.0" {
 		t.Errorf("got %v want %v", ipa.Build, "1.0")
 	}
+	if ipa.Ios.Xcode != "4.2.1" || ipa.Ios.SDKName != "iphoneos5.0" {
+		t.Errorf("got %v %v want %v %v", ipa.Ios.Xcode, ipa.Ios.SDKName, "4.2.1", "iphoneos5.0")
+	}
+	if len(ipa.Ios.Orientations) != 3 || len(ipa.Ios.IpadOrientations) != 4 {
+		t.Errorf("got %v and %v want 3 and 4 orientations", ipa.Ios.Orientations, ipa.Ios.IpadOrientations)
+	}
 }
 
 func TestParseIpaIcon(t *testing.T) {
@@ -150,3 +236,56 @@ func TestParseIpaIcon(t *testing.T) {
 		t.Errorf("got %v want %v", err, ErrNoIcon)
 	}
 }
+
+func TestXcodeVersion(t *testing.T) {
+	for v, want := range map[string]string{
+		"1420": "14.2",
+		"0941": "9.4.1",
+		"1500": "15.0",
+		"":     "",
+	} {
+		if got := xcodeVersion(v); got != want {
+			t.Errorf("got %v want %v", got, want)
+		}
+	}
+}
+
+func TestReadZipFileTooLarge(t *testing.T) {
+	f := &zip.File{FileHeader: zip.FileHeader{Name: "Payload/x.app/Info.plist", UncompressedSize64: maxEntrySize + 1}}
+	if _, err := readZipFile(f); !errors.Is(err, ErrEntryTooLarge) {
+		t.Errorf("got %v want %v", err, ErrEntryTooLarge)
+	}
+}
+
+func TestReadZipFileClaimedSize(t *testing.T) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	data := []byte("tiny")
+	fw, err := w.CreateRaw(&zip.FileHeader{Name: "AndroidManifest.xml", Method: zip.Store, CompressedSize64: uint64(len(data)), UncompressedSize64: maxEntrySize})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw.Write(data)
+	w.Close()
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	if _, err := readZipFile(reader.File[0]); err == nil {
+		t.Errorf("got no error want one for the size mismatch")
+	}
+	runtime.ReadMemStats(&after)
+	if n := after.TotalAlloc - before.TotalAlloc; n > 8<<20 {
+		t.Errorf("got %d bytes allocated want at most %d", n, 8<<20)
+	}
+}
+
+func TestParseIpaProfileTooLarge(t *testing.T) {
+	f := &zip.File{FileHeader: zip.FileHeader{Name: "Payload/x.app/embedded.mobileprovision", UncompressedSize64: maxEntrySize + 1}}
+	if _, err := parseIpaProfile(f); !errors.Is(err, ErrEntryTooLarge) {
+		t.Errorf("got %v want %v", err, ErrEntryTooLarge)
+	}
+}
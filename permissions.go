@@ -0,0 +1,211 @@
+package appfile
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Permission protection levels, see
+// https://developer.android.com/guide/topics/permissions/overview.
+const (
+	ProtectionNormal    = "normal"
+	ProtectionDangerous = "dangerous"
+	ProtectionSignature = "signature"
+	ProtectionUnknown   = "unknown"
+)
+
+// Permission describes an Android permission.
+type Permission struct {
+	Name            string `json:"name"`
+	ProtectionLevel string `json:"protection_level"`
+	Label           string `json:"label,omitempty"`
+	Description     string `json:"description,omitempty"`
+}
+
+// knownPermissions are the platform permissions by their name without the
+// "android.permission." prefix, plus common library permissions by their
+// full name. Levels are those of the latest platform release.
+var knownPermissions = map[string]Permission{
+	// dangerous (runtime) permissions
+	"ACCEPT_HANDOVER":                 {ProtectionLevel: ProtectionDangerous, Label: "continue a call from another app", Description: "Allows the app to continue a call which was started in another app."},
+	"ACCESS_BACKGROUND_LOCATION":      {ProtectionLevel: ProtectionDangerous, Label: "access location in the background", Description: "This app can access location at any time, even while the app is not in use."},
+	"ACCESS_COARSE_LOCATION":          {ProtectionLevel: ProtectionDangerous, Label: "access approximate location", Description: "This app can get your approximate location from network sources."},
+	"ACCESS_FINE_LOCATION":            {ProtectionLevel: ProtectionDangerous, Label: "access precise location", Description: "This app can get your precise location from location services."},
+	"ACCESS_MEDIA_LOCATION":           {ProtectionLevel: ProtectionDangerous, Label: "read locations from your media collection", Description: "Allows the app to read locations from your media collection."},
+	"ACTIVITY_RECOGNITION":            {ProtectionLevel: ProtectionDangerous, Label: "recognize physical activity", Description: "This app can recognize your physical activity."},
+	"ADD_VOICEMAIL":                   {ProtectionLevel: ProtectionDangerous, Label: "add voicemail", Description: "Allows the app to add messages to your voicemail inbox."},
+	"ANSWER_PHONE_CALLS":              {ProtectionLevel: ProtectionDangerous, Label: "answer phone calls", Description: "Allows the app to answer an incoming phone call."},
+	"BLUETOOTH_ADVERTISE":             {ProtectionLevel: ProtectionDangerous, Label: "advertise to nearby Bluetooth devices", Description: "Allows the app to advertise to nearby Bluetooth devices."},
+	"BLUETOOTH_CONNECT":               {ProtectionLevel: ProtectionDangerous, Label: "connect to paired Bluetooth devices", Description: "Allows the app to connect to paired Bluetooth devices."},
+	"BLUETOOTH_SCAN":                  {ProtectionLevel: ProtectionDangerous, Label: "discover and pair nearby Bluetooth devices", Description: "Allows the app to discover and pair nearby Bluetooth devices."},
+	"BODY_SENSORS":                    {ProtectionLevel: ProtectionDangerous, Label: "access body sensors", Description: "Allows the app to access data from sensors that monitor your physical condition, such as your heart rate."},
+	"BODY_SENSORS_BACKGROUND":         {ProtectionLevel: ProtectionDangerous, Label: "access body sensors in the background", Description: "Allows the app to access body sensor data while the app is in the background."},
+	"CALL_PHONE":                      {ProtectionLevel: ProtectionDangerous, Label: "directly call phone numbers", Description: "Allows the app to call phone numbers without your intervention."},
+	"CAMERA":                          {ProtectionLevel: ProtectionDangerous, Label: "take pictures and videos", Description: "This app can take pictures and record videos using the camera while the app is in use."},
+	"GET_ACCOUNTS":                    {ProtectionLevel: ProtectionDangerous, Label: "find accounts on the device", Description: "Allows the app to get the list of accounts known by the phone."},
+	"NEARBY_WIFI_DEVICES":             {ProtectionLevel: ProtectionDangerous, Label: "interact with nearby Wi-Fi devices", Description: "Allows the app to advertise, connect, and determine the relative position of nearby Wi-Fi devices."},
+	"POST_NOTIFICATIONS":              {ProtectionLevel: ProtectionDangerous, Label: "show notifications", Description: "Allows the app to show notifications."},
+	"PROCESS_OUTGOING_CALLS":          {ProtectionLevel: ProtectionDangerous, Label: "reroute outgoing calls", Description: "Allows the app to see the number being dialed during an outgoing call with the option to redirect the call."},
+	"READ_CALENDAR":                   {ProtectionLevel: ProtectionDangerous, Label: "read calendar events and details", Description: "This app can read all calendar events stored on your phone."},
+	"READ_CALL_LOG":                   {ProtectionLevel: ProtectionDangerous, Label: "read call log", Description: "This app can read your call history."},
+	"READ_CONTACTS":                   {ProtectionLevel: ProtectionDangerous, Label: "read your contacts", Description: "Allows the app to read data about your contacts stored on your phone."},
+	"READ_EXTERNAL_STORAGE":           {ProtectionLevel: ProtectionDangerous, Label: "read the contents of your shared storage", Description: "Allows the app to read the contents of your shared storage."},
+	"READ_MEDIA_AUDIO":                {ProtectionLevel: ProtectionDangerous, Label: "read audio files from shared storage", Description: "Allows the app to read audio files from your shared storage."},
+	"READ_MEDIA_IMAGES":               {ProtectionLevel: ProtectionDangerous, Label: "read image files from shared storage", Description: "Allows the app to read image files from your shared storage."},
+	"READ_MEDIA_VIDEO":                {ProtectionLevel: ProtectionDangerous, Label: "read video files from shared storage", Description: "Allows the app to read video files from your shared storage."},
+	"READ_MEDIA_VISUAL_USER_SELECTED": {ProtectionLevel: ProtectionDangerous, Label: "read user selected images and videos", Description: "Allows the app to read images and videos that you select."},
+	"READ_PHONE_NUMBERS":              {ProtectionLevel: ProtectionDangerous, Label: "read phone numbers", Description: "Allows the app to access the phone numbers of the device."},
+	"READ_PHONE_STATE":                {ProtectionLevel: ProtectionDangerous, Label: "read phone status and identity", Description: "Allows the app to access the phone features of the device, including the phone number and device IDs."},
+	"READ_SMS":                        {ProtectionLevel: ProtectionDangerous, Label: "read your text messages", Description: "This app can read all SMS messages stored on your phone."},
+	"RECEIVE_MMS":                     {ProtectionLevel: ProtectionDangerous, Label: "receive text messages (MMS)", Description: "Allows the app to receive and process MMS messages."},
+	"RECEIVE_SMS":                     {ProtectionLevel: ProtectionDangerous, Label: "receive text messages (SMS)", Description: "Allows the app to receive and process SMS messages."},
+	"RECEIVE_WAP_PUSH":                {ProtectionLevel: ProtectionDangerous, Label: "receive text messages (WAP)", Description: "Allows the app to receive and process WAP messages."},
+	"RECORD_AUDIO":                    {ProtectionLevel: ProtectionDangerous, Label: "record audio", Description: "This app can record audio using the microphone while the app is in use."},
+	"SEND_SMS":                        {ProtectionLevel: ProtectionDangerous, Label: "send and view SMS messages", Description: "Allows the app to send SMS messages. This may result in unexpected charges."},
+	"USE_SIP":                         {ProtectionLevel: ProtectionDangerous, Label: "make and receive SIP calls", Description: "Allows the app to make and receive SIP calls."},
+	"UWB_RANGING":                     {ProtectionLevel: ProtectionDangerous, Label: "determine relative position of nearby Ultra-Wideband devices", Description: "Allows the app to determine the relative position between nearby Ultra-Wideband devices."},
+	"WRITE_CALENDAR":                  {ProtectionLevel: ProtectionDangerous, Label: "add or modify calendar events", Description: "This app can add, remove, or change calendar events on your phone."},
+	"WRITE_CALL_LOG":                  {ProtectionLevel: ProtectionDangerous, Label: "write call log", Description: "Allows the app to modify your phone's call log."},
+	"WRITE_CONTACTS":                  {ProtectionLevel: ProtectionDangerous, Label: "modify your contacts", Description: "Allows the app to modify the data about your contacts stored on your phone."},
+	"WRITE_EXTERNAL_STORAGE":          {ProtectionLevel: ProtectionDangerous, Label: "modify or delete the contents of your shared storage", Description: "Allows the app to write the contents of your shared storage."},
+
+	// signature and app-op permissions users grant in settings
+	"BIND_ACCESSIBILITY_SERVICE":         {ProtectionLevel: ProtectionSignature, Label: "bind to an accessibility service", Description: "Allows the app to observe and control the screen as an accessibility service."},
+	"BIND_DEVICE_ADMIN":                  {ProtectionLevel: ProtectionSignature, Label: "interact with a device admin", Description: "Allows the app to act as a device administrator."},
+	"BIND_NOTIFICATION_LISTENER_SERVICE": {ProtectionLevel: ProtectionSignature, Label: "bind to a notification listener service", Description: "Allows the app to read all notifications."},
+	"BIND_VPN_SERVICE":                   {ProtectionLevel: ProtectionSignature, Label: "bind to a VPN service", Description: "Allows the app to provide a VPN that can route all network traffic."},
+	"INSTALL_PACKAGES":                   {ProtectionLevel: ProtectionSignature, Label: "directly install apps", Description: "Allows the app to install new or updated Android packages."},
+	"MANAGE_EXTERNAL_STORAGE":            {ProtectionLevel: ProtectionSignature, Label: "access all files", Description: "Allows the app to read, modify and delete all files in shared storage."},
+	"PACKAGE_USAGE_STATS":                {ProtectionLevel: ProtectionSignature, Label: "update component usage statistics", Description: "Allows the app to read usage statistics of other apps."},
+	"READ_LOGS":                          {ProtectionLevel: ProtectionSignature, Label: "read sensitive log data", Description: "Allows the app to read from the system's various log files."},
+	"REQUEST_INSTALL_PACKAGES":           {ProtectionLevel: ProtectionSignature, Label: "request install packages", Description: "Allows the app to request installation of packages."},
+	"SCHEDULE_EXACT_ALARM":               {ProtectionLevel: ProtectionSignature, Label: "schedule exact alarms", Description: "Allows the app to schedule work at a precise time."},
+	"SYSTEM_ALERT_WINDOW":                {ProtectionLevel: ProtectionSignature, Label: "display over other apps", Description: "This app can appear on top of other apps or other parts of the screen."},
+	"WRITE_SECURE_SETTINGS":              {ProtectionLevel: ProtectionSignature, Label: "modify secure system settings", Description: "Allows the app to modify the system's secure settings data."},
+	"WRITE_SETTINGS":                     {ProtectionLevel: ProtectionSignature, Label: "modify system settings", Description: "Allows the app to modify the system's settings data."},
+
+	// normal permissions, granted at install
+	"ACCESS_LOCATION_EXTRA_COMMANDS":       {ProtectionLevel: ProtectionNormal, Label: "access extra location provider commands", Description: "Allows the app to access extra location provider commands."},
+	"ACCESS_NETWORK_STATE":                 {ProtectionLevel: ProtectionNormal, Label: "view network connections", Description: "Allows the app to view information about network connections."},
+	"ACCESS_NOTIFICATION_POLICY":           {ProtectionLevel: ProtectionNormal, Label: "access Do Not Disturb", Description: "Allows the app to read and write Do Not Disturb configuration."},
+	"ACCESS_WIFI_STATE":                    {ProtectionLevel: ProtectionNormal, Label: "view Wi-Fi connections", Description: "Allows the app to view information about Wi-Fi networking."},
+	"BLUETOOTH":                            {ProtectionLevel: ProtectionNormal, Label: "pair with Bluetooth devices", Description: "Allows the app to view the configuration of Bluetooth and connect to paired devices."},
+	"BLUETOOTH_ADMIN":                      {ProtectionLevel: ProtectionNormal, Label: "access Bluetooth settings", Description: "Allows the app to configure Bluetooth and discover and pair remote devices."},
+	"CHANGE_NETWORK_STATE":                 {ProtectionLevel: ProtectionNormal, Label: "change network connectivity", Description: "Allows the app to change the state of network connectivity."},
+	"CHANGE_WIFI_MULTICAST_STATE":          {ProtectionLevel: ProtectionNormal, Label: "allow Wi-Fi Multicast reception", Description: "Allows the app to receive packets sent to all devices on a Wi-Fi network."},
+	"CHANGE_WIFI_STATE":                    {ProtectionLevel: ProtectionNormal, Label: "connect and disconnect from Wi-Fi", Description: "Allows the app to connect to and disconnect from Wi-Fi access points."},
+	"DISABLE_KEYGUARD":                     {ProtectionLevel: ProtectionNormal, Label: "disable your screen lock", Description: "Allows the app to disable the keylock and any associated password security."},
+	"EXPAND_STATUS_BAR":                    {ProtectionLevel: ProtectionNormal, Label: "expand/collapse status bar", Description: "Allows the app to expand or collapse the status bar."},
+	"FOREGROUND_SERVICE":                   {ProtectionLevel: ProtectionNormal, Label: "run foreground service", Description: "Allows the app to make use of foreground services."},
+	"GET_PACKAGE_SIZE":                     {ProtectionLevel: ProtectionNormal, Label: "measure app storage space", Description: "Allows the app to retrieve its code, data, and cache sizes."},
+	"HIGH_SAMPLING_RATE_SENSORS":           {ProtectionLevel: ProtectionNormal, Label: "access sensor data at a high sampling rate", Description: "Allows the app to sample sensor data at a rate greater than 200 Hz."},
+	"INTERNET":                             {ProtectionLevel: ProtectionNormal, Label: "have full network access", Description: "Allows the app to create network sockets and use custom network protocols."},
+	"KILL_BACKGROUND_PROCESSES":            {ProtectionLevel: ProtectionNormal, Label: "close other apps", Description: "Allows the app to end background processes of other apps."},
+	"MODIFY_AUDIO_SETTINGS":                {ProtectionLevel: ProtectionNormal, Label: "change your audio settings", Description: "Allows the app to modify global audio settings such as volume."},
+	"NFC":                                  {ProtectionLevel: ProtectionNormal, Label: "control Near Field Communication", Description: "Allows the app to communicate with NFC tags, cards, and readers."},
+	"QUERY_ALL_PACKAGES":                   {ProtectionLevel: ProtectionNormal, Label: "query all packages", Description: "Allows the app to see all installed packages."},
+	"READ_SYNC_SETTINGS":                   {ProtectionLevel: ProtectionNormal, Label: "read sync settings", Description: "Allows the app to read the sync settings for an account."},
+	"RECEIVE_BOOT_COMPLETED":               {ProtectionLevel: ProtectionNormal, Label: "run at startup", Description: "Allows the app to start itself as soon as the system has finished booting."},
+	"REORDER_TASKS":                        {ProtectionLevel: ProtectionNormal, Label: "reorder running apps", Description: "Allows the app to move tasks to the foreground and background."},
+	"REQUEST_DELETE_PACKAGES":              {ProtectionLevel: ProtectionNormal, Label: "request deleting packages", Description: "Allows the app to request deletion of packages."},
+	"REQUEST_IGNORE_BATTERY_OPTIMIZATIONS": {ProtectionLevel: ProtectionNormal, Label: "ask to ignore battery optimizations", Description: "Allows the app to ask for permission to ignore battery optimizations."},
+	"SET_ALARM":                            {ProtectionLevel: ProtectionNormal, Label: "set an alarm", Description: "Allows the app to set an alarm in an installed alarm clock app."},
+	"SET_WALLPAPER":                        {ProtectionLevel: ProtectionNormal, Label: "set wallpaper", Description: "Allows the app to set the system wallpaper."},
+	"TRANSMIT_IR":                          {ProtectionLevel: ProtectionNormal, Label: "transmit infrared", Description: "Allows the app to use the phone's infrared transmitter."},
+	"USE_BIOMETRIC":                        {ProtectionLevel: ProtectionNormal, Label: "use biometric hardware", Description: "Allows the app to use biometric hardware for authentication."},
+	"USE_EXACT_ALARM":                      {ProtectionLevel: ProtectionNormal, Label: "schedule alarms or event reminders", Description: "Allows the app to schedule alarms and reminders at a precise time."},
+	"USE_FINGERPRINT":                      {ProtectionLevel: ProtectionNormal, Label: "use fingerprint hardware", Description: "Allows the app to use fingerprint hardware for authentication."},
+	"USE_FULL_SCREEN_INTENT":               {ProtectionLevel: ProtectionNormal, Label: "display notifications as full screen activities", Description: "Allows the app to display notifications as full screen activities on a locked device."},
+	"VIBRATE":                              {ProtectionLevel: ProtectionNormal, Label: "control vibration", Description: "Allows the app to control the vibrator."},
+	"WAKE_LOCK":                            {ProtectionLevel: ProtectionNormal, Label: "prevent phone from sleeping", Description: "Allows the app to prevent the phone from going to sleep."},
+	"WRITE_SYNC_SETTINGS":                  {ProtectionLevel: ProtectionNormal, Label: "toggle sync on and off", Description: "Allows the app to modify the sync settings for an account."},
+
+	"com.android.launcher.permission.INSTALL_SHORTCUT": {ProtectionLevel: ProtectionNormal, Label: "install shortcuts", Description: "Allows the app to add Home screen shortcuts."},
+	"com.android.vending.BILLING":                      {ProtectionLevel: ProtectionNormal, Label: "in-app purchases", Description: "Allows the app to offer Google Play in-app purchases."},
+	"com.android.vending.CHECK_LICENSE":                {ProtectionLevel: ProtectionNormal, Label: "check license", Description: "Allows the app to check its Google Play license."},
+	"com.google.android.c2dm.permission.RECEIVE":       {ProtectionLevel: ProtectionNormal, Label: "receive push messages", Description: "Allows the app to receive Firebase Cloud Messaging messages."},
+	"com.google.android.gms.permission.AD_ID":          {ProtectionLevel: ProtectionNormal, Label: "use the advertising ID", Description: "Allows the app to read the Google advertising ID."},
+}
+
+// LookupPermission classifies the permission name. Permissions missing from
+// the bundled table, such as ones apps declare themselves, are reported as
+// ProtectionUnknown.
+func LookupPermission(name string) Permission {
+	var p Permission
+	var ok bool
+	if short := strings.TrimPrefix(name, "android.permission."); short != name {
+		p, ok = knownPermissions[short]
+		if !ok && strings.HasPrefix(short, "FOREGROUND_SERVICE_") {
+			p, ok = Permission{ProtectionLevel: ProtectionNormal, Label: "run foreground service", Description: "Allows the app to run a typed foreground service."}, true
+		}
+	} else if strings.Contains(name, ".") {
+		p, ok = knownPermissions[name]
+	}
+	if !ok {
+		p.ProtectionLevel = ProtectionUnknown
+	}
+	p.Name = name
+	return p
+}
+
+type androidPermission struct {
+	Name            string `xml:"name,attr"`
+	ProtectionLevel string `xml:"protectionLevel,attr"`
+	Label           string `xml:"label,attr"`
+	Description     string `xml:"description,attr"`
+}
+
+// protectionLevel returns the base level of a protectionLevel attribute:
+// "signature|privileged" in plain and proto XML, or its integer value in
+// binary XML. The flags are dropped and signatureOrSystem is signature.
+func protectionLevel(v string) string {
+	base := strings.SplitN(v, "|", 2)[0]
+	if n, err := strconv.ParseUint(v, 0, 32); err == nil {
+		base = map[uint64]string{0: ProtectionNormal, 1: ProtectionDangerous, 2: ProtectionSignature, 3: ProtectionSignature}[n&0xf]
+	}
+	switch base {
+	case "", ProtectionNormal:
+		return ProtectionNormal
+	case ProtectionDangerous:
+		return ProtectionDangerous
+	case ProtectionSignature, "signatureOrSystem":
+		return ProtectionSignature
+	}
+	return ProtectionUnknown
+}
+
+// definedPermissions returns the <permission> elements of m. Label and
+// Description are resource references until resolved by the caller.
+func definedPermissions(m *androidManifest) []Permission {
+	var perms []Permission
+	for _, p := range m.Permissions {
+		perms = append(perms, Permission{
+			Name:            p.Name,
+			ProtectionLevel: protectionLevel(p.ProtectionLevel),
+			Label:           p.Label,
+			Description:     p.Description,
+		})
+	}
+	return perms
+}
+
+var protectionOrder = map[string]int{
+	ProtectionDangerous: 0,
+	ProtectionSignature: 1,
+	ProtectionUnknown:   2,
+	ProtectionNormal:    3,
+}
+
+// ClassifyPermissions looks up names and sorts them with the most sensitive
+// protection levels first.
+func ClassifyPermissions(names []string) []Permission {
+	perms := make([]Permission, 0, len(names))
+	for _, name := range names {
+		perms = append(perms, LookupPermission(name))
+	}
+	sort.SliceStable(perms, func(i, j int) bool {
+		return protectionOrder[perms[i].ProtectionLevel] < protectionOrder[perms[j].ProtectionLevel]
+	})
+	return perms
+}
@@ -0,0 +1,52 @@
+package appfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLookupPermission(t *testing.T) {
+	for name, want := range map[string]string{
+		"android.permission.CAMERA":                      ProtectionDangerous,
+		"android.permission.INTERNET":                    ProtectionNormal,
+		"android.permission.SYSTEM_ALERT_WINDOW":         ProtectionSignature,
+		"android.permission.FOREGROUND_SERVICE_LOCATION": ProtectionNormal,
+		"com.google.android.c2dm.permission.RECEIVE":     ProtectionNormal,
+		"com.example.permission.C2D_MESSAGE":             ProtectionUnknown,
+		"CAMERA":                                         ProtectionUnknown,
+	} {
+		p := LookupPermission(name)
+		if p.ProtectionLevel != want || p.Name != name {
+			t.Errorf("got %v %v want %v %v", p.Name, p.ProtectionLevel, name, want)
+		}
+	}
+}
+
+func TestClassifyPermissions(t *testing.T) {
+	perms := ClassifyPermissions([]string{"android.permission.INTERNET", "android.permission.READ_SMS", "android.permission.WRITE_SETTINGS"})
+	var levels []string
+	for _, p := range perms {
+		levels = append(levels, p.ProtectionLevel)
+	}
+	if len(levels) != 3 || levels[0] != ProtectionDangerous || levels[1] != ProtectionSignature || levels[2] != ProtectionNormal {
+		t.Errorf("got %v want %v", levels, []string{ProtectionDangerous, ProtectionSignature, ProtectionNormal})
+	}
+}
+
+func TestDefinedPermissions(t *testing.T) {
+	m := new(androidManifest)
+	m.Permissions = []androidPermission{
+		{Name: "com.example.permission.C2D_MESSAGE", ProtectionLevel: "signature"},
+		{Name: "com.example.READ", ProtectionLevel: "0x12", Label: "Read"},
+		{Name: "com.example.USE"},
+		{Name: "com.example.PLUGIN", ProtectionLevel: "dangerous|instant"},
+	}
+	var got []string
+	for _, p := range definedPermissions(m) {
+		got = append(got, p.ProtectionLevel)
+	}
+	want := []string{ProtectionSignature, ProtectionSignature, ProtectionNormal, ProtectionDangerous}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
@@ -0,0 +1,13 @@
+package appfile
+
+import "github.com/follyxing/go-plist"
+
+// EntitlementsPlist returns the profile's entitlements as an XML plist,
+// the format codesign takes with --entitlements.
+func (p *ProvisioningProfile) EntitlementsPlist() ([]byte, error) {
+	entitlements := p.Entitlements
+	if entitlements == nil {
+		entitlements = map[string]interface{}{}
+	}
+	return plist.MarshalIndent(entitlements, plist.XMLFormat, "\t")
+}
@@ -0,0 +1,105 @@
+package appfile
+
+import (
+	"archive/zip"
+	"sort"
+	"strings"
+	"time"
+)
+
+const profileFileName = "embedded.mobileprovision"
+
+// nestedProfileBundle returns the bundle, relative to the .app directory,
+// whose profile name is, e.g. PlugIns/Widget.appex or Watch/App.app. The
+// app's own profile has no bundle.
+func nestedProfileBundle(name string) (string, bool) {
+	parts := strings.Split(name, "/")
+	if len(parts) < 4 || parts[0] != "Payload" || !strings.HasSuffix(parts[1], ".app") || parts[len(parts)-1] != profileFileName {
+		return "", false
+	}
+	return strings.Join(parts[2:len(parts)-1], "/"), true
+}
+
+// parseIpaNestedProfiles keeps profiles that decoded but did not verify
+// and returns the first such error.
+func parseIpaNestedProfiles(files []*zip.File) ([]BundleProfile, error) {
+	var profiles []BundleProfile
+	var err error
+	for _, f := range files {
+		bundle, ok := nestedProfileBundle(f.Name)
+		if !ok {
+			continue
+		}
+		p, perr := parseIpaProfile(f)
+		if p == nil {
+			return nil, perr
+		}
+		if err == nil {
+			err = perr
+		}
+		profiles = append(profiles, BundleProfile{Path: bundle, ProvisioningProfile: p})
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Path < profiles[j].Path })
+	return profiles, err
+}
+
+// ExpiredAt reports whether p is expired at t, e.g. the time an audit is
+// backdated to. The Expired field is relative to the parse time instead.
+func (p *ProvisioningProfile) ExpiredAt(t time.Time) bool {
+	return !p.ExpirationDate.IsZero() && p.ExpirationDate.Before(t)
+}
+
+// checkProfiles warns about extension profiles of another team or signing
+// type than the app's, either of which makes the install fail.
+func checkProfiles(info *AppInfo) {
+	app := info.Ios.Profile
+	if app == nil {
+		return
+	}
+	for _, b := range info.Ios.BundleProfiles {
+		bundle := b.Path + ": "
+		if b.TeamId != app.TeamId {
+			info.warn("%sprofile team %s differs from the app's %s", bundle, b.TeamId, app.TeamId)
+		}
+		if b.SigningType != app.SigningType {
+			info.warn("%sprofile signing type %s differs from the app's %s", bundle, b.SigningType, app.SigningType)
+		}
+	}
+}
+
+// checkExpiry sets the Expired flags of certificates and profiles and
+// warns about profiles that are expired at now.
+func checkExpiry(info *AppInfo, now time.Time) {
+	if info.Android != nil && info.Android.Signer != nil {
+		c := info.Android.Signer
+		c.Expired = !c.NotAfter.IsZero() && c.NotAfter.Before(now)
+	}
+	if info.Ios == nil || info.Ios.Profile == nil {
+		return
+	}
+	expired := func(bundle string, p *ProvisioningProfile) {
+		p.Expired = p.ExpiredAt(now)
+		if p.Expired {
+			info.warn("%sprofile %q expired on %s", bundle, p.Name, p.ExpirationDate.Format("2006-01-02"))
+		}
+		for i := range p.Certificates {
+			c := &p.Certificates[i]
+			c.Expired = !c.NotAfter.IsZero() && c.NotAfter.Before(now)
+		}
+	}
+	expired("", info.Ios.Profile)
+	for _, b := range info.Ios.BundleProfiles {
+		expired(b.Path+": ", b.ProvisioningProfile)
+	}
+}
+
+// applyExpiry checks the expiry of a result at o.now() and applies the
+// mode again for the warnings that adds. It runs after results are
+// cached, so a cache hit is judged at the time it is served.
+func (o *options) applyExpiry(info *AppInfo, err error) (*AppInfo, error) {
+	if info == nil {
+		return nil, err
+	}
+	checkExpiry(info, o.now())
+	return o.applyMode(info, err)
+}
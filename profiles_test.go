@@ -0,0 +1,88 @@
+package appfile
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNestedProfileBundle(t *testing.T) {
+	for name, want := range map[string]string{
+		"Payload/App.app/PlugIns/Widget.appex/embedded.mobileprovision":              "PlugIns/Widget.appex",
+		"Payload/App.app/Watch/Watch.app/PlugIns/Ext.appex/embedded.mobileprovision": "Watch/Watch.app/PlugIns/Ext.appex",
+		"Payload/App.app/embedded.mobileprovision":                                   "",
+		"Payload/App.app/PlugIns/Widget.appex/Info.plist":                            "",
+	} {
+		got, _ := nestedProfileBundle(name)
+		if got != want {
+			t.Errorf("%v: got %q want %q", name, got, want)
+		}
+	}
+}
+
+func TestCheckProfiles(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	info := newAppInfo(PlatformIOS)
+	info.Ios.Profile = &ProvisioningProfile{Name: "App", TeamId: "TEAM1", SigningType: "ad-hoc", ExpirationDate: now.AddDate(0, 1, 0)}
+	info.Ios.BundleProfiles = []BundleProfile{
+		{"PlugIns/Ok.appex", &ProvisioningProfile{Name: "Ok", TeamId: "TEAM1", SigningType: "ad-hoc", ExpirationDate: now.AddDate(1, 0, 0)}},
+		{"PlugIns/Old.appex", &ProvisioningProfile{Name: "Old", TeamId: "TEAM2", SigningType: "development", ExpirationDate: now.AddDate(0, 0, -1)}},
+	}
+	checkProfiles(info)
+	checkExpiry(info, now)
+	want := []string{
+		"PlugIns/Old.appex: profile team TEAM2 differs from the app's TEAM1",
+		"PlugIns/Old.appex: profile signing type development differs from the app's ad-hoc",
+		`PlugIns/Old.appex: profile "Old" expired on 2024-05-31`,
+	}
+	if !reflect.DeepEqual(info.Warnings, want) {
+		t.Errorf("got %q want %q", info.Warnings, want)
+	}
+}
+
+func TestProfileExpired(t *testing.T) {
+	release := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	o := newOptions([]Option{WithClock(func() time.Time { return release })})
+
+	info := newAppInfo(PlatformIOS)
+	info.Ios.Profile = &ProvisioningProfile{
+		Name:           "App",
+		ExpirationDate: release.AddDate(0, 0, 1),
+		Certificates:   []Certificate{{Subject: "Old", NotAfter: release.AddDate(0, 0, -1)}, {Subject: "New"}},
+	}
+	checkExpiry(info, o.now())
+	p := info.Ios.Profile
+	if p.Expired || !p.Certificates[0].Expired || p.Certificates[1].Expired {
+		t.Errorf("got %v %v %v want %v %v %v", p.Expired, p.Certificates[0].Expired, p.Certificates[1].Expired, false, true, false)
+	}
+	if !p.ExpiredAt(release.AddDate(0, 0, 2)) {
+		t.Errorf("got %v want %v", false, true)
+	}
+}
+
+func TestProfileExpiredCached(t *testing.T) {
+	key, err := HashFile("testdata/helloworld.apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	release := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	cached := newAppInfo(PlatformIOS)
+	cached.Ios.Profile = &ProvisioningProfile{Name: "App", ExpirationDate: release.AddDate(0, 0, 1)}
+	c := NewLRUCache(1)
+	c.Set(context.Background(), key, cached)
+
+	for _, tt := range []struct {
+		now     time.Time
+		expired bool
+	}{
+		{release, false},
+		{release.AddDate(0, 0, 2), true},
+		{release, false},
+	} {
+		info, _ := NewAppParser("testdata/helloworld.apk", WithCache(c), WithClock(func() time.Time { return tt.now }))
+		if info.Ios.Profile.Expired != tt.expired || len(info.Warnings) > 0 != tt.expired {
+			t.Errorf("%v: got %v %q want %v", tt.now, info.Ios.Profile.Expired, info.Warnings, tt.expired)
+		}
+	}
+}
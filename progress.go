@@ -0,0 +1,85 @@
+package appfile
+
+import (
+	"io"
+	"sync"
+)
+
+// progressStep caps the bytes read between progress reports.
+const progressStep = 1 << 20
+
+// WithProgress calls fn as an artifact is hashed and read, so UIs can show
+// progress on large files. done counts the bytes of the artifact read in
+// the running stage, such as StageHash or StageManifest, out of its total
+// size; each stage is counted on its own and may stop short of total, as
+// most stages only read some entries. fn is called every percent or MiB,
+// whichever is less, and with StageParse and done equal to total when the
+// parse ends.
+func WithProgress(fn func(stage string, done, total int64)) Option {
+	return func(o *options) {
+		o.progress = fn
+	}
+}
+
+// progressCounter reports the bytes read of an artifact of size total to
+// the progress function of o, counting each stage on its own.
+type progressCounter struct {
+	o     *options
+	total int64
+
+	mu       sync.Mutex
+	done     map[string]int64
+	reported map[string]int64
+}
+
+func newProgressCounter(o *options, total int64) *progressCounter {
+	return &progressCounter{o: o, total: total, done: make(map[string]int64), reported: make(map[string]int64)}
+}
+
+func (c *progressCounter) add(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stage := c.o.stage
+	done := c.done[stage] + int64(n)
+	if done > c.total {
+		done = c.total
+	}
+	c.done[stage] = done
+	step := c.total / 100
+	if step > progressStep {
+		step = progressStep
+	}
+	if done-c.reported[stage] >= step || done == c.total && c.reported[stage] != c.total {
+		c.reported[stage] = done
+		c.o.progress(stage, done, c.total)
+	}
+}
+
+// finish reports the end of the parse.
+func (c *progressCounter) finish() {
+	c.o.progress(StageParse, c.total, c.total)
+}
+
+// progressReaderAt counts the bytes read from r.
+type progressReaderAt struct {
+	r io.ReaderAt
+	c *progressCounter
+}
+
+func (p progressReaderAt) ReadAt(b []byte, off int64) (int, error) {
+	n, err := p.r.ReadAt(b, off)
+	p.c.add(n)
+	return n, err
+}
+
+// progressWriter counts the bytes written to w.
+type progressWriter struct {
+	w io.Writer
+	c *progressCounter
+}
+
+func (p progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.c.add(n)
+	return n, err
+}
@@ -0,0 +1,29 @@
+package appfile
+
+import (
+	"os"
+	"testing"
+)
+
+func TestWithProgress(t *testing.T) {
+	stat, err := os.Stat("testdata/helloworld.apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stages := make(map[string]int64)
+	var last string
+	progress := WithProgress(func(stage string, done, total int64) {
+		if total != stat.Size() || done > total || done < stages[stage] {
+			t.Errorf("got %v %v/%v want monotonic progress out of %v", stage, done, total, stat.Size())
+		}
+		stages[stage], last = done, stage
+	})
+	NewAppParser("testdata/helloworld.apk", progress, WithCache(NewLRUCache(1)))
+
+	if stages[StageHash] != stat.Size() {
+		t.Errorf("got %v want %v hashed", stages[StageHash], stat.Size())
+	}
+	if last != StageParse || stages[StageParse] != stat.Size() {
+		t.Errorf("got %v %v want %v %v last", last, stages[StageParse], StageParse, stat.Size())
+	}
+}
@@ -0,0 +1,53 @@
+// Package promhook exports appfile parse stages as Prometheus metrics.
+package promhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Hook counts stage results and records stage durations. Error rates per
+// stage can be derived from the result label of appfile_stage_total.
+type Hook struct {
+	stages   *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// New creates a Hook and registers its collectors with reg.
+func New(reg prometheus.Registerer) (*Hook, error) {
+	h := &Hook{
+		stages: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "appfile",
+			Name:      "stage_total",
+			Help:      "Number of completed parse stages by result.",
+		}, []string{"stage", "result"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "appfile",
+			Name:      "stage_duration_seconds",
+			Help:      "Duration of parse stages.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"stage"}),
+	}
+	if err := reg.Register(h.stages); err != nil {
+		return nil, err
+	}
+	if err := reg.Register(h.duration); err != nil {
+		reg.Unregister(h.stages)
+		return nil, err
+	}
+	return h, nil
+}
+
+func (h *Hook) StartStage(ctx context.Context, stage string) (context.Context, func(err error)) {
+	start := time.Now()
+	return ctx, func(err error) {
+		result := "ok"
+		if err != nil {
+			result = "error"
+		}
+		h.stages.WithLabelValues(stage, result).Inc()
+		h.duration.WithLabelValues(stage).Observe(time.Since(start).Seconds())
+	}
+}
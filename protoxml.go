@@ -0,0 +1,148 @@
+package appfile
+
+import (
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"io"
+)
+
+var errProtobuf = errors.New("malformed protobuf")
+
+// protoXML turns an aapt2 XmlNode protobuf, the manifest format of Android
+// App Bundles, into the token stream encoding/xml decodes.
+type protoXML struct {
+	tokens []xml.Token
+}
+
+func newProtoXMLDecoder(b []byte) (*xml.Decoder, error) {
+	r := new(protoXML)
+	if err := r.node(b); err != nil {
+		return nil, err
+	}
+	return xml.NewTokenDecoder(r), nil
+}
+
+func (r *protoXML) Token() (xml.Token, error) {
+	if len(r.tokens) == 0 {
+		return nil, io.EOF
+	}
+	t := r.tokens[0]
+	r.tokens = r.tokens[1:]
+	return t, nil
+}
+
+// XmlNode: element = 1, text = 2
+func (r *protoXML) node(b []byte) error {
+	return protoFields(b, func(num int, v []byte) error {
+		switch num {
+		case 1:
+			return r.element(v)
+		case 2:
+			r.tokens = append(r.tokens, xml.CharData(v))
+		}
+		return nil
+	})
+}
+
+// XmlElement: namespace_declaration = 1, namespace_uri = 2, name = 3,
+// attribute = 4, child = 5
+// XmlNamespace: prefix = 1, uri = 2
+// XmlAttribute: namespace_uri = 1, name = 2, value = 3
+func (r *protoXML) element(b []byte) error {
+	var start xml.StartElement
+	var children [][]byte
+	err := protoFields(b, func(num int, v []byte) error {
+		switch num {
+		case 1:
+			attr := xml.Attr{Name: xml.Name{Space: "xmlns"}}
+			err := protoFields(v, func(num int, v []byte) error {
+				switch num {
+				case 1:
+					attr.Name.Local = string(v)
+				case 2:
+					attr.Value = string(v)
+				}
+				return nil
+			})
+			start.Attr = append(start.Attr, attr)
+			return err
+		case 2:
+			start.Name.Space = string(v)
+		case 3:
+			start.Name.Local = string(v)
+		case 4:
+			var attr xml.Attr
+			err := protoFields(v, func(num int, v []byte) error {
+				switch num {
+				case 1:
+					attr.Name.Space = string(v)
+				case 2:
+					attr.Name.Local = string(v)
+				case 3:
+					attr.Value = string(v)
+				}
+				return nil
+			})
+			start.Attr = append(start.Attr, attr)
+			return err
+		case 5:
+			children = append(children, v)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r.tokens = append(r.tokens, start)
+	for _, child := range children {
+		if err := r.node(child); err != nil {
+			return err
+		}
+	}
+	r.tokens = append(r.tokens, start.End())
+	return nil
+}
+
+// protoFields calls fn for every length-delimited field of the message b.
+// Other wire types are skipped.
+func protoFields(b []byte, fn func(num int, v []byte) error) error {
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return errProtobuf
+		}
+		b = b[n:]
+
+		switch key & 7 {
+		case 0:
+			if _, n = binary.Uvarint(b); n <= 0 {
+				return errProtobuf
+			}
+			b = b[n:]
+		case 1, 5:
+			size := 8
+			if key&7 == 5 {
+				size = 4
+			}
+			if len(b) < size {
+				return errProtobuf
+			}
+			b = b[size:]
+		case 2:
+			l, n := binary.Uvarint(b)
+			if n <= 0 || l > uint64(len(b)-n) {
+				return errProtobuf
+			}
+			v := b[n : n+int(l)]
+			b = b[n+int(l):]
+			if err := fn(int(key>>3), v); err != nil {
+				return err
+			}
+		default:
+			return errProtobuf
+		}
+	}
+	return nil
+}
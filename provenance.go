@@ -0,0 +1,48 @@
+package appfile
+
+import "context"
+
+// ProvenanceVerifier checks where an artifact comes from, e.g. against a
+// detached SLSA or sigstore attestation, or an attestation file inside the
+// archive. file is the path of the artifact on disk and may be opened with
+// OpenParser.
+//
+// The returned Provenance is recorded on AppInfo.Provenance. An error
+// marks it unverified with the error as its reason; it is also reported to
+// hooks as the "provenance_verify" stage but does not fail the parse.
+type ProvenanceVerifier interface {
+	VerifyProvenance(ctx context.Context, file string, info *AppInfo) (Provenance, error)
+}
+
+// ProvenanceFunc adapts a function to a ProvenanceVerifier.
+type ProvenanceFunc func(ctx context.Context, file string, info *AppInfo) (Provenance, error)
+
+func (f ProvenanceFunc) VerifyProvenance(ctx context.Context, file string, info *AppInfo) (Provenance, error) {
+	return f(ctx, file, info)
+}
+
+// WithProvenanceVerifier registers v to be run on every parse, including
+// cache hits, so a revoked attestation is not reported as verified.
+func WithProvenanceVerifier(v ProvenanceVerifier) Option {
+	return func(o *options) {
+		o.verifiers = append(o.verifiers, v)
+	}
+}
+
+func (o *options) verifyProvenance(file string, info *AppInfo) {
+	if len(o.verifiers) == 0 {
+		return
+	}
+	provenance := make([]Provenance, 0, len(o.verifiers))
+	for _, v := range o.verifiers {
+		end := o.startStage(StageProvenance)
+		p, err := v.VerifyProvenance(o.ctx, file, info)
+		end(err)
+		if err != nil {
+			p.Verified = false
+			p.Error = err.Error()
+		}
+		provenance = append(provenance, p)
+	}
+	info.Provenance = provenance
+}
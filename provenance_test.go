@@ -0,0 +1,40 @@
+package appfile
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWithProvenanceVerifier(t *testing.T) {
+	var files []string
+	ok := ProvenanceFunc(func(ctx context.Context, file string, info *AppInfo) (Provenance, error) {
+		files = append(files, file)
+		return Provenance{Verifier: "slsa", Verified: true, Builder: "https://ci.example.com"}, nil
+	})
+	errRevoked := errors.New("attestation revoked")
+	revoked := ProvenanceFunc(func(ctx context.Context, file string, info *AppInfo) (Provenance, error) {
+		return Provenance{Verifier: "sigstore", Verified: true}, errRevoked
+	})
+	h := &errorHook{errs: make(map[string]error)}
+
+	info, err := NewAppParser("testdata/helloworld.apk",
+		WithProvenanceVerifier(ok), WithProvenanceVerifier(revoked), WithHook(h))
+	if info == nil {
+		t.Fatal(err)
+	}
+	want := []Provenance{
+		{Verifier: "slsa", Verified: true, Builder: "https://ci.example.com"},
+		{Verifier: "sigstore", Error: errRevoked.Error()},
+	}
+	if !reflect.DeepEqual(info.Provenance, want) {
+		t.Errorf("got %+v want %+v", info.Provenance, want)
+	}
+	if want := []string{"testdata/helloworld.apk"}; !reflect.DeepEqual(files, want) {
+		t.Errorf("got %v want %v", files, want)
+	}
+	if h.errs[StageProvenance] != errRevoked {
+		t.Errorf("got %v want %v", h.errs[StageProvenance], errRevoked)
+	}
+}
@@ -0,0 +1,53 @@
+package appfile
+
+import "strings"
+
+type androidProvider struct {
+	Name                string `xml:"name,attr"`
+	Authorities         string `xml:"authorities,attr"`
+	Exported            string `xml:"exported,attr"`
+	Enabled             string `xml:"enabled,attr"`
+	Permission          string `xml:"permission,attr"`
+	ReadPermission      string `xml:"readPermission,attr"`
+	WritePermission     string `xml:"writePermission,attr"`
+	GrantUriPermissions string `xml:"grantUriPermissions,attr"`
+
+	MetaData []androidMetaData `xml:"meta-data"`
+}
+
+// parseProviders lists the content providers of the manifest and warns
+// about exported ones other apps can read or write without a permission.
+// Providers are exported by default for apps targeting API level 16 and
+// lower.
+func parseProviders(manifest *androidManifest, info *AppInfo) {
+	target := atoi(info.Android.TargetSdkVersion)
+	if target == 0 {
+		target = atoi(info.Android.MinSdkVersion)
+	}
+	for _, p := range manifest.Application.Providers {
+		c := ContentProvider{
+			Name:                manifest.className(p.Name),
+			Exported:            p.Exported == "true" || p.Exported == "" && target < 17,
+			Permission:          p.Permission,
+			ReadPermission:      p.ReadPermission,
+			WritePermission:     p.WritePermission,
+			GrantUriPermissions: p.GrantUriPermissions == "true",
+		}
+		for _, a := range strings.Split(p.Authorities, ";") {
+			if a = strings.TrimSpace(a); a != "" {
+				c.Authorities = append(c.Authorities, a)
+			}
+		}
+		info.Android.Providers = append(info.Android.Providers, c)
+
+		if !c.Exported || p.Enabled == "false" {
+			continue
+		}
+		if c.Permission == "" && c.ReadPermission == "" {
+			info.warn("provider %s is exported and readable by any app", c.Name)
+		}
+		if c.Permission == "" && c.WritePermission == "" {
+			info.warn("provider %s is exported and writable by any app", c.Name)
+		}
+	}
+}
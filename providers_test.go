@@ -0,0 +1,32 @@
+package appfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseProviders(t *testing.T) {
+	manifest := new(androidManifest)
+	manifest.Package = "com.example"
+	manifest.Application.Providers = []androidProvider{
+		{Name: "androidx.core.content.FileProvider", Authorities: "com.example.files", Exported: "false", GrantUriPermissions: "true"},
+		{Name: ".Data", Authorities: "com.example.data;com.example.legacy", Exported: "true", ReadPermission: "com.example.READ"},
+		{Name: ".Old", Authorities: "com.example.old", Enabled: "false"},
+	}
+	info := newAppInfo(PlatformAndroid)
+	info.Android.TargetSdkVersion = "16"
+
+	parseProviders(manifest, info)
+	want := []ContentProvider{
+		{Name: "androidx.core.content.FileProvider", Authorities: []string{"com.example.files"}, GrantUriPermissions: true},
+		{Name: "com.example.Data", Authorities: []string{"com.example.data", "com.example.legacy"}, Exported: true, ReadPermission: "com.example.READ"},
+		{Name: "com.example.Old", Authorities: []string{"com.example.old"}, Exported: true},
+	}
+	if !reflect.DeepEqual(info.Android.Providers, want) {
+		t.Errorf("got %+v want %+v", info.Android.Providers, want)
+	}
+	// .Data is writable by any app; .Old is disabled.
+	if len(info.Warnings) != 1 {
+		t.Errorf("got %v want one warning", info.Warnings)
+	}
+}
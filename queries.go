@@ -0,0 +1,66 @@
+package appfile
+
+import "strings"
+
+const permissionQueryAllPackages = "android.permission.QUERY_ALL_PACKAGES"
+
+type androidQueries struct {
+	Packages []androidName `xml:"package"`
+	Intents  []struct {
+		Actions    []androidName `xml:"action"`
+		Categories []androidName `xml:"category"`
+		Data       []struct {
+			Scheme   string `xml:"scheme,attr"`
+			Host     string `xml:"host,attr"`
+			MimeType string `xml:"mimeType,attr"`
+		} `xml:"data"`
+	} `xml:"intent"`
+	Providers []struct {
+		Authorities string `xml:"authorities,attr"`
+	} `xml:"provider"`
+}
+
+// newPackageVisibility returns the apps m declares visibility of in its
+// <queries> elements, or nil when it declares none. Each <intent> has a
+// single action.
+func newPackageVisibility(m *androidManifest, permissions []string) *PackageVisibility {
+	v := new(PackageVisibility)
+	for _, p := range permissions {
+		v.QueryAllPackages = v.QueryAllPackages || p == permissionQueryAllPackages
+	}
+	for _, q := range m.Queries {
+		for _, p := range q.Packages {
+			v.Packages = appendUnique(v.Packages, p.Name)
+		}
+		for _, i := range q.Intents {
+			var intent QueryIntent
+			if len(i.Actions) > 0 {
+				intent.Action = i.Actions[0].Name
+			}
+			for _, c := range i.Categories {
+				intent.Categories = append(intent.Categories, c.Name)
+			}
+			for _, d := range i.Data {
+				if d.Scheme != "" {
+					intent.Schemes = appendUnique(intent.Schemes, d.Scheme)
+				}
+				if d.Host != "" {
+					intent.Hosts = appendUnique(intent.Hosts, d.Host)
+				}
+				if d.MimeType != "" {
+					intent.MimeTypes = appendUnique(intent.MimeTypes, d.MimeType)
+				}
+			}
+			v.Intents = append(v.Intents, intent)
+		}
+		for _, p := range q.Providers {
+			for _, a := range strings.Split(p.Authorities, ";") {
+				v.Providers = appendUnique(v.Providers, a)
+			}
+		}
+	}
+	if !v.QueryAllPackages && v.Packages == nil && v.Intents == nil && v.Providers == nil {
+		return nil
+	}
+	return v
+}
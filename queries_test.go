@@ -0,0 +1,47 @@
+package appfile
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+)
+
+func TestNewPackageVisibility(t *testing.T) {
+	m := new(androidManifest)
+	err := xml.Unmarshal([]byte(`<manifest xmlns:android="http://schemas.android.com/apk/res/android">
+  <queries>
+    <package android:name="com.whatsapp"/>
+    <intent>
+      <action android:name="android.intent.action.VIEW"/>
+      <category android:name="android.intent.category.BROWSABLE"/>
+      <data android:scheme="https" android:host="maps.example.com"/>
+    </intent>
+    <provider android:authorities="com.example.sync;com.example.auth"/>
+  </queries>
+  <queries><package android:name="com.whatsapp"/></queries>
+</manifest>`), m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &PackageVisibility{
+		Packages: []string{"com.whatsapp"},
+		Intents: []QueryIntent{{
+			Action:     "android.intent.action.VIEW",
+			Categories: []string{"android.intent.category.BROWSABLE"},
+			Schemes:    []string{"https"},
+			Hosts:      []string{"maps.example.com"},
+		}},
+		Providers: []string{"com.example.sync", "com.example.auth"},
+	}
+	if got := newPackageVisibility(m, nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+
+	if got := newPackageVisibility(new(androidManifest), nil); got != nil {
+		t.Errorf("got %+v want nil", got)
+	}
+	if got := newPackageVisibility(new(androidManifest), []string{permissionQueryAllPackages}); got == nil || !got.QueryAllPackages {
+		t.Errorf("got %+v want QueryAllPackages", got)
+	}
+}
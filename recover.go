@@ -0,0 +1,62 @@
+package appfile
+
+import (
+	"fmt"
+	"io"
+	"runtime/debug"
+)
+
+// PanicError is the error of a stage during which a decoder panicked,
+// e.g. on a malformed artifact. The parse of that artifact fails, but the
+// process, such as an ingestion service, keeps running.
+type PanicError struct {
+	Value interface{} // the value passed to panic
+	Stack []byte      // the stack of the panicking goroutine
+}
+
+func (e *PanicError) Error() string { return fmt.Sprintf("panic: %v", e.Value) }
+
+// WithPanics lets panics of the decoders propagate instead of turning
+// them into a *PanicError, e.g. to debug them with the full crash output.
+func WithPanics() Option {
+	return func(o *options) {
+		o.panics = true
+	}
+}
+
+// recoverPanic turns a panic of a decoder into a *PanicError in *err. The
+// Parser queries and other entry points that decode outside a parse defer
+// it, unlike parses it does not honour WithPanics.
+func recoverPanic(err *error) {
+	if v := recover(); v != nil {
+		*err = &PanicError{Value: v, Stack: debug.Stack()}
+	}
+}
+
+// parseFormat parses an artifact with f. A panic fails the innermost
+// running stage with a *PanicError and ends the stages it left open.
+func (o *options) parseFormat(f Format, name string, r io.ReaderAt, size int64) (info *AppInfo, err error) {
+	if !o.panics {
+		depth := len(o.running)
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			perr := &PanicError{Value: v, Stack: debug.Stack()}
+			var errs stageErrors
+			errs.add(o.stage, perr)
+			for i := len(o.running) - 1; i >= depth; i-- {
+				o.running[i](perr)
+			}
+			o.running = o.running[:depth]
+			info, err = nil, errs.err()
+		}()
+	}
+	switch f := f.(type) {
+	case optionsFormat:
+		return f.parse(name, r, size, o)
+	default:
+		return f.Parse(name, r, size)
+	}
+}
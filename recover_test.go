@@ -0,0 +1,91 @@
+package appfile
+
+import (
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/follyxing/appfile-info/fixture"
+)
+
+// badStringRef returns the binary XML buf with the name of its first
+// element pointing past the string pool, which the decoder indexes
+// without checking.
+func badStringRef(t *testing.T, buf []byte) []byte {
+	const resXMLStartElement = 0x0102
+	for pos := 8; pos+8 <= len(buf); pos += int(binary.LittleEndian.Uint32(buf[pos+4:])) {
+		if binary.LittleEndian.Uint16(buf[pos:]) == resXMLStartElement {
+			header := int(binary.LittleEndian.Uint16(buf[pos+2:]))
+			binary.LittleEndian.PutUint32(buf[pos+header+4:], 0xfffff0)
+			return buf
+		}
+	}
+	t.Fatal("no element in binary xml")
+	return nil
+}
+
+func TestParsePanic(t *testing.T) {
+	apk := &fixture.APK{Package: "com.example.panic", VersionCode: 1, VersionName: "1.0"}
+	name := filepath.Join(t.TempDir(), "panic.apk")
+	writeZip(t, name, map[string][]byte{"AndroidManifest.xml": badStringRef(t, fixture.AXML(apk.Manifest()))})
+
+	h := new(recordingHook)
+	info, err := NewAppParser(name, WithHook(h))
+	if info != nil {
+		t.Errorf("got %v want nil", info)
+	}
+	var perr *PanicError
+	if !errors.As(err, &perr) || len(perr.Stack) == 0 {
+		t.Fatalf("got %v want PanicError", err)
+	}
+	var serr *StageError
+	if !errors.As(err, &serr) || serr.Stage != StageManifest {
+		t.Errorf("got %v want stage %v", err, StageManifest)
+	}
+	var starts, ends int
+	for _, e := range h.events {
+		if strings.HasPrefix(e, "start ") {
+			starts++
+		} else {
+			ends++
+		}
+	}
+	if starts != ends {
+		t.Errorf("got %v want every stage ended", h.events)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("got no panic with WithPanics")
+		}
+	}()
+	NewAppParser(name, WithPanics())
+}
+
+func TestParserPanic(t *testing.T) {
+	apk := &fixture.APK{Package: "com.example.panic", VersionCode: 1, VersionName: "1.0"}
+	name := filepath.Join(t.TempDir(), "panic.apk")
+	writeZip(t, name, map[string][]byte{"AndroidManifest.xml": badStringRef(t, fixture.AXML(apk.Manifest()))})
+
+	var perr *PanicError
+	if err := WriteManifestXML(name, ioutil.Discard); !errors.As(err, &perr) {
+		t.Errorf("got %v want PanicError", err)
+	}
+	p, err := OpenParser(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+	if err := p.WriteManifestXML(ioutil.Discard); !errors.As(err, &perr) {
+		t.Errorf("got %v want PanicError", err)
+	}
+	if _, err := p.Manifest(); !errors.As(err, &perr) {
+		t.Errorf("got %v want PanicError", err)
+	}
+	if err := p.ExtractIcon(ioutil.Discard, ""); !errors.As(err, &perr) {
+		t.Errorf("got %v want PanicError", err)
+	}
+}
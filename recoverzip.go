@@ -0,0 +1,149 @@
+package appfile
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+const zipDescriptorSig = 0x08074b50
+
+// WithZipRecovery reads archives whose central directory is damaged or
+// missing, as left by interrupted uploads and downloads, by scanning them
+// for local file headers. Whatever entries are complete are parsed, and the
+// corruption and every entry lost are reported as warnings. It implies
+// WithTolerantZip.
+func WithZipRecovery() Option {
+	return func(o *options) {
+		o.tolerantZip = true
+		o.recoverZip = true
+	}
+}
+
+// recoverZip rebuilds the archive r from its local file headers. Entries
+// with a data descriptor are measured by inflating them; stored ones cannot
+// be and are skipped. The scan stops at the first entry running past the
+// end of r.
+func recoverZip(r io.ReaderAt, size int64) (*zip.Reader, []string, error) {
+	var entries []centralEntry
+	var warnings []string
+	for off, ok := findZipSignature(r, 0, size, zipLocalHeaderSig); ok; off, ok = findZipSignature(r, off, size, zipLocalHeaderSig) {
+		var local [30]byte
+		if _, err := r.ReadAt(local[:], off); err != nil {
+			warnings = append(warnings, fmt.Sprintf("zip local header at offset %d is truncated", off))
+			break
+		}
+		nameLen, extraLen := int64(le.Uint16(local[26:])), int64(le.Uint16(local[28:]))
+		buf := make([]byte, nameLen+extraLen)
+		if _, err := r.ReadAt(buf, off+30); err != nil {
+			warnings = append(warnings, fmt.Sprintf("zip local header at offset %d is truncated", off))
+			break
+		}
+		e := centralEntry{
+			name:             string(buf[:nameLen]),
+			method:           le.Uint16(local[8:]),
+			crc32:            le.Uint32(local[14:]),
+			compressedSize:   uint64(le.Uint32(local[18:])),
+			uncompressedSize: uint64(le.Uint32(local[22:])),
+			offset:           uint64(off),
+			dataOffset:       uint64(off + 30 + nameLen + extraLen),
+		}
+		e.readZip64(buf[nameLen:])
+		off = int64(e.dataOffset)
+
+		if le.Uint16(local[6:])&0x8 != 0 {
+			if e.method != zip.Deflate {
+				warnings = append(warnings, fmt.Sprintf("zip entry %s is stored with a data descriptor; it is skipped", e.name))
+				continue
+			}
+			if err := e.inflateSize(r, size); err != nil {
+				warnings = append(warnings, fmt.Sprintf("zip entry %s is truncated; it is skipped", e.name))
+				break
+			}
+			off += int64(e.compressedSize) + e.descriptorSize(r)
+		} else {
+			off += int64(e.compressedSize)
+		}
+		if off > size {
+			warnings = append(warnings, fmt.Sprintf("zip entry %s is truncated; it is skipped", e.name))
+			break
+		}
+		if e.method != zip.Store && e.method != zip.Deflate {
+			warnings = append(warnings, fmt.Sprintf("zip entry %s has compression method %d; it is skipped", e.name, e.method))
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if len(entries) == 0 {
+		return nil, nil, errZipDirectory
+	}
+
+	reader, fixes, err := rebuildZip(r, size, entries)
+	warnings = append([]string{fmt.Sprintf("central directory is damaged; %d entries recovered from local headers", len(entries))}, warnings...)
+	return reader, append(warnings, fixes...), err
+}
+
+// inflateSize sets the sizes and checksum of a deflated entry whose local
+// header defers them to a data descriptor.
+func (e *centralEntry) inflateSize(r io.ReaderAt, size int64) error {
+	src := &countingReader{r: bufio.NewReader(io.NewSectionReader(r, int64(e.dataOffset), size-int64(e.dataOffset)))}
+	h := crc32.NewIEEE()
+	n, err := io.Copy(h, flate.NewReader(src))
+	if err != nil {
+		return err
+	}
+	e.compressedSize, e.uncompressedSize, e.crc32 = uint64(src.n), uint64(n), h.Sum32()
+	return nil
+}
+
+// descriptorSize returns the length of the data descriptor following the
+// data of e, whose signature is optional.
+func (e *centralEntry) descriptorSize(r io.ReaderAt) int64 {
+	var sig [4]byte
+	if _, err := r.ReadAt(sig[:], int64(e.dataOffset+e.compressedSize)); err == nil && le.Uint32(sig[:]) == zipDescriptorSig {
+		return 16
+	}
+	return 12
+}
+
+// findZipSignature returns the offset of the first sig at or after off.
+func findZipSignature(r io.ReaderAt, off, size int64, sig uint32) (int64, bool) {
+	var want [4]byte
+	le.PutUint32(want[:], sig)
+	buf := make([]byte, 64*1024)
+	for ; off+4 <= size; off += int64(len(buf)) - 3 {
+		n, _ := r.ReadAt(buf, off)
+		if n < 4 {
+			break
+		}
+		if i := bytes.Index(buf[:n], want[:]); i >= 0 {
+			return off + int64(i), true
+		}
+	}
+	return 0, false
+}
+
+// countingReader counts the bytes flate consumes, which reads through
+// ReadByte and so never past the end of the stream.
+type countingReader struct {
+	r *bufio.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *countingReader) ReadByte() (byte, error) {
+	b, err := c.r.ReadByte()
+	if err == nil {
+		c.n++
+	}
+	return b, err
+}
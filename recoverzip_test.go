@@ -0,0 +1,84 @@
+package appfile
+
+import (
+	"archive/zip"
+	"bytes"
+	"hash/crc32"
+	"testing"
+)
+
+// truncatedZip returns an archive cut off in the middle of its last entry,
+// losing the central directory. Its entries are deflated with data
+// descriptors, stored with sizes in the local header and stored with a data
+// descriptor.
+func truncatedZip(t *testing.T) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, _ := w.Create("AndroidManifest.xml")
+	f.Write(bytes.Repeat([]byte("manifest"), 100))
+	stored := []byte("dex\n035\x00")
+	f, err := w.CreateRaw(&zip.FileHeader{
+		Name:               "classes.dex",
+		Method:             zip.Store,
+		CRC32:              crc32.ChecksumIEEE(stored),
+		CompressedSize64:   uint64(len(stored)),
+		UncompressedSize64: uint64(len(stored)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write(stored)
+	f, _ = w.CreateHeader(&zip.FileHeader{Name: "assets/stored.txt", Method: zip.Store})
+	f.Write([]byte("stored"))
+	f, _ = w.Create("res/raw/big.bin")
+	f.Write(bytes.Repeat([]byte{1, 2, 3, 4, 5, 6, 7, 8}, 4096))
+	w.Close()
+	b := buf.Bytes()
+	last := bytes.LastIndex(b, []byte("PK\x03\x04"))
+	return b[:last+60]
+}
+
+func TestRecoverZip(t *testing.T) {
+	b := truncatedZip(t)
+	if _, _, err := repairZip(bytes.NewReader(b), int64(len(b))); err == nil {
+		t.Fatalf("got no error want the central directory to be missing")
+	}
+
+	reader, warnings, err := recoverZip(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	if len(reader.File) != 2 || len(warnings) != 3 {
+		t.Fatalf("got %d files %v want 2 files and 3 warnings", len(reader.File), warnings)
+	}
+	for name, want := range map[string]string{
+		"AndroidManifest.xml": string(bytes.Repeat([]byte("manifest"), 100)),
+		"classes.dex":         "dex\n035\x00",
+	} {
+		buf, err := readZipFile(findZipFile(reader.File, name))
+		if err != nil || string(buf) != want {
+			t.Errorf("got %q %v want %q", buf, err, want)
+		}
+	}
+
+	if _, _, err := recoverZip(bytes.NewReader([]byte("not a zip")), 9); err == nil {
+		t.Errorf("got no error want one")
+	}
+}
+
+func TestZipFormatRecovery(t *testing.T) {
+	b := truncatedZip(t)
+	f := zipFormat(func(_ string, reader *zip.Reader, _ *options) (*AppInfo, error) {
+		return newAppInfo(PlatformAndroid), nil
+	})
+	if _, err := f.parse("app.apk", bytes.NewReader(b), int64(len(b)), newOptions([]Option{WithTolerantZip()})); err == nil {
+		t.Errorf("got no error want one without WithZipRecovery")
+	}
+	info, err := f.parse("app.apk", bytes.NewReader(b), int64(len(b)), newOptions([]Option{WithZipRecovery()}))
+	if err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	if len(info.Warnings) != 4 {
+		t.Errorf("got %v want 4 warnings", info.Warnings)
+	}
+}
@@ -0,0 +1,134 @@
+// Package rediscache is an example appfile.Cache backed by Redis.
+package rediscache
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/png"
+	"time"
+
+	"github.com/follyxing/appfile-info"
+	"github.com/redis/go-redis/v9"
+)
+
+const defaultPrefix = "appfile:"
+
+// Cache stores parse results as JSON. The fields AppInfo leaves out of its
+// JSON are stored beside it, images encoded as PNG, so a hit returns what
+// the parse did.
+type Cache struct {
+	client redis.UniversalClient
+	prefix string
+	ttl    time.Duration
+}
+
+type record struct {
+	Info *appfile.AppInfo
+	Icon []byte `json:",omitempty"`
+
+	RoundIcon        []byte `json:",omitempty"`
+	Banner           []byte `json:",omitempty"`
+	NotificationIcon []byte `json:",omitempty"`
+
+	// ProfileData and BundleProfileData are the Data of Ios.Profile and
+	// of each of Ios.BundleProfiles.
+	ProfileData       []byte   `json:",omitempty"`
+	BundleProfileData [][]byte `json:",omitempty"`
+}
+
+// New returns a Cache that expires entries after ttl; a zero ttl keeps them
+// until Redis evicts them.
+func New(client redis.UniversalClient, ttl time.Duration) *Cache {
+	return &Cache{client: client, prefix: defaultPrefix, ttl: ttl}
+}
+
+func (c *Cache) Get(ctx context.Context, key string) (*appfile.AppInfo, bool) {
+	data, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if err != nil {
+		return nil, false
+	}
+
+	var r record
+	if err := json.Unmarshal(data, &r); err != nil || r.Info == nil {
+		return nil, false
+	}
+	info := r.Info
+	ok := decodePNG(r.Icon, &info.Icon)
+	if a := info.Android; a != nil {
+		ok = ok && decodePNG(r.RoundIcon, &a.RoundIcon) &&
+			decodePNG(r.Banner, &a.Banner) &&
+			decodePNG(r.NotificationIcon, &a.NotificationIcon)
+	}
+	if !ok {
+		return nil, false
+	}
+	if ios := info.Ios; ios != nil {
+		if ios.Profile != nil {
+			ios.Profile.Data = r.ProfileData
+		}
+		for i, b := range ios.BundleProfiles {
+			if i < len(r.BundleProfileData) && b.ProvisioningProfile != nil {
+				b.Data = r.BundleProfileData[i]
+			}
+		}
+	}
+	return info, true
+}
+
+func (c *Cache) Set(ctx context.Context, key string, info *appfile.AppInfo) {
+	r := record{Info: info}
+	ok := encodePNG(info.Icon, &r.Icon)
+	if a := info.Android; a != nil {
+		ok = ok && encodePNG(a.RoundIcon, &r.RoundIcon) &&
+			encodePNG(a.Banner, &r.Banner) &&
+			encodePNG(a.NotificationIcon, &r.NotificationIcon)
+	}
+	if !ok {
+		return
+	}
+	if ios := info.Ios; ios != nil {
+		if ios.Profile != nil {
+			r.ProfileData = ios.Profile.Data
+		}
+		for _, b := range ios.BundleProfiles {
+			var data []byte
+			if b.ProvisioningProfile != nil {
+				data = b.Data
+			}
+			r.BundleProfileData = append(r.BundleProfileData, data)
+		}
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return
+	}
+	c.client.Set(ctx, c.prefix+key, data, c.ttl)
+}
+
+// encodePNG stores img in b, leaving b empty for no image.
+func encodePNG(img image.Image, b *[]byte) bool {
+	if img == nil {
+		return true
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return false
+	}
+	*b = buf.Bytes()
+	return true
+}
+
+func decodePNG(b []byte, img *image.Image) bool {
+	if len(b) == 0 {
+		return true
+	}
+	decoded, err := png.Decode(bytes.NewReader(b))
+	if err != nil {
+		return false
+	}
+	*img = decoded
+	return true
+}
@@ -0,0 +1,192 @@
+package appfile
+
+import (
+	"archive/zip"
+	"image"
+	"path"
+
+	"github.com/shogo82148/androidbinary"
+)
+
+// apkResources resolves resource references of an APK against its
+// resources.arsc, which is only read when first needed. References to
+// android: resources go to framework when it is set.
+type apkResources struct {
+	files     []*zip.File
+	table     *arscTable
+	loaded    bool
+	framework *apkResources
+}
+
+func newApkResources(files []*zip.File) *apkResources {
+	return &apkResources{files: files}
+}
+
+// owner returns the resources ref belongs to: the framework for android:
+// references, if set, and r otherwise.
+func (r *apkResources) owner(ref string) *apkResources {
+	if r.framework == nil || !androidbinary.IsResID(ref) {
+		return r
+	}
+	if id, err := androidbinary.ParseResID(ref); err == nil && uint32(id)>>24 == frameworkPackageID {
+		return r.framework
+	}
+	return r
+}
+
+// lookup returns the table and the id of ref, such as "@0x7F030000".
+func (r *apkResources) lookup(ref string) (*arscTable, uint32, bool) {
+	if !androidbinary.IsResID(ref) {
+		return nil, 0, false
+	}
+	id, err := androidbinary.ParseResID(ref)
+	if err != nil {
+		return nil, 0, false
+	}
+	r = r.owner(ref)
+	if r.load() == nil {
+		return nil, 0, false
+	}
+	return r.table, uint32(id), true
+}
+
+func (r *apkResources) load() *arscTable {
+	if !r.loaded {
+		r.loaded = true
+		r.table = openResourceTable(r.files)
+	}
+	return r.table
+}
+
+// stringNamed returns the value of the string resource name in the default
+// configuration.
+func (r *apkResources) stringNamed(name string) string {
+	t := r.load()
+	if t == nil {
+		return ""
+	}
+	id, ok := t.ids["string/"+name]
+	if !ok {
+		return ""
+	}
+	if e := t.entry(id); e != nil {
+		return t.string(e.value)
+	}
+	return ""
+}
+
+// stringValue resolves ref if it references a string resource and returns
+// it unchanged otherwise.
+func (r *apkResources) stringValue(ref string) string {
+	t, id, ok := r.lookup(ref)
+	if !ok {
+		return ref
+	}
+	if e := t.entry(id); e != nil {
+		return t.resolveString(e)
+	}
+	return ref
+}
+
+// refName returns ref as @type/name if it names a resource of the app, and
+// ref itself otherwise.
+func (r *apkResources) refName(ref string) string {
+	t, id, ok := r.lookup(ref)
+	if !ok {
+		return ref
+	}
+	if name := t.name(id); name != "" {
+		return "@" + name
+	}
+	return ref
+}
+
+// file returns the file resource ref, such as res/xml/backup_rules.xml.
+func (r *apkResources) file(ref string) *zip.File {
+	r = r.owner(ref)
+	t, id, ok := r.lookup(ref)
+	if !ok {
+		return nil
+	}
+	e := t.entry(id)
+	if e == nil {
+		return nil
+	}
+	return findZipFile(r.files, t.string(e.value))
+}
+
+// image decodes the drawable ref at the highest density. Vector and
+// adaptive drawables are not rendered and yield nil.
+func (r *apkResources) image(ref string) image.Image {
+	f := r.imageFile(ref)
+	if f == nil {
+		return nil
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil
+	}
+	defer rc.Close()
+	img, _, err := image.Decode(rc)
+	if err != nil {
+		return nil
+	}
+	return img
+}
+
+// imageFile returns the PNG or JPEG file of the drawable ref at the highest
+// density.
+func (r *apkResources) imageFile(ref string) *zip.File {
+	r = r.owner(ref)
+	t, id, ok := r.lookup(ref)
+	if !ok {
+		return nil
+	}
+
+	var best *zip.File
+	var bestDensity uint16
+	for _, res := range t.resources[id] {
+		name := t.string(res.value)
+		switch path.Ext(name) {
+		case ".png", ".jpg", ".jpeg":
+		default:
+			continue
+		}
+		d := res.config.density()
+		if d == 0xffff || d == 0xfffe {
+			d = 0 // anydpi, nodpi
+		}
+		if f := findZipFile(r.files, name); f != nil && (best == nil || d > bestDensity) {
+			best, bestDensity = f, d
+		}
+	}
+	return best
+}
+
+func openResourceTable(files []*zip.File) *arscTable {
+	f := findZipFile(files, "resources.arsc")
+	if f == nil {
+		return nil
+	}
+	buf, err := readZipFile(f)
+	if err != nil {
+		return nil
+	}
+	table, err := parseARSC(buf)
+	if err != nil {
+		return nil
+	}
+	return table
+}
+
+func findZipFile(files []*zip.File, name string) *zip.File {
+	if name == "" {
+		return nil
+	}
+	for _, f := range files {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
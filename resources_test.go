@@ -0,0 +1,20 @@
+package appfile
+
+import "testing"
+
+func TestApkResourcesMissingTable(t *testing.T) {
+	reader, err := getAppZipReader("testdata/helloworld.ipa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := newApkResources(reader.File)
+	if img := res.image("@0x7F030000"); img != nil {
+		t.Errorf("got %v want nil", img)
+	}
+	if img := res.image(""); img != nil {
+		t.Errorf("got %v want nil", img)
+	}
+	if !res.loaded || res.table != nil {
+		t.Errorf("got loaded=%v table=%v want loaded and no table", res.loaded, res.table)
+	}
+}
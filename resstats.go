@@ -0,0 +1,87 @@
+package appfile
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var densityNames = map[uint16]string{
+	120:    "ldpi",
+	160:    "mdpi",
+	213:    "tvdpi",
+	240:    "hdpi",
+	320:    "xhdpi",
+	480:    "xxhdpi",
+	640:    "xxxhdpi",
+	0xfffe: "anydpi",
+	0xffff: "nodpi",
+}
+
+// resourceStats summarizes the resource table of an APK, or returns nil if
+// it has none.
+func resourceStats(res *apkResources) *ResourceStats {
+	t := res.load()
+	if t == nil {
+		return nil
+	}
+
+	s := &ResourceStats{Types: make(map[string]int)}
+	if f := findZipFile(res.files, "resources.arsc"); f != nil {
+		s.TableSize = int64(f.UncompressedSize64)
+	}
+	for name := range t.ids {
+		s.Types[strings.SplitN(name, "/", 2)[0]]++
+	}
+
+	files := make(map[string]bool)
+	configs := make(map[string]bool)
+	densities := make(map[uint16]bool)
+	locales := make(map[string]bool)
+	for id, rs := range t.resources {
+		if id>>24 == 0x01 {
+			continue
+		}
+		for _, r := range rs {
+			configs[string(r.config[4:])] = true
+			if d := r.config.density(); d != 0 {
+				densities[d] = true
+			}
+			if l := r.config.locale(); l != "" {
+				locales[l] = true
+			}
+			if !r.bag {
+				if name := t.string(r.value); strings.HasPrefix(name, "res/") {
+					files[name] = true
+				}
+			}
+		}
+	}
+	s.Configurations = len(configs)
+
+	ds := make([]int, 0, len(densities))
+	for d := range densities {
+		ds = append(ds, int(d))
+	}
+	sort.Ints(ds)
+	for _, d := range ds {
+		name, ok := densityNames[uint16(d)]
+		if !ok {
+			name = strconv.Itoa(d) + "dpi"
+		}
+		s.Densities = append(s.Densities, name)
+	}
+	for l := range locales {
+		s.Locales = append(s.Locales, l)
+	}
+	sort.Strings(s.Locales)
+
+	s.Size = s.TableSize
+	for _, f := range res.files {
+		if files[f.Name] {
+			s.FileSize += int64(f.UncompressedSize64)
+		}
+	}
+	s.Size += s.FileSize
+	return s
+}
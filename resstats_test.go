@@ -0,0 +1,34 @@
+package appfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResourceStats(t *testing.T) {
+	reader, err := getAppZipReader("testdata/helloworld.apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := resourceStats(newApkResources(reader.File))
+	if s == nil {
+		t.Fatal("got no stats")
+	}
+	if s.Types["string"] != 34 || s.Types["anim"] != 10 || s.Types["mipmap"] != 1 {
+		t.Errorf("got %v", s.Types)
+	}
+	want := []string{"mdpi", "hdpi", "xhdpi", "xxhdpi", "xxxhdpi"}
+	if !reflect.DeepEqual(s.Densities, want) {
+		t.Errorf("got %v want %v", s.Densities, want)
+	}
+	if len(s.Locales) != 80 || s.Locales[0] != "af" {
+		t.Errorf("got %v", s.Locales)
+	}
+	if s.TableSize != 192780 || s.Size != s.TableSize+s.FileSize || s.FileSize == 0 {
+		t.Errorf("got %v %v %v", s.TableSize, s.FileSize, s.Size)
+	}
+
+	if s := resourceStats(newApkResources(nil)); s != nil {
+		t.Errorf("got %+v want nil", s)
+	}
+}
@@ -0,0 +1,106 @@
+package appfile
+
+import (
+	"strconv"
+	"strings"
+)
+
+type androidSupportsScreens struct {
+	SmallScreens            string `xml:"smallScreens,attr"`
+	NormalScreens           string `xml:"normalScreens,attr"`
+	LargeScreens            string `xml:"largeScreens,attr"`
+	XLargeScreens           string `xml:"xlargeScreens,attr"`
+	AnyDensity              string `xml:"anyDensity,attr"`
+	Resizeable              string `xml:"resizeable,attr"`
+	RequiresSmallestWidthDp string `xml:"requiresSmallestWidthDp,attr"`
+	LargestWidthLimitDp     string `xml:"largestWidthLimitDp,attr"`
+}
+
+type androidScreen struct {
+	ScreenSize    string `xml:"screenSize,attr"`
+	ScreenDensity string `xml:"screenDensity,attr"`
+}
+
+// Binary manifests store android:screenSize as a number.
+var screenSizeNames = map[string]string{
+	"200": "small",
+	"300": "normal",
+	"400": "large",
+	"500": "xlarge",
+}
+
+// newScreenSupport reads <supports-screens> and <compatible-screens>. Left
+// out screen sizes default to supported, as for apps targeting API level 9
+// and later.
+func newScreenSupport(m *androidManifest) *ScreenSupport {
+	ss := m.SupportsScreens
+	s := &ScreenSupport{
+		Small:                   ss.SmallScreens != "false",
+		Normal:                  ss.NormalScreens != "false",
+		Large:                   ss.LargeScreens != "false",
+		XLarge:                  ss.XLargeScreens != "false",
+		AnyDensity:              ss.AnyDensity != "false",
+		Resizeable:              ss.Resizeable != "false",
+		RequiresSmallestWidthDp: atoi(ss.RequiresSmallestWidthDp),
+		LargestWidthLimitDp:     atoi(ss.LargestWidthLimitDp),
+	}
+	for _, c := range m.CompatibleScreens {
+		size := c.ScreenSize
+		if name, ok := screenSizeNames[size]; ok {
+			size = name
+		}
+		s.CompatibleScreens = append(s.CompatibleScreens, CompatibleScreen{
+			Size:    size,
+			Density: densityName(c.ScreenDensity),
+		})
+	}
+	return s
+}
+
+// Tablets reports whether the app can be installed on large and xlarge
+// screens.
+func (s *ScreenSupport) Tablets() bool {
+	if len(s.CompatibleScreens) > 0 {
+		for _, c := range s.CompatibleScreens {
+			if c.Size == "large" || c.Size == "xlarge" {
+				return true
+			}
+		}
+		return false
+	}
+	return s.Large || s.XLarge
+}
+
+// densityName returns the name of a density in dpi, e.g. 480 is xxhdpi.
+// Names are kept as they are.
+func densityName(v string) string {
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return v
+	}
+	if name, ok := densityNames[uint16(n)]; ok {
+		return name
+	}
+	return v + "dpi"
+}
+
+// densitySplit returns the density of a configuration split such as
+// config.xxhdpi or feature.config.hdpi.
+func densitySplit(split string) string {
+	i := strings.LastIndex(split, "config.")
+	if i < 0 || i > 0 && split[i-1] != '.' {
+		return ""
+	}
+	name := split[i+len("config."):]
+	for _, d := range densityNames {
+		if d == name {
+			return name
+		}
+	}
+	return ""
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,44 @@
+package appfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewScreenSupport(t *testing.T) {
+	m := new(androidManifest)
+	s := newScreenSupport(m)
+	if !s.Small || !s.Normal || !s.Large || !s.XLarge || !s.AnyDensity || !s.Tablets() {
+		t.Errorf("got %+v want all screens", s)
+	}
+
+	m.SupportsScreens = androidSupportsScreens{LargeScreens: "false", XLargeScreens: "false", RequiresSmallestWidthDp: "320"}
+	if s := newScreenSupport(m); s.Large || s.Tablets() || s.RequiresSmallestWidthDp != 320 {
+		t.Errorf("got %+v want no tablets", s)
+	}
+
+	m.CompatibleScreens = []androidScreen{{"200", "480"}, {"normal", "xhdpi"}, {"300", "280"}}
+	s = newScreenSupport(m)
+	want := []CompatibleScreen{{"small", "xxhdpi"}, {"normal", "xhdpi"}, {"normal", "280dpi"}}
+	if !reflect.DeepEqual(s.CompatibleScreens, want) {
+		t.Errorf("got %v want %v", s.CompatibleScreens, want)
+	}
+	if s.Tablets() {
+		t.Errorf("got tablets want phones only")
+	}
+}
+
+func TestDensitySplit(t *testing.T) {
+	for split, want := range map[string]string{
+		"config.xxhdpi":        "xxhdpi",
+		"feature.config.tvdpi": "tvdpi",
+		"config.arm64_v8a":     "",
+		"config.en":            "",
+		"myconfig.hdpi":        "",
+		"":                     "",
+	} {
+		if got := densitySplit(split); got != want {
+			t.Errorf("%q: got %q want %q", split, got, want)
+		}
+	}
+}
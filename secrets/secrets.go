@@ -0,0 +1,107 @@
+// Package secrets scans the files of .apk and .ipa archives for embedded
+// credentials such as cloud access keys and private keys.
+package secrets
+
+import (
+	"archive/zip"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"sort"
+)
+
+// Rule is a kind of secret and the pattern matching it.
+type Rule struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// DefaultRules are the rules Scan uses.
+var DefaultRules = []Rule{
+	{"aws_access_key_id", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"google_api_key", regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}`)},
+	{"private_key", regexp.MustCompile(`-----BEGIN (?:RSA |EC |DSA |OPENSSH |ENCRYPTED )?PRIVATE KEY-----`)},
+}
+
+// Finding is one match of a rule. Match is redacted to its first four
+// characters so reports can be shared.
+type Finding struct {
+	Rule   string `json:"rule"`
+	File   string `json:"file"`
+	Offset int64  `json:"offset"`
+	Match  string `json:"match"`
+}
+
+// Scanner scans archives with a set of rules.
+type Scanner struct {
+	Rules []Rule
+	// MaxFileSize skips larger files, which are mostly media. Zero means
+	// no limit.
+	MaxFileSize int64
+}
+
+// Scan scans the archive name with DefaultRules and files up to 64 MiB.
+func Scan(name string) ([]Finding, error) {
+	s := &Scanner{Rules: DefaultRules, MaxFileSize: 64 << 20}
+	return s.Scan(name)
+}
+
+// Scan returns the findings in every file of the archive name, ordered by
+// file and offset.
+func (s *Scanner) Scan(name string) ([]Finding, error) {
+	r, err := zip.OpenReader(name)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var findings []Finding
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() || s.MaxFileSize > 0 && int64(f.UncompressedSize64) > s.MaxFileSize {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		buf, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, s.scanBytes(f.Name, buf)...)
+	}
+	return findings, nil
+}
+
+// ScanReader scans the contents of a single file.
+func (s *Scanner) ScanReader(name string, r io.Reader) ([]Finding, error) {
+	buf, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.scanBytes(name, buf), nil
+}
+
+func (s *Scanner) scanBytes(name string, buf []byte) []Finding {
+	var findings []Finding
+	for _, rule := range s.Rules {
+		for _, loc := range rule.Pattern.FindAllIndex(buf, -1) {
+			findings = append(findings, Finding{
+				Rule:   rule.Name,
+				File:   name,
+				Offset: int64(loc[0]),
+				Match:  redact(buf[loc[0]:loc[1]]),
+			})
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Offset < findings[j].Offset })
+	return findings
+}
+
+func redact(b []byte) string {
+	if len(b) <= 4 {
+		return string(b)
+	}
+	return string(b[:4]) + "..."
+}
@@ -0,0 +1,193 @@
+package appfile
+
+import (
+	"archive/zip"
+	"crypto/x509"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/fullsailor/pkcs7"
+)
+
+// IDs of well-known APK Signing Block pairs. Other IDs, such as those of
+// in-house provenance tools, are returned as they are.
+const (
+	SigningBlockV2            = 0x7109871a // APK Signature Scheme v2
+	SigningBlockV3            = 0xf05368c0 // APK Signature Scheme v3
+	SigningBlockV31           = 0x1b93ad61 // APK Signature Scheme v3.1
+	SigningBlockSourceStamp   = 0x6dff800d // source stamp v2
+	SigningBlockVerityPadding = 0x42726577 // aligns the block for verity
+	SigningBlockDependencies  = 0x504b4453 // Google Play dependency info
+	SigningBlockWalle         = 0x71777777 // Meituan Walle channel
+	SigningBlockVasDolly      = 0x881155ff // Tencent VasDolly channel
+)
+
+// ErrNoSigningBlock is returned for archives without an APK Signing Block,
+// such as unsigned or v1-only APKs.
+var ErrNoSigningBlock = errors.New("no APK Signing Block")
+
+var errSigningBlock = errors.New("malformed APK Signing Block")
+
+const signingBlockMagic = "APK Sig Block 42"
+
+// SigningBlockPair is an ID-value pair of the APK Signing Block.
+type SigningBlockPair struct {
+	ID    uint32
+	Value []byte
+}
+
+// ReadSigningBlock returns the ID-value pairs of the APK Signing Block of
+// the .apk name, in file order.
+func ReadSigningBlock(name string) (_ []SigningBlockPair, err error) {
+	defer recoverPanic(&err)
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return readSigningBlock(file, stat.Size())
+}
+
+// SigningBlock is ReadSigningBlock for the open archive.
+func (p *Parser) SigningBlock() (_ []SigningBlockPair, err error) {
+	defer recoverPanic(&err)
+	stat, err := p.file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return readSigningBlock(p.file, stat.Size())
+}
+
+// readSigningBlock reads the block that ends where the central directory
+// starts: its size, the pairs, the size again and the magic.
+func readSigningBlock(r io.ReaderAt, size int64) ([]SigningBlockPair, error) {
+	dirOffset, _, err := findCentralDirectory(r, size)
+	if err != nil {
+		return nil, err
+	}
+	if dirOffset < 32 {
+		return nil, ErrNoSigningBlock
+	}
+	var footer [24]byte
+	if _, err := r.ReadAt(footer[:], int64(dirOffset)-24); err != nil {
+		return nil, err
+	}
+	if string(footer[8:]) != signingBlockMagic {
+		return nil, ErrNoSigningBlock
+	}
+	blockSize := le.Uint64(footer[:])
+	if blockSize < 24 || blockSize > dirOffset-8 || blockSize > maxEntrySize {
+		return nil, errSigningBlock
+	}
+	block := make([]byte, blockSize+8)
+	if _, err := r.ReadAt(block, int64(dirOffset-blockSize-8)); err != nil {
+		return nil, err
+	}
+	if le.Uint64(block) != blockSize {
+		return nil, errSigningBlock
+	}
+
+	var pairs []SigningBlockPair
+	for b := block[8 : len(block)-24]; len(b) > 0; {
+		if len(b) < 12 {
+			return nil, errSigningBlock
+		}
+		n := le.Uint64(b)
+		if n < 4 || n > uint64(len(b)-8) {
+			return nil, errSigningBlock
+		}
+		pairs = append(pairs, SigningBlockPair{ID: le.Uint32(b[8:]), Value: b[12 : 8+n]})
+		b = b[8+n:]
+	}
+	return pairs, nil
+}
+
+// apkSigner returns the first signer certificate of the archive r: that of
+// the v3 or v2 scheme block, or else of the v1 JAR signature.
+func apkSigner(r io.ReaderAt, size int64, files []*zip.File) *Certificate {
+	if pairs, err := readSigningBlock(r, size); err == nil {
+		for _, id := range []uint32{SigningBlockV3, SigningBlockV2} {
+			for _, pair := range pairs {
+				if pair.ID != id {
+					continue
+				}
+				if c := schemeSigner(pair.Value); c != nil {
+					return c
+				}
+			}
+		}
+	}
+	for _, f := range files {
+		if !strings.HasPrefix(f.Name, "META-INF/") {
+			continue
+		}
+		switch strings.ToUpper(path.Ext(f.Name)) {
+		case ".RSA", ".DSA", ".EC":
+		default:
+			continue
+		}
+		b, err := readZipFile(f)
+		if err != nil {
+			continue
+		}
+		msg, err := pkcs7.Parse(b)
+		if err != nil {
+			continue
+		}
+		if c := msg.GetOnlySigner(); c != nil {
+			cert := newCertificate(c)
+			return &cert
+		}
+	}
+	return nil
+}
+
+// schemeSigner reads the first certificate of the first signer of a v2 or
+// v3 block value. Both nest length-prefixed sequences the same way: the
+// signers, the signed data, its digests and then its certificates.
+func schemeSigner(b []byte) *Certificate {
+	prefixed := func(b []byte) ([]byte, []byte, bool) {
+		if len(b) < 4 || uint64(le.Uint32(b)) > uint64(len(b)-4) {
+			return nil, nil, false
+		}
+		n := 4 + le.Uint32(b)
+		return b[4:n], b[n:], true
+	}
+	signers, _, ok := prefixed(b)
+	if !ok {
+		return nil
+	}
+	signer, _, ok := prefixed(signers)
+	if !ok {
+		return nil
+	}
+	signed, _, ok := prefixed(signer)
+	if !ok {
+		return nil
+	}
+	_, rest, ok := prefixed(signed)
+	if !ok {
+		return nil
+	}
+	certs, _, ok := prefixed(rest)
+	if !ok {
+		return nil
+	}
+	der, _, ok := prefixed(certs)
+	if !ok {
+		return nil
+	}
+	c, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil
+	}
+	cert := newCertificate(c)
+	return &cert
+}
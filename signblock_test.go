@@ -0,0 +1,109 @@
+package appfile
+
+import (
+	"encoding/binary"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/follyxing/appfile-info/fixture"
+)
+
+func TestReadSigningBlock(t *testing.T) {
+	data, err := (&fixture.APK{Package: "com.example.signed"}).Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsigned := writeFile(t, "unsigned.apk", data)
+	if _, err := ReadSigningBlock(unsigned); !errors.Is(err, ErrNoSigningBlock) {
+		t.Errorf("got %v want %v", err, ErrNoSigningBlock)
+	}
+
+	want := []SigningBlockPair{
+		{ID: SigningBlockWalle, Value: []byte(`{"channel":"store"}`)},
+		{ID: 0x6275696c, Value: []byte("ci-4711")},
+	}
+	var pairs []fixture.SigningBlockPair
+	for _, pair := range want {
+		pairs = append(pairs, fixture.SigningBlockPair{ID: pair.ID, Value: pair.Value})
+	}
+	data, err = fixture.AddSigningBlock(data, pairs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := writeFile(t, "signed.apk", data)
+	got, err := ReadSigningBlock(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	p, err := OpenParser(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+	if got, err := p.SigningBlock(); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v %v want %v", got, err, want)
+	}
+}
+
+func TestApkSigner(t *testing.T) {
+	data, err := (&fixture.APK{Package: "com.example.signed"}).Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefixed := func(parts ...[]byte) []byte {
+		var b []byte
+		for _, p := range parts {
+			b = binary.LittleEndian.AppendUint32(b, uint32(len(p)))
+			b = append(b, p...)
+		}
+		return b
+	}
+	der := newTestCertificate(t, "Android Release")
+	// signers > signer > signed data > digests, certificates
+	signed := prefixed(nil, prefixed(der), nil)
+	value := prefixed(prefixed(prefixed(signed, nil, nil)))
+	data, err = fixture.AddSigningBlock(data, []fixture.SigningBlockPair{{ID: SigningBlockV2, Value: value}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := writeFile(t, "signed.apk", data)
+
+	info, _ := NewAppParser(name)
+	if info == nil || info.Android == nil || info.Android.Signer == nil {
+		t.Fatalf("got %v want a signer", info)
+	}
+	signer := info.Android.Signer
+	if signer.Subject != "Android Release" || !signer.Expired {
+		t.Errorf("got %v want an expired Android Release certificate", signer)
+	}
+
+	// The signer of a wrapped APK and of an .apks base survives the
+	// outer archive, which has no signature of its own.
+	for _, wrapped := range []string{"artifact.zip", "app.apks"} {
+		entry := "build/app.apk"
+		if wrapped == "app.apks" {
+			entry = "splits/base-master.apk"
+		}
+		outer := filepath.Join(t.TempDir(), wrapped)
+		writeZip(t, outer, map[string][]byte{entry: data})
+		info, _ := NewAppParser(outer)
+		if info == nil || info.Android == nil || info.Android.Signer == nil || info.Android.Signer.Subject != "Android Release" {
+			t.Errorf("%s: got %v want the signer of %s", wrapped, info, entry)
+		}
+	}
+
+	unsigned, err := (&fixture.APK{Package: "com.example.unsigned"}).Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, _ = NewAppParser(writeFile(t, "unsigned.apk", unsigned))
+	if info == nil || info.Android == nil || info.Android.Signer != nil {
+		t.Errorf("got %v want no signer", info)
+	}
+}
@@ -0,0 +1,23 @@
+package appfile
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrSimulatorBuild is the profile stage error of unprovisioned IPAs built
+// for the Simulator, which are sometimes uploaded in place of device builds.
+var ErrSimulatorBuild = errors.New("built for the Simulator")
+
+// checkSimulator flags builds whose main executable runs on the Simulator
+// only and warns about them, as they parse but fail to install.
+func checkSimulator(info *AppInfo) {
+	for _, b := range info.Ios.Binaries {
+		if !strings.Contains(b.Path, "/") && b.Simulator {
+			info.Ios.Simulator = true
+		}
+	}
+	if info.Ios.Simulator {
+		info.warn("built for the Simulator, it does not install on devices")
+	}
+}
@@ -0,0 +1,81 @@
+package appfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/follyxing/appfile-info/fixture"
+)
+
+// thinMachO returns a 64-bit executable for cpu with an LC_BUILD_VERSION
+// for platform, or no load commands if platform is 0.
+func thinMachO(cpu, platform uint32) []byte {
+	var ncmds, sizeofcmds uint32
+	if platform != 0 {
+		ncmds, sizeofcmds = 1, 24
+	}
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, []uint32{0xfeedfacf, cpu, 0, 2, ncmds, sizeofcmds, 0, 0})
+	if platform != 0 {
+		binary.Write(&buf, binary.LittleEndian, []uint32{machoLoadBuildVersion, 24, platform, 0x100000, 0x110000, 0})
+	}
+	return buf.Bytes()
+}
+
+func TestMachOSimulator(t *testing.T) {
+	const arm64, amd64 = 0x0100000c, 0x01000007
+	for _, tt := range []struct {
+		name          string
+		cpu, platform uint32
+		want          bool
+	}{
+		{"arm64 device", arm64, 2, false},
+		{"arm64 simulator", arm64, 7, true},
+		{"x86_64", amd64, 0, true},
+		{"arm64 without build version", arm64, 0, false},
+	} {
+		b, err := parseMachO(thinMachO(tt.cpu, tt.platform))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if b.Simulator != tt.want {
+			t.Errorf("%s: got %v want %v", tt.name, b.Simulator, tt.want)
+		}
+	}
+
+	info := newAppInfo(PlatformIOS)
+	info.Ios.Binaries = []IosBinary{{Path: "Frameworks/Kit.framework/Kit", Simulator: true}, {Path: "App"}}
+	if checkSimulator(info); info.Ios.Simulator {
+		t.Errorf("got %v want %v", info.Ios.Simulator, false)
+	}
+	info.Ios.Binaries[1].Simulator = true
+	if checkSimulator(info); !info.Ios.Simulator || len(info.Warnings) != 1 {
+		t.Errorf("got %v %q want simulator warning", info.Ios.Simulator, info.Warnings)
+	}
+}
+
+func TestParseSimulatorIpa(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "sim.ipa")
+	writeZip(t, name, map[string][]byte{
+		"Payload/App.app/Info.plist": fixture.Plist(map[string]interface{}{
+			"CFBundleIdentifier": "com.example.app",
+			"DTPlatformName":     "iphonesimulator",
+		}),
+		"Payload/App.app/App": thinMachO(0x0100000c, 7),
+	})
+	info, err := NewAppParser(name)
+	if !errors.Is(err, ErrSimulatorBuild) {
+		t.Errorf("got %v want %v", err, ErrSimulatorBuild)
+	}
+	if info == nil || !info.Ios.Simulator {
+		t.Fatalf("got %v want a Simulator build", info)
+	}
+
+	info, err = NewAppParser(name, WithMode(ModeLenient))
+	if err != nil || info == nil || info.BundleId != "com.example.app" {
+		t.Errorf("got %v %v want the app without error", info, err)
+	}
+}
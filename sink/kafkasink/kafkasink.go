@@ -0,0 +1,33 @@
+// Package kafkasink publishes parse results to a Kafka topic.
+package kafkasink
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/follyxing/appfile-info/sink"
+	"github.com/segmentio/kafka-go"
+)
+
+// Sink writes one message per event, keyed by bundle id so all builds of an
+// app land in the same partition.
+type Sink struct {
+	w *kafka.Writer
+}
+
+func New(w *kafka.Writer) *Sink {
+	return &Sink{w: w}
+}
+
+func (s *Sink) Publish(ctx context.Context, e sink.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	msg := kafka.Message{Value: data}
+	if e.Info != nil {
+		msg.Key = []byte(e.Info.BundleId)
+	}
+	return s.w.WriteMessages(ctx, msg)
+}
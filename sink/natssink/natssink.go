@@ -0,0 +1,27 @@
+// Package natssink publishes parse results to a NATS subject.
+package natssink
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/follyxing/appfile-info/sink"
+	"github.com/nats-io/nats.go"
+)
+
+type Sink struct {
+	conn    *nats.Conn
+	subject string
+}
+
+func New(conn *nats.Conn, subject string) *Sink {
+	return &Sink{conn: conn, subject: subject}
+}
+
+func (s *Sink) Publish(ctx context.Context, e sink.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return s.conn.Publish(s.subject, data)
+}
@@ -0,0 +1,157 @@
+// Package sink publishes parse results to files, webhooks and queues.
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/follyxing/appfile-info"
+)
+
+// Event describes one parsed artifact.
+type Event struct {
+	Path    string           `json:"path"`
+	Info    *appfile.AppInfo `json:"info"`
+	IconURL string           `json:"icon_url,omitempty"`
+}
+
+// Sink receives parse results.
+type Sink interface {
+	Publish(ctx context.Context, e Event) error
+}
+
+// Notifier adapts a Sink to appfile.Notifier so results are published as
+// soon as appfile.NewAppParser completes.
+type Notifier struct {
+	Sink Sink
+	// IconURL, if set, returns the URL the artifact's icon is served at.
+	IconURL func(name string, info *appfile.AppInfo) string
+}
+
+func (n *Notifier) Notify(ctx context.Context, name string, info *appfile.AppInfo) error {
+	e := Event{Path: name, Info: info}
+	if n.IconURL != nil {
+		e.IconURL = n.IconURL(name, info)
+	}
+	return n.Sink.Publish(ctx, e)
+}
+
+// JSONFile appends one JSON document per event to a file.
+type JSONFile struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewJSONFile(path string) *JSONFile {
+	return &JSONFile{path: path}
+}
+
+func (s *JSONFile) Publish(ctx context.Context, e Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// JSONWriter writes one JSON document per event to an io.Writer.
+type JSONWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewJSONWriter(w io.Writer) *JSONWriter {
+	return &JSONWriter{w: w}
+}
+
+func (s *JSONWriter) Publish(ctx context.Context, e Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(data, '\n'))
+	return err
+}
+
+// Webhook POSTs each event as JSON to a URL.
+type Webhook struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhook returns a Webhook using client, or http.DefaultClient if nil.
+func NewWebhook(url string, client *http.Client) *Webhook {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Webhook{url: url, client: client}
+}
+
+func (s *Webhook) Publish(ctx context.Context, e Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook %s: unexpected status %s", s.url, resp.Status)
+	}
+	return nil
+}
+
+// Queue is an in-process buffered queue of events.
+type Queue struct {
+	c chan Event
+}
+
+func NewQueue(size int) *Queue {
+	return &Queue{c: make(chan Event, size)}
+}
+
+// Publish blocks until the event is queued or ctx is done.
+func (s *Queue) Publish(ctx context.Context, e Event) error {
+	select {
+	case s.c <- e:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// C returns the channel events are delivered on.
+func (s *Queue) C() <-chan Event {
+	return s.c
+}
@@ -0,0 +1,133 @@
+package sink
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/follyxing/appfile-info"
+)
+
+func TestJSONFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sink")
+	if err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "out.json")
+	s := NewJSONFile(path)
+	for _, id := range []string{"a", "b"} {
+		if err := s.Publish(context.Background(), Event{Path: id + ".apk", Info: &appfile.AppInfo{BundleId: id}}); err != nil {
+			t.Errorf("got %v want no error", err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	defer f.Close()
+
+	var ids []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Errorf("got %v want no error", err)
+			continue
+		}
+		ids = append(ids, e.Info.BundleId)
+	}
+	if len(ids) != 2 || ids[0] != "a" || ids[1] != "b" {
+		t.Errorf("got %v want %v", ids, []string{"a", "b"})
+	}
+}
+
+func TestJSONWriter(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewJSONWriter(&buf)
+	if err := s.Publish(context.Background(), Event{Path: "a.apk", Info: &appfile.AppInfo{BundleId: "a"}}); err != nil {
+		t.Errorf("got %v want no error", err)
+	}
+
+	var e Event
+	if err := json.Unmarshal(buf.Bytes(), &e); err != nil {
+		t.Errorf("got %v want no error", err)
+	}
+	if e.Path != "a.apk" || e.Info == nil || e.Info.BundleId != "a" {
+		t.Errorf("got %v want %v", e, "a.apk")
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	var got Event
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	s := NewWebhook(srv.URL, nil)
+	if err := s.Publish(context.Background(), Event{Path: "a.apk", Info: &appfile.AppInfo{BundleId: "a"}}); err != nil {
+		t.Errorf("got %v want no error", err)
+	}
+	if got.Path != "a.apk" || got.Info == nil || got.Info.BundleId != "a" {
+		t.Errorf("got %v want %v", got, "a.apk")
+	}
+}
+
+func TestWebhookStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	s := NewWebhook(srv.URL, nil)
+	if err := s.Publish(context.Background(), Event{}); err == nil {
+		t.Errorf("got nil want error")
+	}
+}
+
+func TestQueue(t *testing.T) {
+	q := NewQueue(1)
+	if err := q.Publish(context.Background(), Event{Path: "a.apk"}); err != nil {
+		t.Errorf("got %v want no error", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := q.Publish(ctx, Event{Path: "b.apk"}); err != context.Canceled {
+		t.Errorf("got %v want %v", err, context.Canceled)
+	}
+	if e := <-q.C(); e.Path != "a.apk" {
+		t.Errorf("got %v want %v", e.Path, "a.apk")
+	}
+}
+
+func TestNotifier(t *testing.T) {
+	q := NewQueue(1)
+	n := &Notifier{
+		Sink: q,
+		IconURL: func(name string, info *appfile.AppInfo) string {
+			return "https://example.com/icons/" + info.BundleId + ".png"
+		},
+	}
+	if err := n.Notify(context.Background(), "a.apk", &appfile.AppInfo{BundleId: "a"}); err != nil {
+		t.Errorf("got %v want no error", err)
+	}
+
+	e := <-q.C()
+	if e.Path != "a.apk" {
+		t.Errorf("got %v want %v", e.Path, "a.apk")
+	}
+	if e.IconURL != "https://example.com/icons/a.png" {
+		t.Errorf("got %v want %v", e.IconURL, "https://example.com/icons/a.png")
+	}
+}
@@ -0,0 +1,75 @@
+package appfile
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path"
+)
+
+// ErrTooLarge is returned for streams and archive entries above the
+// limit set with WithMaxSize.
+var ErrTooLarge = errors.New("artifact exceeds size limit")
+
+// WithSpoolDir spools streams and the packages extracted from .zip and
+// .tar.gz containers to dir instead of the system temporary directory,
+// e.g. a volume sized for the largest accepted upload. Archives decrypted
+// with WithArchivePassword are rebuilt there instead of in memory.
+func WithSpoolDir(dir string) Option {
+	return func(o *options) {
+		o.spoolDir = dir
+	}
+}
+
+// WithMaxSize fails with ErrTooLarge when a stream or an extracted package
+// is above n bytes, before more than n+1 bytes are spooled, and when an
+// encrypted archive is, before it is decrypted.
+func WithMaxSize(n int64) Option {
+	return func(o *options) {
+		o.maxSize = n
+	}
+}
+
+// ParseReader parses the artifact read from r, such as a streamed HTTP
+// upload, which need not fit in memory: it is spooled to a temporary file
+// that is removed before ParseReader returns. name picks the format by its
+// extension, e.g. "upload.ipa", and is the name notifiers are called with.
+func ParseReader(r io.Reader, name string, opts ...Option) (info *AppInfo, err error) {
+	o := newOptions(opts)
+	end := o.startStage(StageParse)
+	defer func() { end(err) }()
+
+	endSpool := o.startStage(StageSpool)
+	tmp, err := o.spool(name, r)
+	endSpool(err)
+	if err != nil {
+		var errs stageErrors
+		errs.add(StageSpool, err)
+		return nil, errs.err()
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	return o.parseNamed(tmp.Name(), name)
+}
+
+// spool copies r to a temporary file with the extension of name, as some
+// formats reopen the file by name. The caller must remove it.
+func (o *options) spool(name string, r io.Reader) (*os.File, error) {
+	tmp, err := os.CreateTemp(o.spoolDir, "appfile-*"+path.Ext(name))
+	if err != nil {
+		return nil, err
+	}
+	if o.maxSize > 0 {
+		r = io.LimitReader(r, o.maxSize+1)
+	}
+	n, err := io.Copy(tmp, r)
+	if err == nil && o.maxSize > 0 && n > o.maxSize {
+		err = ErrTooLarge
+	}
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, err
+	}
+	return tmp, nil
+}
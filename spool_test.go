@@ -0,0 +1,44 @@
+package appfile
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/follyxing/appfile-info/fixture"
+)
+
+func TestParseReader(t *testing.T) {
+	data, err := (&fixture.APK{Package: "com.example.stream", VersionCode: 3, VersionName: "1.3"}).Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+
+	n := new(recordingNotifier)
+	info, err := ParseReader(bytes.NewReader(data), "upload.apk", WithSpoolDir(dir), WithNotifier(n))
+	if info == nil {
+		t.Fatal(err)
+	}
+	if info.BundleId != "com.example.stream" || info.Size != int64(len(data)) {
+		t.Errorf("got %v %v want %v %v", info.BundleId, info.Size, "com.example.stream", len(data))
+	}
+	if want := []string{"upload.apk com.example.stream"}; !reflect.DeepEqual(n.names, want) {
+		t.Errorf("got %v want %v", n.names, want)
+	}
+
+	_, err = ParseReader(bytes.NewReader(data), "upload.apk", WithSpoolDir(dir), WithMaxSize(int64(len(data)-1)))
+	if !errors.Is(err, ErrTooLarge) {
+		t.Errorf("got %v want %v", err, ErrTooLarge)
+	}
+	var serr *StageError
+	if !errors.As(err, &serr) || serr.Stage != StageSpool {
+		t.Errorf("got %v want stage %v", err, StageSpool)
+	}
+
+	if files, _ := ioutil.ReadDir(dir); len(files) != 0 {
+		t.Errorf("got %d spooled files left want 0", len(files))
+	}
+}
@@ -0,0 +1,36 @@
+package appfile
+
+import "sort"
+
+// sortSets sorts the slices of info whose order carries no meaning, such
+// as ABIs and features, so two parses of equivalent artifacts compare
+// equal whatever order their manifest or archive lists them in. Android
+// permissions keep their manifest order, which aapt prints. Maps need
+// nothing: encoding/json writes their keys sorted.
+func (info *AppInfo) sortSets() {
+	sets := [][]string{info.Hosts}
+	if a := info.Android; a != nil {
+		sets = append(sets, a.RequiredFeatures, a.OptionalFeatures, a.ABIs)
+		if a.Screens != nil {
+			sets = append(sets, a.Screens.DensitySplits)
+		}
+	}
+	if i := info.Ios; i != nil {
+		sets = append(sets, i.DeviceFamilies, i.RequiredCapabilities)
+		profiles := []*ProvisioningProfile{i.Profile}
+		for _, b := range i.BundleProfiles {
+			profiles = append(profiles, b.ProvisioningProfile)
+		}
+		for _, p := range profiles {
+			if p != nil {
+				sets = append(sets, p.Platform, p.ProvisionedDevices)
+			}
+		}
+	}
+	if info.Tizen != nil {
+		sets = append(sets, info.Tizen.Privileges)
+	}
+	for _, s := range sets {
+		sort.Strings(s)
+	}
+}
@@ -0,0 +1,35 @@
+package appfile
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestSortSets(t *testing.T) {
+	info := newAppInfo(PlatformAndroid)
+	info.Android.Permissions = []string{"android.permission.INTERNET", "android.permission.CAMERA"}
+	info.Android.ABIs = []string{"x86_64", "arm64-v8a"}
+	info.sortSets()
+	if want := []string{"android.permission.INTERNET", "android.permission.CAMERA"}; !reflect.DeepEqual(info.Android.Permissions, want) {
+		t.Errorf("got %v want %v", info.Android.Permissions, want)
+	}
+	if want := []string{"arm64-v8a", "x86_64"}; !reflect.DeepEqual(info.Android.ABIs, want) {
+		t.Errorf("got %v want %v", info.Android.ABIs, want)
+	}
+}
+
+func TestParseStable(t *testing.T) {
+	var docs []string
+	for i := 0; i < 2; i++ {
+		info, _ := NewAppParser("testdata/helloworld.apk")
+		b, err := json.Marshal(info)
+		if err != nil {
+			t.Fatal(err)
+		}
+		docs = append(docs, string(b))
+	}
+	if docs[0] != docs[1] {
+		t.Errorf("got %s want %s", docs[1], docs[0])
+	}
+}
@@ -0,0 +1,61 @@
+package appfile
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+)
+
+const (
+	startupProvider = "androidx.startup.InitializationProvider"
+	startupMarker   = "androidx.startup"
+)
+
+// Where APKs and app bundles keep the baseline profile the Android Gradle
+// plugin compiles; the .profm metadata sits next to it.
+var baselineProfileFiles = []string{
+	"assets/dexopt/baseline.prof",
+	"BUNDLE-METADATA/com.android.tools.build.profiles/baseline.prof",
+}
+
+// startupInitializers returns the androidx.startup initializers: the
+// meta-data of InitializationProvider whose value is "androidx.startup".
+func startupInitializers(m *androidManifest) []string {
+	var names []string
+	for _, p := range m.Application.Providers {
+		if p.Name != startupProvider {
+			continue
+		}
+		for _, meta := range p.MetaData {
+			if meta.Value == startupMarker {
+				names = append(names, meta.Name)
+			}
+		}
+	}
+	return names
+}
+
+// parseBaselineProfile returns the version of the baseline profile among
+// files, e.g. "010", or "" if there is none. A profile with an unreadable
+// header has version "unknown".
+func parseBaselineProfile(files []*zip.File) string {
+	for _, name := range baselineProfileFiles {
+		f := findZipFile(files, name)
+		if f == nil {
+			continue
+		}
+		// "pro\x00", then the version as three digits and a NUL
+		buf := make([]byte, 8)
+		rc, err := f.Open()
+		if err != nil {
+			return "unknown"
+		}
+		_, err = io.ReadFull(rc, buf)
+		rc.Close()
+		if err != nil || !bytes.HasPrefix(buf, []byte("pro\x00")) {
+			return "unknown"
+		}
+		return string(bytes.TrimRight(buf[4:], "\x00"))
+	}
+	return ""
+}
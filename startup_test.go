@@ -0,0 +1,45 @@
+package appfile
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestStartupInitializers(t *testing.T) {
+	m := new(androidManifest)
+	m.Application.Providers = []androidProvider{{
+		Name: startupProvider,
+		MetaData: []androidMetaData{
+			{Name: "androidx.work.WorkManagerInitializer", Value: startupMarker},
+			{Name: "com.example.Other", Value: "x"},
+		},
+	}}
+	if got := startupInitializers(m); !reflect.DeepEqual(got, []string{"androidx.work.WorkManagerInitializer"}) {
+		t.Errorf("got %v want %v", got, []string{"androidx.work.WorkManagerInitializer"})
+	}
+}
+
+func TestParseBaselineProfile(t *testing.T) {
+	for content, want := range map[string]string{
+		"pro\x00010\x00\x01\x02": "010",
+		"garbage":                "unknown",
+	} {
+		var buf bytes.Buffer
+		w := zip.NewWriter(&buf)
+		f, _ := w.Create("assets/dexopt/baseline.prof")
+		f.Write([]byte(content))
+		w.Close()
+		reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := parseBaselineProfile(reader.File); got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	}
+	if got := parseBaselineProfile(nil); got != "" {
+		t.Errorf("got %q want none", got)
+	}
+}
@@ -0,0 +1,169 @@
+package store
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fakeDriver is a database/sql driver that understands just the
+// statements of this package, keeping each DSN's tables in memory.
+type fakeDriver struct {
+	mu  sync.Mutex
+	dbs map[string]*fakeDB
+}
+
+type fakeDB struct {
+	mu         sync.Mutex
+	tables     map[string]bool
+	migrations []int64
+	apps       []fakeApp
+}
+
+type fakeApp struct {
+	sha256, path, bundleID, info string
+	icon                         []byte
+	createdAt                    time.Time
+}
+
+func init() {
+	sql.Register("appfile-fake", &fakeDriver{dbs: make(map[string]*fakeDB)})
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	db, ok := d.dbs[name]
+	if !ok {
+		db = &fakeDB{tables: make(map[string]bool)}
+		d.dbs[name] = db
+	}
+	return &fakeConn{db}, nil
+}
+
+type fakeConn struct{ db *fakeDB }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{db: c.db, query: strings.Join(strings.Fields(query), " ")}, nil
+}
+
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	db := s.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	q := s.query
+	switch {
+	case strings.HasPrefix(q, "CREATE TABLE IF NOT EXISTS schema_migrations"):
+		db.tables["schema_migrations"] = true
+	case strings.HasPrefix(q, "CREATE TABLE apps"):
+		if db.tables["apps"] {
+			return nil, errors.New("table apps already exists")
+		}
+		db.tables["apps"] = true
+	case strings.HasPrefix(q, "CREATE INDEX"):
+	case strings.HasPrefix(q, "INSERT INTO schema_migrations"):
+		db.migrations = append(db.migrations, args[0].(int64))
+	case strings.HasPrefix(q, "INSERT INTO apps"):
+		if !db.tables["apps"] {
+			return nil, errors.New("no such table: apps")
+		}
+		app := fakeApp{
+			sha256:    args[0].(string),
+			path:      args[1].(string),
+			bundleID:  args[4].(string),
+			info:      args[8].(string),
+			createdAt: args[10].(time.Time),
+		}
+		app.icon, _ = args[9].([]byte)
+		for i := range db.apps {
+			if db.apps[i].sha256 == app.sha256 {
+				// ON CONFLICT keeps created_at.
+				app.createdAt = db.apps[i].createdAt
+				db.apps[i] = app
+				return driver.RowsAffected(1), nil
+			}
+		}
+		db.apps = append(db.apps, app)
+	default:
+		return nil, errors.New("fake: unsupported statement " + q)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	db := s.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	q := s.query
+	switch {
+	case strings.HasPrefix(q, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations"):
+		var max int64
+		for _, v := range db.migrations {
+			if v > max {
+				max = v
+			}
+		}
+		return &fakeRows{cols: []string{"version"}, rows: [][]driver.Value{{max}}}, nil
+	case strings.HasPrefix(q, strings.Join(strings.Fields(selectRecord), " ")):
+		var apps []fakeApp
+		for _, app := range db.apps {
+			switch {
+			case strings.Contains(q, "WHERE sha256 ="):
+				if app.sha256 == args[0] {
+					apps = append(apps, app)
+				}
+			case strings.Contains(q, "WHERE bundle_id ="):
+				if app.bundleID == args[0] {
+					apps = append(apps, app)
+				}
+			}
+		}
+		if strings.Contains(q, "ORDER BY created_at DESC") {
+			sort.SliceStable(apps, func(i, j int) bool { return apps[i].createdAt.After(apps[j].createdAt) })
+		}
+		rows := &fakeRows{cols: []string{"sha256", "path", "info", "icon", "created_at"}}
+		for _, app := range apps {
+			rows.rows = append(rows.rows, []driver.Value{app.sha256, app.path, app.info, app.icon, app.createdAt})
+		}
+		return rows, nil
+	}
+	return nil, errors.New("fake: unsupported query " + q)
+}
+
+type fakeRows struct {
+	cols []string
+	rows [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.cols }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
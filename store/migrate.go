@@ -0,0 +1,74 @@
+package store
+
+import (
+	"context"
+	"strings"
+)
+
+// migrations are applied in order; never edit a released migration, append
+// a new one instead. {{blob}} and {{id}} are replaced per dialect.
+var migrations = []string{
+	`CREATE TABLE apps (
+		id {{id}},
+		sha256 TEXT NOT NULL UNIQUE,
+		path TEXT NOT NULL,
+		platform TEXT NOT NULL,
+		name TEXT NOT NULL,
+		bundle_id TEXT NOT NULL,
+		version TEXT NOT NULL,
+		build TEXT NOT NULL,
+		size BIGINT NOT NULL,
+		info TEXT NOT NULL,
+		icon {{blob}},
+		created_at TIMESTAMP NOT NULL
+	);
+	CREATE INDEX apps_bundle_id ON apps (bundle_id)`,
+}
+
+func (s *Store) migration(i int) []string {
+	r := strings.NewReplacer("{{id}}", "INTEGER PRIMARY KEY AUTOINCREMENT", "{{blob}}", "BLOB")
+	if s.dialect == Postgres {
+		r = strings.NewReplacer("{{id}}", "BIGSERIAL PRIMARY KEY", "{{blob}}", "BYTEA")
+	}
+	var stmts []string
+	for _, stmt := range strings.Split(r.Replace(migrations[i]), ";") {
+		if stmt = strings.TrimSpace(stmt); stmt != "" {
+			stmts = append(stmts, stmt)
+		}
+	}
+	return stmts
+}
+
+// Migrate brings the schema up to date. It is safe to call on every start.
+func (s *Store) Migrate(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY)`); err != nil {
+		return err
+	}
+
+	var version int
+	row := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`)
+	if err := row.Scan(&version); err != nil {
+		return err
+	}
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		for _, stmt := range s.migration(i) {
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+		if _, err := tx.ExecContext(ctx, s.rebind(`INSERT INTO schema_migrations (version) VALUES (?)`), i+1); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
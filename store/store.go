@@ -0,0 +1,154 @@
+// Package store persists parse results to SQLite or PostgreSQL through
+// database/sql. The caller opens the *sql.DB with a driver of its choice.
+package store
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"image/png"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/follyxing/appfile-info"
+)
+
+type Dialect int
+
+const (
+	SQLite Dialect = iota
+	Postgres
+)
+
+var ErrNotFound = errors.New("store: artifact not found")
+
+// Record is a stored artifact.
+type Record struct {
+	SHA256    string
+	Path      string
+	Info      *appfile.AppInfo
+	CreatedAt time.Time
+}
+
+type Store struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+func New(db *sql.DB, dialect Dialect) *Store {
+	return &Store{db: db, dialect: dialect}
+}
+
+// rebind rewrites ? placeholders for the dialect.
+func (s *Store) rebind(query string) string {
+	if s.dialect != Postgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Save stores info under the artifact's SHA-256, replacing an earlier
+// record of the same artifact.
+func (s *Store) Save(ctx context.Context, sha256, path string, info *appfile.AppInfo) error {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	var icon []byte
+	if info.Icon != nil {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, info.Icon); err != nil {
+			return err
+		}
+		icon = buf.Bytes()
+	}
+
+	_, err = s.db.ExecContext(ctx, s.rebind(`
+		INSERT INTO apps (sha256, path, platform, name, bundle_id, version, build, size, info, icon, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (sha256) DO UPDATE SET
+			path = excluded.path,
+			platform = excluded.platform,
+			name = excluded.name,
+			bundle_id = excluded.bundle_id,
+			version = excluded.version,
+			build = excluded.build,
+			size = excluded.size,
+			info = excluded.info,
+			icon = excluded.icon`),
+		sha256, path, info.Platform, info.Name, info.BundleId, info.Version, info.Build, info.Size,
+		string(data), icon, time.Now().UTC())
+	return err
+}
+
+const selectRecord = `SELECT sha256, path, info, icon, created_at FROM apps`
+
+// Get returns the record of the artifact with the given SHA-256.
+func (s *Store) Get(ctx context.Context, sha256 string) (*Record, error) {
+	row := s.db.QueryRowContext(ctx, s.rebind(selectRecord+` WHERE sha256 = ?`), sha256)
+	r, err := scanRecord(row)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return r, err
+}
+
+// FindByBundleId returns all stored builds of an app, newest first.
+func (s *Store) FindByBundleId(ctx context.Context, bundleId string) ([]*Record, error) {
+	rows, err := s.db.QueryContext(ctx, s.rebind(selectRecord+` WHERE bundle_id = ? ORDER BY created_at DESC`), bundleId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []*Record
+	for rows.Next() {
+		r, err := scanRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRecord(row scanner) (*Record, error) {
+	var (
+		r    Record
+		data string
+		icon []byte
+	)
+	if err := row.Scan(&r.SHA256, &r.Path, &data, &icon, &r.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	r.Info = new(appfile.AppInfo)
+	if err := json.Unmarshal([]byte(data), r.Info); err != nil {
+		return nil, err
+	}
+	if len(icon) > 0 {
+		img, err := png.Decode(bytes.NewReader(icon))
+		if err != nil {
+			return nil, err
+		}
+		r.Info.Icon = img
+	}
+	return &r, nil
+}
@@ -0,0 +1,118 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"image"
+	"image/color"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/follyxing/appfile-info"
+)
+
+func TestRebind(t *testing.T) {
+	query := `SELECT * FROM apps WHERE sha256 = ? AND bundle_id = ?`
+	if got := New(nil, SQLite).rebind(query); got != query {
+		t.Errorf("got %v want %v", got, query)
+	}
+	want := `SELECT * FROM apps WHERE sha256 = $1 AND bundle_id = $2`
+	if got := New(nil, Postgres).rebind(query); got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestMigrationDialects(t *testing.T) {
+	sqlite := New(nil, SQLite).migration(0)
+	postgres := New(nil, Postgres).migration(0)
+	if len(sqlite) != 2 || len(postgres) != 2 {
+		t.Fatalf("got %v and %v statements want 2", len(sqlite), len(postgres))
+	}
+	if !strings.Contains(sqlite[0], "icon BLOB") || !strings.Contains(sqlite[0], "AUTOINCREMENT") {
+		t.Errorf("got %v want sqlite types", sqlite[0])
+	}
+	if !strings.Contains(postgres[0], "icon BYTEA") || !strings.Contains(postgres[0], "BIGSERIAL") {
+		t.Errorf("got %v want postgres types", postgres[0])
+	}
+	for _, stmt := range append(sqlite, postgres...) {
+		if strings.Contains(stmt, "{{") {
+			t.Errorf("got unreplaced placeholder in %v", stmt)
+		}
+	}
+}
+
+func openStore(t *testing.T, dialect Dialect) *Store {
+	db, err := sql.Open("appfile-fake", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	s := New(db, dialect)
+	for i := 0; i < 2; i++ {
+		if err := s.Migrate(context.Background()); err != nil {
+			t.Fatalf("got %v want no error", err)
+		}
+	}
+	return s
+}
+
+func TestStore(t *testing.T) {
+	for name, dialect := range map[string]Dialect{"sqlite": SQLite, "postgres": Postgres} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			s := openStore(t, dialect)
+
+			icon := image.NewRGBA(image.Rect(0, 0, 2, 2))
+			icon.Set(1, 1, color.RGBA{R: 255, A: 255})
+			old := &appfile.AppInfo{Platform: appfile.PlatformAndroid, BundleId: "com.example.app", Version: "1.0", Build: "1", Icon: icon}
+			if err := s.Save(ctx, "aaa", "app-1.apk", old); err != nil {
+				t.Fatalf("got %v want no error", err)
+			}
+			if err := s.Save(ctx, "bbb", "app-2.apk", &appfile.AppInfo{BundleId: "com.example.app", Version: "2.0", Build: "2"}); err != nil {
+				t.Fatalf("got %v want no error", err)
+			}
+			if err := s.Save(ctx, "ccc", "other.apk", &appfile.AppInfo{BundleId: "com.example.other"}); err != nil {
+				t.Fatalf("got %v want no error", err)
+			}
+
+			r, err := s.Get(ctx, "aaa")
+			if err != nil {
+				t.Fatalf("got %v want no error", err)
+			}
+			if r.Path != "app-1.apk" || r.Info.Version != "1.0" || r.Info.Icon == nil {
+				t.Fatalf("got %v %+v want the saved record", r.Path, r.Info)
+			}
+			if red, _, _, _ := r.Info.Icon.At(1, 1).RGBA(); red != 0xffff {
+				t.Errorf("got red %#x want %#x", red, 0xffff)
+			}
+			if _, err := s.Get(ctx, "missing"); err != ErrNotFound {
+				t.Errorf("got %v want %v", err, ErrNotFound)
+			}
+
+			// Saving an artifact again replaces its record.
+			old.Version = "1.0.1"
+			if err := s.Save(ctx, "aaa", "renamed.apk", old); err != nil {
+				t.Fatalf("got %v want no error", err)
+			}
+			if r, err := s.Get(ctx, "aaa"); err != nil || r.Path != "renamed.apk" || r.Info.Version != "1.0.1" {
+				t.Errorf("got %v %v want the replaced record", r, err)
+			}
+
+			records, err := s.FindByBundleId(ctx, "com.example.app")
+			if err != nil {
+				t.Fatalf("got %v want no error", err)
+			}
+			var got []string
+			for _, r := range records {
+				got = append(got, r.SHA256)
+			}
+			if want := []string{"bbb", "aaa"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v want %v", got, want)
+			}
+			if records, err := s.FindByBundleId(ctx, "com.example.none"); err != nil || len(records) != 0 {
+				t.Errorf("got %v %v want no records", records, err)
+			}
+		})
+	}
+}
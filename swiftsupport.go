@@ -0,0 +1,61 @@
+package appfile
+
+import (
+	"archive/zip"
+	"path"
+	"sort"
+	"strings"
+)
+
+// checkIpaSupportFolders records the SwiftSupport/ and Symbols/ folders
+// and warns where they do not match what App Store Connect expects: apps
+// bundling the Swift runtime need a matching SwiftSupport/, which
+// other distribution methods ignore.
+func checkIpaSupportFolders(files []*zip.File, info *AppInfo) {
+	bundled, supported := make(map[string]bool), make(map[string]bool)
+	for _, f := range files {
+		parts := strings.Split(f.Name, "/")
+		base := parts[len(parts)-1]
+		switch {
+		case parts[0] == "SwiftSupport":
+			info.Ios.SwiftSupport = true
+			if strings.HasPrefix(base, "libswift") {
+				supported[base] = true
+			}
+		case parts[0] == "Symbols":
+			info.Ios.Symbols = true
+		case len(parts) == 4 && parts[0] == "Payload" && parts[2] == "Frameworks" &&
+			strings.HasPrefix(base, "libswift") && path.Ext(base) == ".dylib":
+			bundled[base] = true
+		}
+	}
+
+	signing := ""
+	if info.Ios.Profile != nil {
+		signing = info.Ios.Profile.SigningType
+	}
+	if signing != "app-store" {
+		if signing != "" && info.Ios.SwiftSupport {
+			info.warn("SwiftSupport/ is only used by App Store Connect and is ignored for %s builds", signing)
+		}
+		return
+	}
+
+	if len(bundled) > 0 && !info.Ios.SwiftSupport {
+		info.warn("SwiftSupport/ is missing but the app bundles the Swift runtime; export the archive with Xcode to add it")
+		return
+	}
+	var missing []string
+	for lib := range bundled {
+		if !supported[lib] {
+			missing = append(missing, lib)
+		}
+	}
+	sort.Strings(missing)
+	for _, lib := range missing {
+		info.warn("SwiftSupport/ lacks %s, which the app bundles in Frameworks/", lib)
+	}
+	if !info.Ios.Symbols {
+		info.warn("Symbols/ is missing; App Store crash reports will not be symbolicated")
+	}
+}
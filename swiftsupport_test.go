@@ -0,0 +1,46 @@
+package appfile
+
+import (
+	"archive/zip"
+	"reflect"
+	"testing"
+)
+
+func TestCheckIpaSupportFolders(t *testing.T) {
+	files := func(names ...string) []*zip.File {
+		fs := make([]*zip.File, len(names))
+		for i, n := range names {
+			fs[i] = &zip.File{FileHeader: zip.FileHeader{Name: n}}
+		}
+		return fs
+	}
+	for _, tt := range []struct {
+		signing string
+		files   []*zip.File
+		want    []string
+	}{
+		{"app-store", files("Payload/A.app/A", "Symbols/1234.symbols"), nil},
+		{"app-store", files("Payload/A.app/Frameworks/libswiftCore.dylib", "Symbols/1234.symbols"), []string{
+			"SwiftSupport/ is missing but the app bundles the Swift runtime; export the archive with Xcode to add it",
+		}},
+		{"app-store", files(
+			"Payload/A.app/Frameworks/libswiftCore.dylib",
+			"Payload/A.app/Frameworks/libswiftUIKit.dylib",
+			"SwiftSupport/iphoneos/libswiftCore.dylib",
+		), []string{
+			"SwiftSupport/ lacks libswiftUIKit.dylib, which the app bundles in Frameworks/",
+			"Symbols/ is missing; App Store crash reports will not be symbolicated",
+		}},
+		{"ad-hoc", files("SwiftSupport/iphoneos/libswiftCore.dylib"), []string{
+			"SwiftSupport/ is only used by App Store Connect and is ignored for ad-hoc builds",
+		}},
+		{"development", files("Payload/A.app/Frameworks/libswiftCore.dylib"), nil},
+	} {
+		info := newAppInfo(PlatformIOS)
+		info.Ios.Profile = &ProvisioningProfile{SigningType: tt.signing}
+		checkIpaSupportFolders(tt.files, info)
+		if !reflect.DeepEqual(info.Warnings, tt.want) {
+			t.Errorf("got %q want %q", info.Warnings, tt.want)
+		}
+	}
+}
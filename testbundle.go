@@ -0,0 +1,56 @@
+package appfile
+
+import (
+	"archive/zip"
+	"path"
+	"strings"
+)
+
+// Kinds of TestInfo.
+const (
+	TestInstrumentation = "instrumentation"
+	TestOnly            = "test_only"
+	TestXCTest          = "xctest"
+)
+
+type androidInstrumentation struct {
+	Name          string `xml:"name,attr"`
+	TargetPackage string `xml:"targetPackage,attr"`
+}
+
+// newAndroidTestInfo classifies androidTest APKs by their <instrumentation>
+// and others by android:testOnly, which Android Studio sets on the builds
+// it deploys and the Play Store refuses.
+func newAndroidTestInfo(manifest *androidManifest) *TestInfo {
+	if len(manifest.Instrumentations) > 0 {
+		in := manifest.Instrumentations[0]
+		return &TestInfo{
+			Kind:          TestInstrumentation,
+			Runner:        manifest.className(in.Name),
+			TargetPackage: in.TargetPackage,
+		}
+	}
+	if manifest.Application.TestOnly == "true" {
+		return &TestInfo{Kind: TestOnly}
+	}
+	return nil
+}
+
+// newIpaTestInfo classifies the runners xcodebuild build-for-testing
+// makes, which carry their tests as PlugIns/*.xctest bundles.
+func newIpaTestInfo(files []*zip.File) *TestInfo {
+	var bundles []string
+	seen := make(map[string]bool)
+	for _, f := range files {
+		parts := strings.SplitN(f.Name, "/", 5)
+		if len(parts) < 4 || parts[2] != "PlugIns" || path.Ext(parts[3]) != ".xctest" || seen[parts[3]] {
+			continue
+		}
+		seen[parts[3]] = true
+		bundles = append(bundles, parts[3])
+	}
+	if len(bundles) == 0 {
+		return nil
+	}
+	return &TestInfo{Kind: TestXCTest, Bundles: bundles}
+}
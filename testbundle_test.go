@@ -0,0 +1,65 @@
+package appfile
+
+import (
+	"archive/zip"
+	"reflect"
+	"testing"
+
+	"github.com/follyxing/appfile-info/fixture"
+)
+
+func TestAndroidTestInfo(t *testing.T) {
+	spec := &fixture.APK{Package: "com.example.app.test"}
+	manifest := spec.Manifest()
+	manifest.Children = append(manifest.Children, &fixture.Element{
+		Name: "instrumentation",
+		Attrs: []fixture.Attr{
+			{Android: true, Name: "name", Value: "androidx.test.runner.AndroidJUnitRunner"},
+			{Android: true, Name: "targetPackage", Value: "com.example.app"},
+		},
+	})
+	data, err := fixture.Zip(map[string][]byte{"AndroidManifest.xml": fixture.AXML(manifest)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, _ := NewAppParser(writeFile(t, "test.apk", data))
+	if info == nil {
+		t.Fatal("no AppInfo")
+	}
+	want := &TestInfo{Kind: TestInstrumentation, Runner: "androidx.test.runner.AndroidJUnitRunner", TargetPackage: "com.example.app"}
+	if !reflect.DeepEqual(info.Test, want) {
+		t.Errorf("got %+v want %+v", info.Test, want)
+	}
+
+	app := &AppInfo{BundleId: "com.example.app", Platform: PlatformAndroid}
+	catalogRoles([]*AppInfo{app, info})
+	if info.Role != RoleTest || info.Parent != "com.example.app" || app.Role != RolePrimary {
+		t.Errorf("got %v %v %v want %v %v %v", info.Role, info.Parent, app.Role, RoleTest, "com.example.app", RolePrimary)
+	}
+
+	if got := newAndroidTestInfo(&androidManifest{Application: androidApplication{TestOnly: "true"}}); got == nil || got.Kind != TestOnly {
+		t.Errorf("got %+v want %v", got, TestOnly)
+	}
+	if got := newAndroidTestInfo(&androidManifest{}); got != nil {
+		t.Errorf("got %+v want nil", got)
+	}
+}
+
+func TestIpaTestInfo(t *testing.T) {
+	var files []*zip.File
+	for _, name := range []string{
+		"Payload/AppUITests-Runner.app/Info.plist",
+		"Payload/AppUITests-Runner.app/PlugIns/AppUITests.xctest/Info.plist",
+		"Payload/AppUITests-Runner.app/PlugIns/AppUITests.xctest/AppUITests",
+		"Payload/AppUITests-Runner.app/Frameworks/XCTest.framework/XCTest",
+	} {
+		files = append(files, &zip.File{FileHeader: zip.FileHeader{Name: name}})
+	}
+	want := &TestInfo{Kind: TestXCTest, Bundles: []string{"AppUITests.xctest"}}
+	if got := newIpaTestInfo(files); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+	if got := newIpaTestInfo(files[:1]); got != nil {
+		t.Errorf("got %+v want nil", got)
+	}
+}
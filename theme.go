@@ -0,0 +1,64 @@
+package appfile
+
+import "fmt"
+
+const (
+	metaNotificationIcon  = "com.google.firebase.messaging.default_notification_icon"
+	metaNotificationColor = "com.google.firebase.messaging.default_notification_color"
+
+	androidAttrColorPrimary = 0x01010433
+)
+
+type androidMetaData struct {
+	Name     string `xml:"name,attr"`
+	Value    string `xml:"value,attr"`
+	Resource string `xml:"resource,attr"`
+}
+
+// parseApkTheme resolves the theme's colorPrimary and the notification icon
+// and color apps configure for Firebase Cloud Messaging.
+func parseApkTheme(res *apkResources, manifest *androidManifest, android *AndroidInfo) {
+	app := manifest.Application
+	for _, m := range app.MetaData {
+		switch m.Name {
+		case metaNotificationIcon:
+			android.NotificationIcon = res.image(m.Resource)
+		case metaNotificationColor:
+			if t, id, ok := res.lookup(m.Resource); ok {
+				if c, ok := t.color(arscValue{typ: resValueReference, data: id}, 0); ok {
+					android.NotificationColor = formatColor(c)
+				}
+			}
+		}
+	}
+
+	t, theme, ok := res.lookup(app.Theme)
+	if !ok {
+		return
+	}
+	// AppCompat and Material Components themes set their own attribute,
+	// platform themes android:colorPrimary.
+	attrs := []uint32{androidAttrColorPrimary}
+	if id, ok := t.ids["attr/colorPrimary"]; ok {
+		attrs = append([]uint32{id}, attrs...)
+	}
+	for _, attr := range attrs {
+		v, ok := t.themeAttr(theme, attr)
+		if !ok {
+			continue
+		}
+		if c, ok := t.color(v, theme); ok {
+			android.ThemeColor = formatColor(c)
+			return
+		}
+	}
+}
+
+// formatColor formats an ARGB color as #rrggbb, or #aarrggbb if it is not
+// opaque.
+func formatColor(argb uint32) string {
+	if argb>>24 == 0xff {
+		return fmt.Sprintf("#%06x", argb&0xffffff)
+	}
+	return fmt.Sprintf("#%08x", argb)
+}
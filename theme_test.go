@@ -0,0 +1,36 @@
+package appfile
+
+import "testing"
+
+func TestParseApkTheme(t *testing.T) {
+	reader, err := getAppZipReader("testdata/helloworld.apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest := new(androidManifest)
+	manifest.Application.Theme = "@0x7F08008E"
+	manifest.Application.MetaData = []androidMetaData{
+		{Name: metaNotificationIcon, Resource: "@0x7F030000"},
+		{Name: metaNotificationColor, Resource: "@0x7F0A0014"},
+	}
+
+	android := new(AndroidInfo)
+	parseApkTheme(newApkResources(reader.File), manifest, android)
+	if android.ThemeColor != "#3f51b5" {
+		t.Errorf("got %v want %v", android.ThemeColor, "#3f51b5")
+	}
+	if android.NotificationColor != "#303f9f" {
+		t.Errorf("got %v want %v", android.NotificationColor, "#303f9f")
+	}
+	if android.NotificationIcon == nil || android.NotificationIcon.Bounds().Dx() != 192 {
+		t.Errorf("got %v want the xxxhdpi launcher icon", android.NotificationIcon)
+	}
+}
+
+func TestFormatColor(t *testing.T) {
+	for c, want := range map[uint32]string{0xff3f51b5: "#3f51b5", 0x803f51b5: "#803f51b5"} {
+		if got := formatColor(c); got != want {
+			t.Errorf("got %v want %v", got, want)
+		}
+	}
+}
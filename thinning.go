@@ -0,0 +1,162 @@
+package appfile
+
+import (
+	"archive/zip"
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ThinningReportName is the size report Xcode writes next to the variant
+// IPAs of an export with app thinning.
+const ThinningReportName = "App Thinning Size Report.txt"
+
+// ThinningVariant is a device variant of an app thinning export. Sizes are
+// in bytes; the compressed ones estimate the download, the uncompressed
+// ones the install size.
+type ThinningVariant struct {
+	Name        string              `json:"name"` // the variant .ipa
+	Descriptors []VariantDescriptor `json:"descriptors,omitempty"`
+
+	AppCompressed        int64 `json:"app_compressed"`
+	AppUncompressed      int64 `json:"app_uncompressed"`
+	OnDemandCompressed   int64 `json:"on_demand_compressed"`
+	OnDemandUncompressed int64 `json:"on_demand_uncompressed"`
+}
+
+// VariantDescriptor is a device and OS version a variant is built for,
+// e.g. iPhone11,4 and 15.0. The universal variant has none.
+type VariantDescriptor struct {
+	Device    string `json:"device"`
+	OSVersion string `json:"os_version"`
+}
+
+// ThinningVariants reports the variants of the export directory dir, as
+// Xcode's app thinning size report does. Without a ThinningReportName
+// report, the .ipa files in dir and dir/Apps are measured instead; their
+// descriptors are then unknown. On-Demand Resources count if the packs are
+// embedded in the IPA.
+func ThinningVariants(dir string) ([]ThinningVariant, error) {
+	if f, err := os.Open(filepath.Join(dir, ThinningReportName)); err == nil {
+		defer f.Close()
+		return ReadThinningReport(f)
+	}
+
+	var names []string
+	for _, pattern := range []string{"*.ipa", "Apps/*.ipa"} {
+		m, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, m...)
+	}
+	if len(names) == 0 {
+		return nil, errors.New("no app thinning variants in " + dir)
+	}
+	sort.Strings(names)
+
+	variants := make([]ThinningVariant, 0, len(names))
+	for _, name := range names {
+		v, err := measureVariant(name)
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, v)
+	}
+	return variants, nil
+}
+
+func measureVariant(name string) (ThinningVariant, error) {
+	v := ThinningVariant{Name: filepath.Base(name)}
+	r, err := zip.OpenReader(name)
+	if err != nil {
+		return v, err
+	}
+	defer r.Close()
+	for _, f := range r.File {
+		if strings.Contains(f.Name, ".assetpack/") {
+			v.OnDemandCompressed += int64(f.CompressedSize64)
+			v.OnDemandUncompressed += int64(f.UncompressedSize64)
+		} else {
+			v.AppCompressed += int64(f.CompressedSize64)
+			v.AppUncompressed += int64(f.UncompressedSize64)
+		}
+	}
+	return v, nil
+}
+
+var (
+	variantDescriptor = regexp.MustCompile(`\[device: (.+?), os-version: (.+?)\]`)
+	variantSize       = regexp.MustCompile(`^(.+?) compressed, (.+?) uncompressed$`)
+)
+
+// ReadThinningReport parses an app thinning size report.
+func ReadThinningReport(r io.Reader) ([]ThinningVariant, error) {
+	var variants []ThinningVariant
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ": ")
+		if !ok {
+			continue
+		}
+		if key == "Variant" {
+			variants = append(variants, ThinningVariant{Name: value})
+			continue
+		}
+		if len(variants) == 0 {
+			continue
+		}
+		v := &variants[len(variants)-1]
+		switch key {
+		case "Supported variant descriptors":
+			for _, m := range variantDescriptor.FindAllStringSubmatch(value, -1) {
+				v.Descriptors = append(v.Descriptors, VariantDescriptor{Device: m[1], OSVersion: m[2]})
+			}
+		case "App size":
+			v.AppCompressed, v.AppUncompressed = parseVariantSizes(value)
+		case "On Demand Resources size":
+			v.OnDemandCompressed, v.OnDemandUncompressed = parseVariantSizes(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(variants) == 0 {
+		return nil, errors.New("no variants in app thinning size report")
+	}
+	return variants, nil
+}
+
+// parseVariantSizes parses "6.7 MB compressed, 18.6 MB uncompressed".
+func parseVariantSizes(s string) (compressed, uncompressed int64) {
+	m := variantSize.FindStringSubmatch(s)
+	if m == nil {
+		return 0, 0
+	}
+	return parseReportSize(m[1]), parseReportSize(m[2])
+}
+
+// parseReportSize parses a size as Xcode prints it, e.g. "Zero KB",
+// "512 KB" or "6.7 MB", in powers of 1000.
+func parseReportSize(s string) int64 {
+	num, unit, _ := strings.Cut(s, " ")
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0
+	}
+	switch unit {
+	case "KB":
+		n *= 1e3
+	case "MB":
+		n *= 1e6
+	case "GB":
+		n *= 1e9
+	}
+	return int64(n)
+}
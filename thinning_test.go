@@ -0,0 +1,80 @@
+package appfile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const thinningReport = `App Thinning Size Report for All Variants of Example
+
+Variant: Example-0A1B.ipa
+Supported variant descriptors: [device: iPhone11,4, os-version: 15.0], [device: iPhone11,6, os-version: 15.0]
+App + On Demand Resources size: 6.7 MB compressed, 18.6 MB uncompressed
+App size: 6.7 MB compressed, 18.6 MB uncompressed
+On Demand Resources size: Zero KB compressed, Zero KB uncompressed
+
+
+Variant: Example.ipa
+Supported variant descriptors: Universal
+App + On Demand Resources size: 9.3 MB compressed, 25.1 MB uncompressed
+App size: 8.1 MB compressed, 22 MB uncompressed
+On Demand Resources size: 1.2 MB compressed, 3.1 MB uncompressed
+`
+
+func TestReadThinningReport(t *testing.T) {
+	got, err := ReadThinningReport(strings.NewReader(thinningReport))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ThinningVariant{
+		{
+			Name: "Example-0A1B.ipa",
+			Descriptors: []VariantDescriptor{
+				{Device: "iPhone11,4", OSVersion: "15.0"},
+				{Device: "iPhone11,6", OSVersion: "15.0"},
+			},
+			AppCompressed:   6700000,
+			AppUncompressed: 18600000,
+		},
+		{
+			Name:                 "Example.ipa",
+			AppCompressed:        8100000,
+			AppUncompressed:      22000000,
+			OnDemandCompressed:   1200000,
+			OnDemandUncompressed: 3100000,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+}
+
+func TestThinningVariants(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "Apps"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeZip(t, filepath.Join(dir, "Apps", "Example-0A1B.ipa"), map[string][]byte{
+		"Payload/Example.app/Example":                      make([]byte, 300),
+		"Payload/Example.app/com.example.tag1.assetpack/a": make([]byte, 100),
+	})
+
+	got, err := ThinningVariants(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Name != "Example-0A1B.ipa" || got[0].AppUncompressed != 300 || got[0].OnDemandUncompressed != 100 {
+		t.Errorf("got %+v want measured Example-0A1B.ipa", got)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, ThinningReportName), []byte(thinningReport), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ThinningVariants(dir); err != nil || len(got) != 2 || len(got[0].Descriptors) != 2 {
+		t.Errorf("got %+v %v want the report's variants", got, err)
+	}
+}
@@ -0,0 +1,167 @@
+package appfile
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+)
+
+const (
+	tpkExt = ".tpk"
+	wgtExt = ".wgt"
+)
+
+// A .tpk is a native Tizen package described by tizen-manifest.xml, a .wgt
+// a web app described by a W3C widget config.xml with tizen extensions.
+type tizenManifest struct {
+	Package    string `xml:"package,attr"`
+	Version    string `xml:"version,attr"`
+	ApiVersion string `xml:"api-version,attr"`
+	Profiles   []struct {
+		Name string `xml:"name,attr"`
+	} `xml:"profile"`
+	Applications []tizenApplication `xml:",any"`
+	Privileges   []string           `xml:"privileges>privilege"`
+}
+
+// tizenApplication is a ui-application, service-application,
+// watch-application or widget-application element.
+type tizenApplication struct {
+	XMLName xml.Name
+	Labels  []tizenLabel `xml:"label"`
+	Icon    string       `xml:"icon"`
+}
+
+type tizenLabel struct {
+	Lang  string `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	Value string `xml:",chardata"`
+}
+
+type tizenWidget struct {
+	Id          string       `xml:"id,attr"`
+	Version     string       `xml:"version,attr"`
+	Names       []tizenLabel `xml:"name"`
+	Application struct {
+		Package         string `xml:"package,attr"`
+		RequiredVersion string `xml:"required_version,attr"`
+	} `xml:"http://tizen.org/ns/widgets application"`
+	Icons []struct {
+		Src string `xml:"src,attr"`
+	} `xml:"icon"`
+	Profile struct {
+		Name string `xml:"name,attr"`
+	} `xml:"http://tizen.org/ns/widgets profile"`
+	Privileges []struct {
+		Name string `xml:"name,attr"`
+	} `xml:"http://tizen.org/ns/widgets privilege"`
+}
+
+func parseTpk(_ string, reader *zip.Reader, o *options) (*AppInfo, error) {
+	var errs stageErrors
+	end := o.startStage(StageManifest)
+	m := new(tizenManifest)
+	err := decodeZipXML(reader, "tizen-manifest.xml", m)
+	end(err)
+	if err != nil {
+		errs.add(StageManifest, err)
+		return nil, errs.err()
+	}
+
+	info := newAppInfo(PlatformTizen)
+	info.BundleId = m.Package
+	info.Version = m.Version
+	info.Tizen.ApiVersion = m.ApiVersion
+	if len(m.Profiles) > 0 {
+		info.Tizen.Profile = m.Profiles[0].Name
+	}
+	info.Tizen.Privileges = m.Privileges
+
+	var icon string
+	for _, app := range m.Applications {
+		if !isTizenApplication(app.XMLName.Local) {
+			continue
+		}
+		info.Name = tizenLabelValue(app.Labels)
+		if app.Icon != "" {
+			icon = "shared/res/" + app.Icon
+		}
+		break
+	}
+
+	end = o.startStage(StageIcon)
+	img, err := decodeZipIcon(reader, icon)
+	end(err)
+	errs.add(StageIcon, err)
+	info.setIcon(img)
+	return info, errs.err()
+}
+
+func parseWgt(_ string, reader *zip.Reader, o *options) (*AppInfo, error) {
+	var errs stageErrors
+	end := o.startStage(StageManifest)
+	w := new(tizenWidget)
+	err := decodeZipXML(reader, "config.xml", w)
+	end(err)
+	if err != nil {
+		errs.add(StageManifest, err)
+		return nil, errs.err()
+	}
+
+	info := newAppInfo(PlatformTizen)
+	info.Name = tizenLabelValue(w.Names)
+	info.BundleId = w.Application.Package
+	if info.BundleId == "" {
+		info.BundleId = w.Id
+	}
+	info.Version = w.Version
+	info.Tizen.ApiVersion = w.Application.RequiredVersion
+	info.Tizen.Profile = w.Profile.Name
+	for _, p := range w.Privileges {
+		info.Tizen.Privileges = append(info.Tizen.Privileges, p.Name)
+	}
+
+	var icon string
+	if len(w.Icons) > 0 {
+		icon = w.Icons[0].Src
+	}
+	end = o.startStage(StageIcon)
+	img, err := decodeZipIcon(reader, icon)
+	end(err)
+	errs.add(StageIcon, err)
+	info.setIcon(img)
+	return info, errs.err()
+}
+
+func isTizenApplication(name string) bool {
+	switch name {
+	case "ui-application", "service-application", "watch-application", "widget-application":
+		return true
+	}
+	return false
+}
+
+// tizenLabelValue prefers the label without xml:lang, the default locale.
+func tizenLabelValue(labels []tizenLabel) string {
+	for _, l := range labels {
+		if l.Lang == "" {
+			return l.Value
+		}
+	}
+	if len(labels) > 0 {
+		return labels[0].Value
+	}
+	return ""
+}
+
+func decodeZipXML(reader *zip.Reader, name string, v interface{}) error {
+	f := findZipFile(reader.File, name)
+	if f == nil {
+		return errors.New(name + " not found")
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return xml.NewDecoder(rc).Decode(v)
+}
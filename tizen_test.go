@@ -0,0 +1,109 @@
+package appfile
+
+import (
+	"bytes"
+	"errors"
+	"image/color"
+	"image/png"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func testIcon(t *testing.T) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, uniformImage(8, 8, color.NRGBA{B: 255, A: 255})); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseTpk(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.tpk")
+	writeZip(t, name, map[string][]byte{
+		"tizen-manifest.xml": []byte(`<?xml version="1.0" encoding="utf-8"?>
+<manifest xmlns="http://tizen.org/ns/packages" api-version="2.3.2" package="org.example.watch" version="1.2.0">
+	<profile name="wearable"/>
+	<watch-application appid="org.example.watch" exec="watch" type="capp">
+		<label xml:lang="ko-kr">시계</label>
+		<label>Watch</label>
+		<icon>watch.png</icon>
+	</watch-application>
+	<privileges>
+		<privilege>http://tizen.org/privilege/alarm.set</privilege>
+	</privileges>
+</manifest>`),
+		"shared/res/watch.png": testIcon(t),
+	})
+
+	info, err := NewAppParser(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Platform != PlatformTizen || info.BundleId != "org.example.watch" || info.Version != "1.2.0" {
+		t.Errorf("got %v %v %v", info.Platform, info.BundleId, info.Version)
+	}
+	if info.Name != "Watch" {
+		t.Errorf("got %v want %v", info.Name, "Watch")
+	}
+	if info.Icon == nil || info.IconColor != "#0000ff" {
+		t.Errorf("got %v want %v", info.IconColor, "#0000ff")
+	}
+	want := &TizenInfo{
+		ApiVersion: "2.3.2",
+		Profile:    "wearable",
+		Privileges: []string{"http://tizen.org/privilege/alarm.set"},
+	}
+	if !reflect.DeepEqual(info.Tizen, want) {
+		t.Errorf("got %+v want %+v", info.Tizen, want)
+	}
+}
+
+func TestParseWgt(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.wgt")
+	writeZip(t, name, map[string][]byte{
+		"config.xml": []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<widget xmlns="http://www.w3.org/ns/widgets" xmlns:tizen="http://tizen.org/ns/widgets" id="http://example.com/web" version="2.0.1">
+	<tizen:application id="AbCdEfGhIj.Web" package="AbCdEfGhIj" required_version="2.3"/>
+	<icon src="./icon.png"/>
+	<name>Web</name>
+	<tizen:profile name="wearable"/>
+	<tizen:privilege name="http://tizen.org/privilege/internet"/>
+</widget>`),
+		"icon.png": testIcon(t),
+	})
+
+	info, err := NewAppParser(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.BundleId != "AbCdEfGhIj" || info.Version != "2.0.1" || info.Name != "Web" {
+		t.Errorf("got %v %v %v", info.BundleId, info.Version, info.Name)
+	}
+	if info.Icon == nil {
+		t.Errorf("got no icon")
+	}
+	want := &TizenInfo{
+		ApiVersion: "2.3",
+		Profile:    "wearable",
+		Privileges: []string{"http://tizen.org/privilege/internet"},
+	}
+	if !reflect.DeepEqual(info.Tizen, want) {
+		t.Errorf("got %+v want %+v", info.Tizen, want)
+	}
+}
+
+func TestParseWgtNoIcon(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.wgt")
+	writeZip(t, name, map[string][]byte{
+		"config.xml": []byte(`<widget xmlns="http://www.w3.org/ns/widgets" id="http://example.com/web" version="1.0.0"><name>Web</name></widget>`),
+	})
+
+	info, err := NewAppParser(name)
+	if !errors.Is(err, ErrNoIcon) {
+		t.Errorf("got %v want %v", err, ErrNoIcon)
+	}
+	if info == nil || info.BundleId != "http://example.com/web" {
+		t.Errorf("got %+v", info)
+	}
+}
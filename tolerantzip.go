@@ -0,0 +1,218 @@
+package appfile
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var errZipDirectory = errors.New("zip: central directory not found")
+
+// Zip record signatures and the zip64 extra field id.
+const (
+	zipLocalHeaderSig   = 0x04034b50
+	zipCentralHeaderSig = 0x02014b50
+	zipEndSig           = 0x06054b50
+	zip64LocatorSig     = 0x07064b50
+	zip64ExtraID        = 0x0001
+)
+
+// WithTolerantZip reads archives that archive/zip rejects or misreads, as
+// some packers produce them: wrong entry counts, bogus compression methods,
+// stored entries with a wrong compressed size and duplicate entries. Such
+// archives are rebuilt in memory from their central directory, which is
+// what the Android package installer reads, and each fix is reported as a
+// warning.
+func WithTolerantZip() Option {
+	return func(o *options) {
+		o.tolerantZip = true
+	}
+}
+
+// needsRepair reports whether reader has entries the parsers would get
+// wrong: duplicates, which shadow each other, and unknown methods.
+func needsRepair(reader *zip.Reader) bool {
+	seen := make(map[string]bool, len(reader.File))
+	for _, f := range reader.File {
+		if seen[f.Name] || f.Method != zip.Store && f.Method != zip.Deflate {
+			return true
+		}
+		seen[f.Name] = true
+	}
+	return false
+}
+
+type centralEntry struct {
+	name             string
+	method           uint16
+	crc32            uint32
+	compressedSize   uint64
+	uncompressedSize uint64
+	offset           uint64
+	dataOffset       uint64
+}
+
+// repairZip rebuilds the archive r from its central directory. The entry
+// count of the end record is ignored, the first of duplicate entries is
+// kept, methods other than store are read as deflate and stored entries
+// use their uncompressed size. Local headers only locate the data.
+func repairZip(r io.ReaderAt, size int64) (*zip.Reader, []string, error) {
+	entries, err := readCentralDirectory(r, size)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var warnings []string
+	for i := range entries {
+		e := &entries[i]
+		switch e.method {
+		case zip.Store:
+			e.compressedSize = e.uncompressedSize
+		case zip.Deflate:
+		default:
+			warnings = append(warnings, fmt.Sprintf("zip entry %s has compression method %d; it is read as deflate", e.name, e.method))
+			e.method = zip.Deflate
+		}
+		var local [30]byte
+		if _, err := r.ReadAt(local[:], int64(e.offset)); err == nil && le.Uint32(local[:]) == zipLocalHeaderSig {
+			e.dataOffset = e.offset + 30 + uint64(le.Uint16(local[26:])) + uint64(le.Uint16(local[28:]))
+		}
+	}
+	reader, fixes, err := rebuildZip(r, size, entries)
+	return reader, append(warnings, fixes...), err
+}
+
+// rebuildZip writes the entries of r into a new archive in memory, copying
+// their compressed data. Duplicates and entries past the end of r are
+// left out.
+func rebuildZip(r io.ReaderAt, size int64, entries []centralEntry) (*zip.Reader, []string, error) {
+	var warnings []string
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	seen := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		if seen[e.name] {
+			warnings = append(warnings, fmt.Sprintf("duplicate zip entry %s; the first one is used", e.name))
+			continue
+		}
+		seen[e.name] = true
+		if e.dataOffset+e.compressedSize > uint64(size) {
+			warnings = append(warnings, fmt.Sprintf("zip entry %s is truncated; it is skipped", e.name))
+			continue
+		}
+		fw, err := w.CreateRaw(&zip.FileHeader{
+			Name:               e.name,
+			Method:             e.method,
+			CRC32:              e.crc32,
+			CompressedSize64:   e.compressedSize,
+			UncompressedSize64: e.uncompressedSize,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, err := io.Copy(fw, io.NewSectionReader(r, int64(e.dataOffset), int64(e.compressedSize))); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, nil, err
+	}
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	return reader, warnings, err
+}
+
+// readCentralDirectory returns the entries of the central directory the
+// end record, or its zip64 variant, points to.
+func readCentralDirectory(r io.ReaderAt, size int64) ([]centralEntry, error) {
+	dirOffset, dirSize, err := findCentralDirectory(r, size)
+	if err != nil {
+		return nil, err
+	}
+	dir := make([]byte, dirSize)
+	if _, err := r.ReadAt(dir, int64(dirOffset)); err != nil {
+		return nil, err
+	}
+
+	var entries []centralEntry
+	for len(dir) >= 46 && le.Uint32(dir) == zipCentralHeaderSig {
+		nameLen, extraLen, commentLen := int(le.Uint16(dir[28:])), int(le.Uint16(dir[30:])), int(le.Uint16(dir[32:]))
+		if 46+nameLen+extraLen+commentLen > len(dir) {
+			break
+		}
+		e := centralEntry{
+			name:             string(dir[46 : 46+nameLen]),
+			method:           le.Uint16(dir[10:]),
+			crc32:            le.Uint32(dir[16:]),
+			compressedSize:   uint64(le.Uint32(dir[20:])),
+			uncompressedSize: uint64(le.Uint32(dir[24:])),
+			offset:           uint64(le.Uint32(dir[42:])),
+		}
+		e.readZip64(dir[46+nameLen : 46+nameLen+extraLen])
+		e.dataOffset = e.offset + 30 + uint64(nameLen) + uint64(extraLen)
+		entries = append(entries, e)
+		dir = dir[46+nameLen+extraLen+commentLen:]
+	}
+	if len(entries) == 0 {
+		return nil, errZipDirectory
+	}
+	return entries, nil
+}
+
+// findCentralDirectory returns the offset and size of the central
+// directory from the end record, or its zip64 variant.
+func findCentralDirectory(r io.ReaderAt, size int64) (offset, dirSize uint64, err error) {
+	// The end record is 22 bytes plus a comment of up to 64 KB.
+	tail := int64(22 + 0xffff)
+	if tail > size {
+		tail = size
+	}
+	b := make([]byte, tail)
+	if _, err := r.ReadAt(b, size-tail); err != nil && err != io.EOF {
+		return 0, 0, err
+	}
+	end := -1
+	for i := len(b) - 22; i >= 0; i-- {
+		if le.Uint32(b[i:]) == zipEndSig {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return 0, 0, errZipDirectory
+	}
+	dirSize, offset = uint64(le.Uint32(b[end+12:])), uint64(le.Uint32(b[end+16:]))
+	if offset == 0xffffffff && end >= 20 && le.Uint32(b[end-20:]) == zip64LocatorSig {
+		var rec [56]byte
+		if _, err := r.ReadAt(rec[:], int64(le.Uint64(b[end-20+8:]))); err != nil {
+			return 0, 0, err
+		}
+		dirSize, offset = le.Uint64(rec[40:]), le.Uint64(rec[48:])
+	}
+	if offset+dirSize > uint64(size) {
+		return 0, 0, errZipDirectory
+	}
+	return offset, dirSize, nil
+}
+
+// readZip64 takes the sizes and offset that do not fit 32 bits from the
+// zip64 extra field, in which they appear in this order.
+func (e *centralEntry) readZip64(extra []byte) {
+	for len(extra) >= 4 {
+		id, n := le.Uint16(extra), int(le.Uint16(extra[2:]))
+		if 4+n > len(extra) {
+			return
+		}
+		if id == zip64ExtraID {
+			field := extra[4 : 4+n]
+			for _, v := range []*uint64{&e.uncompressedSize, &e.compressedSize, &e.offset} {
+				if *v == 0xffffffff && len(field) >= 8 {
+					*v, field = le.Uint64(field), field[8:]
+				}
+			}
+			return
+		}
+		extra = extra[4+n:]
+	}
+}
@@ -0,0 +1,92 @@
+package appfile
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+)
+
+// malformedZip returns an archive with a duplicate AndroidManifest.xml, an
+// entry whose central directory method is bogus and an end record with a
+// wrong entry count.
+func malformedZip(t *testing.T) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, e := range []struct {
+		name, content string
+	}{
+		{"AndroidManifest.xml", "first"},
+		{"classes.dex", "dex\n035\x00"},
+		{"AndroidManifest.xml", "second"},
+	} {
+		f, err := w.Create(e.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(e.content))
+	}
+	w.Close()
+	b := buf.Bytes()
+
+	end := bytes.LastIndex(b, []byte("PK\x05\x06"))
+	le.PutUint16(b[end+8:], 7)
+	le.PutUint16(b[end+10:], 7)
+	dir := b[le.Uint32(b[end+16:]):]
+	second := bytes.Index(dir[4:], []byte("PK\x01\x02")) + 4
+	le.PutUint16(dir[second+10:], 99)
+	return b
+}
+
+func TestRepairZip(t *testing.T) {
+	b := malformedZip(t)
+	if _, err := zip.NewReader(bytes.NewReader(b), int64(len(b))); err == nil {
+		t.Fatalf("got no error want archive/zip to reject the archive")
+	}
+
+	reader, warnings, err := repairZip(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	if len(reader.File) != 2 || len(warnings) != 2 {
+		t.Fatalf("got %d files %v want 2 files and 2 warnings", len(reader.File), warnings)
+	}
+	for name, want := range map[string]string{"AndroidManifest.xml": "first", "classes.dex": "dex\n035\x00"} {
+		buf, err := readZipFile(findZipFile(reader.File, name))
+		if err != nil || string(buf) != want {
+			t.Errorf("got %q %v want %q", buf, err, want)
+		}
+	}
+
+	if _, _, err := repairZip(bytes.NewReader([]byte("not a zip")), 9); err == nil {
+		t.Errorf("got no error want one")
+	}
+}
+
+func TestNeedsRepair(t *testing.T) {
+	reader, err := getAppZipReader("testdata/helloworld.apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if needsRepair(reader) {
+		t.Errorf("got repair want none for a well-formed APK")
+	}
+}
+
+func TestZipFormatTolerant(t *testing.T) {
+	b := malformedZip(t)
+	var files int
+	f := zipFormat(func(_ string, reader *zip.Reader, _ *options) (*AppInfo, error) {
+		files = len(reader.File)
+		return newAppInfo(PlatformAndroid), nil
+	})
+	if _, err := f.parse("app.apk", bytes.NewReader(b), int64(len(b)), newOptions(nil)); err == nil {
+		t.Errorf("got no error want one without WithTolerantZip")
+	}
+	info, err := f.parse("app.apk", bytes.NewReader(b), int64(len(b)), newOptions([]Option{WithTolerantZip()}))
+	if err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	if files != 2 || len(info.Warnings) != 3 {
+		t.Errorf("got %d files %v want 2 files and 3 warnings", files, info.Warnings)
+	}
+}
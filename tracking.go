@@ -0,0 +1,56 @@
+package appfile
+
+import "strconv"
+
+const permissionAdID = "com.google.android.gms.permission.AD_ID"
+
+// Names of the tracking APIs in sdkScan.
+const (
+	trackingAdvertisingID = "advertising-id"
+	trackingATT           = "app-tracking-transparency"
+)
+
+// newApkTracking returns the advertising ID use of an APK, or nil. Apps
+// targeting Android 13 that read the ID without the AD_ID permission get
+// zeros, which usually means a library's permission was removed by hand.
+func newApkTracking(s sdkScan, info *AppInfo) *TrackingInfo {
+	t := new(TrackingInfo)
+	for _, p := range info.Android.Permissions {
+		t.AdIDPermission = t.AdIDPermission || p == permissionAdID
+	}
+	for _, name := range s[markerTracking] {
+		t.AdvertisingID = t.AdvertisingID || name == trackingAdvertisingID
+	}
+	if !t.AdIDPermission && !t.AdvertisingID {
+		return nil
+	}
+	if target, _ := strconv.Atoi(info.Android.TargetSdkVersion); t.AdvertisingID && !t.AdIDPermission && target >= 33 {
+		info.warn("the app reads the advertising ID without the %s permission and gets zeros on Android 13", permissionAdID)
+	}
+	return t
+}
+
+// newIpaTracking returns the tracking of an IPA, or nil. info.Tracking
+// holds the NSUserTrackingUsageDescription from Info.plist; requesting
+// tracking authorization without it crashes the app.
+func newIpaTracking(s sdkScan, info *AppInfo) *TrackingInfo {
+	t := new(TrackingInfo)
+	if info.Tracking != nil {
+		t.UsageDescription = info.Tracking.UsageDescription
+	}
+	for _, name := range s[markerTracking] {
+		switch name {
+		case trackingAdvertisingID:
+			t.AdvertisingID = true
+		case trackingATT:
+			t.ATT = true
+		}
+	}
+	if !t.AdvertisingID && !t.ATT && t.UsageDescription == "" {
+		return nil
+	}
+	if t.ATT && t.UsageDescription == "" {
+		info.warn("the app uses App Tracking Transparency without NSUserTrackingUsageDescription")
+	}
+	return t
+}
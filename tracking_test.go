@@ -0,0 +1,45 @@
+package appfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewApkTracking(t *testing.T) {
+	info := newAppInfo(PlatformAndroid)
+	info.Android.TargetSdkVersion = "33"
+	got := newApkTracking(sdkScan{markerTracking: {trackingAdvertisingID}}, info)
+	if want := (&TrackingInfo{AdvertisingID: true}); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+	if len(info.Warnings) != 1 {
+		t.Errorf("got %v want an AD_ID warning", info.Warnings)
+	}
+
+	info = newAppInfo(PlatformAndroid)
+	info.Android.Permissions = []string{permissionAdID}
+	if got := newApkTracking(sdkScan{}, info); got == nil || !got.AdIDPermission || len(info.Warnings) != 0 {
+		t.Errorf("got %+v %v want the permission and no warnings", got, info.Warnings)
+	}
+	if got := newApkTracking(sdkScan{}, newAppInfo(PlatformAndroid)); got != nil {
+		t.Errorf("got %+v want nil", got)
+	}
+}
+
+func TestNewIpaTracking(t *testing.T) {
+	info := newAppInfo(PlatformIOS)
+	got := newIpaTracking(sdkScan{markerTracking: {trackingATT}}, info)
+	if want := (&TrackingInfo{ATT: true}); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+	if len(info.Warnings) != 1 {
+		t.Errorf("got %v want a usage description warning", info.Warnings)
+	}
+
+	info = newAppInfo(PlatformIOS)
+	info.Tracking = &TrackingInfo{UsageDescription: "Ads"}
+	got = newIpaTracking(sdkScan{markerTracking: {trackingATT, trackingAdvertisingID}}, info)
+	if want := (&TrackingInfo{AdvertisingID: true, ATT: true, UsageDescription: "Ads"}); !reflect.DeepEqual(got, want) || len(info.Warnings) != 0 {
+		t.Errorf("got %+v %v want %+v", got, info.Warnings, want)
+	}
+}
@@ -0,0 +1,161 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/follyxing/appfile-info"
+)
+
+// DefaultFirebaseURL is the Firebase App Distribution API.
+const DefaultFirebaseURL = "https://firebaseappdistribution.googleapis.com"
+
+// Firebase uploads releases to Firebase App Distribution and distributes
+// them to testers.
+type Firebase struct {
+	// App is the Firebase app ID, e.g. 1:1234567890:android:0a1b2c3d4e5f.
+	App string
+	// Token returns an OAuth access token with the cloud-platform scope,
+	// e.g. from golang.org/x/oauth2/google.
+	Token func(ctx context.Context) (string, error)
+
+	Testers []string // tester emails the release is distributed to
+	Groups  []string // tester group aliases the release is distributed to
+	// ReleaseNotes, if set, returns the release notes of info.
+	ReleaseNotes func(info *appfile.AppInfo) string
+
+	BaseURL      string        // DefaultFirebaseURL if empty
+	HTTPClient   *http.Client  // http.DefaultClient if nil
+	PollInterval time.Duration // between upload status checks, 5s if 0
+}
+
+type firebaseRelease struct {
+	Name               string `json:"name"`
+	FirebaseConsoleURI string `json:"firebaseConsoleUri"`
+}
+
+type firebaseOperation struct {
+	Name     string `json:"name"`
+	Done     bool   `json:"done"`
+	Response struct {
+		Release firebaseRelease `json:"release"`
+	} `json:"response"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (fb *Firebase) Upload(ctx context.Context, name string, info *appfile.AppInfo) (*Release, error) {
+	// The project number is the second field of the app ID.
+	fields := strings.Split(fb.App, ":")
+	if len(fields) != 4 {
+		return nil, fmt.Errorf("firebase: malformed app ID %q", fb.App)
+	}
+	token, err := fb.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var op firebaseOperation
+	path := "/upload/v1/projects/" + fields[1] + "/apps/" + url.PathEscape(fb.App) + "/releases:upload"
+	header := http.Header{
+		"X-Goog-Upload-Protocol":  {"raw"},
+		"X-Goog-Upload-File-Name": {filepath.Base(name)},
+	}
+	if err := fb.do(ctx, token, http.MethodPost, path, header, f, &op); err != nil {
+		return nil, err
+	}
+
+	interval := fb.PollInterval
+	if interval == 0 {
+		interval = 5 * time.Second
+	}
+	for !op.Done {
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if err := fb.do(ctx, token, http.MethodGet, "/v1/"+op.Name, nil, nil, &op); err != nil {
+			return nil, err
+		}
+	}
+	if op.Error != nil {
+		return nil, fmt.Errorf("firebase: upload %s: %s", name, op.Error.Message)
+	}
+	release := op.Response.Release
+
+	if fb.ReleaseNotes != nil {
+		if notes := fb.ReleaseNotes(info); notes != "" {
+			body, _ := json.Marshal(map[string]interface{}{
+				"name":         release.Name,
+				"releaseNotes": map[string]string{"text": notes},
+			})
+			path := "/v1/" + release.Name + "?updateMask=release_notes.text"
+			if err := fb.do(ctx, token, http.MethodPatch, path, nil, bytes.NewReader(body), nil); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(fb.Testers)+len(fb.Groups) > 0 {
+		body, _ := json.Marshal(map[string][]string{"testerEmails": fb.Testers, "groupAliases": fb.Groups})
+		if err := fb.do(ctx, token, http.MethodPost, "/v1/"+release.Name+":distribute", nil, bytes.NewReader(body), nil); err != nil {
+			return nil, err
+		}
+	}
+	return &Release{ID: release.Name, URL: release.FirebaseConsoleURI}, nil
+}
+
+func (fb *Firebase) do(ctx context.Context, token, method, path string, header http.Header, body io.Reader, v interface{}) error {
+	base := fb.BaseURL
+	if base == "" {
+		base = DefaultFirebaseURL
+	}
+	req, err := http.NewRequest(method, base+path, body)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	for k, vs := range header {
+		req.Header[k] = vs
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if header == nil && body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := fb.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		var e firebaseOperation
+		if json.NewDecoder(resp.Body).Decode(&e) == nil && e.Error != nil {
+			return fmt.Errorf("firebase: %s %s: %s", method, path, e.Error.Message)
+		}
+		return fmt.Errorf("firebase: %s %s: unexpected status %s", method, path, resp.Status)
+	}
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
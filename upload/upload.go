@@ -0,0 +1,119 @@
+// Package upload publishes parsed artifacts to distribution services such
+// as Firebase App Distribution or an in-house portal.
+package upload
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/follyxing/appfile-info"
+)
+
+// Release is an uploaded artifact.
+type Release struct {
+	ID  string `json:"id"`
+	URL string `json:"url,omitempty"` // where the release is shown, e.g. in a console
+}
+
+// Uploader uploads the artifact name, parsed as info.
+type Uploader interface {
+	Upload(ctx context.Context, name string, info *appfile.AppInfo) (*Release, error)
+}
+
+// ParseAndUpload parses name and uploads it with u. An artifact that did
+// not parse cleanly is not uploaded; appfile.WithMode(appfile.ModeLenient)
+// uploads it with the failed stages as warnings.
+func ParseAndUpload(ctx context.Context, name string, u Uploader, opts ...appfile.Option) (*appfile.AppInfo, *Release, error) {
+	opts = append([]appfile.Option{appfile.WithContext(ctx)}, opts...)
+	info, err := appfile.NewAppParser(name, opts...)
+	if err != nil {
+		return info, nil, err
+	}
+	r, err := u.Upload(ctx, name, info)
+	return info, r, err
+}
+
+// Portal POSTs artifacts to an HTTP endpoint as multipart/form-data, with
+// the AppInfo as JSON in the "info" field and the artifact in "file". A
+// JSON response body is read as the Release.
+type Portal struct {
+	url    string
+	client *http.Client
+	// Header is added to every request, e.g. for an Authorization token.
+	Header http.Header
+}
+
+// NewPortal returns a Portal using client, or http.DefaultClient if nil.
+func NewPortal(url string, client *http.Client) *Portal {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Portal{url: url, client: client, Header: make(http.Header)}
+}
+
+func (p *Portal) Upload(ctx context.Context, name string, info *appfile.AppInfo) (*Release, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// The artifact is streamed rather than buffered, as it may be large.
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writeForm(mw, name, f, info))
+	}()
+
+	req, err := http.NewRequest(http.MethodPost, p.url, pr)
+	if err != nil {
+		pr.Close()
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	for k, v := range p.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("portal %s: unexpected status %s", p.url, resp.Status)
+	}
+	r := new(Release)
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		if err := json.NewDecoder(resp.Body).Decode(r); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func writeForm(mw *multipart.Writer, name string, f io.Reader, info *appfile.AppInfo) error {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	if err := mw.WriteField("info", string(data)); err != nil {
+		return err
+	}
+	fw, err := mw.CreateFormFile("file", filepath.Base(name))
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(fw, f); err != nil {
+		return err
+	}
+	return mw.Close()
+}
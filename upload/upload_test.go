@@ -0,0 +1,127 @@
+package upload
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/follyxing/appfile-info"
+	"github.com/follyxing/appfile-info/fixture"
+)
+
+func writeAPK(t *testing.T) (string, []byte) {
+	data, err := (&fixture.APK{Package: "com.example.app", VersionCode: 7, VersionName: "1.7"}).Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := filepath.Join(t.TempDir(), "app.apk")
+	if err := ioutil.WriteFile(name, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return name, data
+}
+
+func TestParseAndUploadPortal(t *testing.T) {
+	name, data := writeAPK(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var info appfile.AppInfo
+		if err := json.Unmarshal([]byte(r.FormValue("info")), &info); err != nil || info.BundleId != "com.example.app" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f, fh, err := r.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		b, _ := ioutil.ReadAll(f)
+		if fh.Filename != "app.apk" || string(b) != string(data) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Write([]byte(`{"id":"r-7","url":"https://portal.example.com/r-7"}`))
+	}))
+	defer srv.Close()
+
+	p := NewPortal(srv.URL, nil)
+	p.Header.Set("Authorization", "Bearer s3cret")
+	info, r, err := ParseAndUpload(context.Background(), name, p, appfile.WithMode(appfile.ModeLenient))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (&Release{ID: "r-7", URL: "https://portal.example.com/r-7"}); !reflect.DeepEqual(r, want) {
+		t.Errorf("got %+v want %+v", r, want)
+	}
+	if info.Version != "1.7" {
+		t.Errorf("got %v want %v", info.Version, "1.7")
+	}
+}
+
+func TestFirebase(t *testing.T) {
+	name, data := writeAPK(t)
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Header.Get("Authorization") != "Bearer t0k3n":
+			w.WriteHeader(http.StatusUnauthorized)
+		case strings.HasSuffix(r.URL.Path, "/releases:upload"):
+			if string(body) != string(data) || r.Header.Get("X-Goog-Upload-File-Name") != "app.apk" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`{"name":"projects/123/apps/1:123:android:abc/releases/-/operations/op1"}`))
+		case strings.HasSuffix(r.URL.Path, "/operations/op1"):
+			w.Write([]byte(`{"done":true,"response":{"release":{"name":"projects/123/apps/1:123:android:abc/releases/rel1","firebaseConsoleUri":"https://console.firebase.google.com/rel1"}}}`))
+		case r.Method == http.MethodPatch:
+			if !strings.Contains(string(body), `"text":"Build 7"`) {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		case strings.HasSuffix(r.URL.Path, ":distribute"):
+			if string(body) != `{"groupAliases":["qa"],"testerEmails":null}` {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	fb := &Firebase{
+		App:          "1:123:android:abc",
+		Token:        func(context.Context) (string, error) { return "t0k3n", nil },
+		Groups:       []string{"qa"},
+		ReleaseNotes: func(info *appfile.AppInfo) string { return "Build " + info.Build },
+		BaseURL:      srv.URL,
+		PollInterval: 1,
+	}
+	r, err := fb.Upload(context.Background(), name, &appfile.AppInfo{Build: "7"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Release{ID: "projects/123/apps/1:123:android:abc/releases/rel1", URL: "https://console.firebase.google.com/rel1"}
+	if !reflect.DeepEqual(r, want) {
+		t.Errorf("got %+v want %+v", r, want)
+	}
+	wantCalls := []string{
+		"POST /upload/v1/projects/123/apps/1:123:android:abc/releases:upload",
+		"GET /v1/projects/123/apps/1:123:android:abc/releases/-/operations/op1",
+		"PATCH /v1/projects/123/apps/1:123:android:abc/releases/rel1",
+		"POST /v1/projects/123/apps/1:123:android:abc/releases/rel1:distribute",
+	}
+	if !reflect.DeepEqual(calls, wantCalls) {
+		t.Errorf("got %v want %v", calls, wantCalls)
+	}
+}
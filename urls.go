@@ -0,0 +1,112 @@
+package appfile
+
+import (
+	"archive/zip"
+	"bytes"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var reURLHost = regexp.MustCompile(`https?://([A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)+)`)
+
+// Hosts of XML namespaces and DTDs rather than endpoints.
+var ignoredURLHosts = map[string]bool{
+	"schemas.android.com": true,
+	"www.w3.org":          true,
+	"www.apple.com":       true,
+	"ns.adobe.com":        true,
+}
+
+// WithURLScan makes the parse collect the hosts of http and https URLs in
+// the app's strings into AppInfo.Hosts: string resources and dex files of
+// APKs, Info.plist and the main executable of IPAs. It reads every dex
+// file or the whole executable, so it is off by default.
+func WithURLScan() Option {
+	return func(o *options) {
+		o.scanURLs = true
+	}
+}
+
+type hostSet map[string]bool
+
+func (s hostSet) scan(b []byte) {
+	for _, m := range reURLHost.FindAllSubmatch(b, -1) {
+		host := strings.ToLower(string(m[1]))
+		if !ignoredURLHosts[host] {
+			s[host] = true
+		}
+	}
+}
+
+func (s hostSet) sorted() []string {
+	if len(s) == 0 {
+		return nil
+	}
+	hosts := make([]string, 0, len(s))
+	for h := range s {
+		hosts = append(hosts, h)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+func scanApkHosts(files []*zip.File, res *apkResources) ([]string, error) {
+	s := make(hostSet)
+	if t := res.load(); t != nil {
+		for _, str := range t.strings {
+			s.scan([]byte(str))
+		}
+	}
+	for _, f := range files {
+		if !strings.HasSuffix(f.Name, ".dex") {
+			continue
+		}
+		buf, err := readZipFile(f)
+		if err != nil {
+			return nil, err
+		}
+		s.scan(buf)
+	}
+	return s.sorted(), nil
+}
+
+// scanIpaHosts scans Info.plist and the Mach-O files directly in the .app
+// directory, which is where the main executable lives.
+func scanIpaHosts(files []*zip.File) ([]string, error) {
+	s := make(hostSet)
+	for _, f := range files {
+		dir, name := path.Split(f.Name)
+		if !strings.HasPrefix(dir, "Payload/") || strings.Count(dir, "/") != 2 || !strings.HasSuffix(dir, ".app/") {
+			continue
+		}
+		if name != "Info.plist" && path.Ext(name) != "" {
+			continue
+		}
+		buf, err := readZipFile(f)
+		if err != nil {
+			return nil, err
+		}
+		if name == "Info.plist" || isMachO(buf) {
+			s.scan(buf)
+		}
+	}
+	return s.sorted(), nil
+}
+
+func isMachO(b []byte) bool {
+	if len(b) < 4 {
+		return false
+	}
+	for _, magic := range [][]byte{
+		{0xfe, 0xed, 0xfa, 0xce}, {0xce, 0xfa, 0xed, 0xfe},
+		{0xfe, 0xed, 0xfa, 0xcf}, {0xcf, 0xfa, 0xed, 0xfe},
+		{0xca, 0xfe, 0xba, 0xbe},
+	} {
+		if bytes.HasPrefix(b, magic) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package appfile
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestScanIpaHosts(t *testing.T) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"Payload/App.app/Info.plist":         `<string>https://api.example.com/v1</string><!DOCTYPE plist "http://www.apple.com/DTDs/PropertyList-1.0.dtd">`,
+		"Payload/App.app/App":                "\xcf\xfa\xed\xfe\x00https://Staging.Example.com:8443/login\x00http://localhost",
+		"Payload/App.app/README":             "https://not-a-binary.example.com",
+		"Payload/App.app/Frameworks/F.plist": "https://framework.example.com",
+	} {
+		f, _ := w.Create(name)
+		f.Write([]byte(content))
+	}
+	w.Close()
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := scanIpaHosts(reader.File)
+	want := []string{"api.example.com", "staging.example.com"}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, %v want %v", got, err, want)
+	}
+}
+
+func TestScanApkHosts(t *testing.T) {
+	reader, err := getAppZipReader("testdata/helloworld.apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := scanApkHosts(reader.File, newApkResources(reader.File))
+	if err != nil || got != nil {
+		t.Errorf("got %v, %v want nil", got, err)
+	}
+}
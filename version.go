@@ -0,0 +1,107 @@
+package appfile
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CompareVersions compares two version strings and returns -1, 0 or 1.
+// Versions are dot separated with an optional "v" prefix. Numeric parts
+// compare numerically, other parts lexically, and missing parts count as
+// zero, so "1.2" == "1.2.0" < "1.10". A semver pre-release ("1.0-beta.2")
+// sorts before its release and build metadata ("+sha.abc") is ignored.
+func CompareVersions(a, b string) int {
+	a, aPre := splitVersion(a)
+	b, bPre := splitVersion(b)
+
+	if c := compareParts(strings.Split(a, "."), strings.Split(b, "."), true); c != 0 {
+		return c
+	}
+
+	switch {
+	case aPre == "" && bPre == "":
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	}
+	return compareParts(strings.Split(aPre, "."), strings.Split(bPre, "."), false)
+}
+
+func splitVersion(v string) (core, pre string) {
+	v = strings.TrimSpace(v)
+	v = strings.TrimPrefix(strings.TrimPrefix(v, "v"), "V")
+	if i := strings.IndexByte(v, '+'); i >= 0 {
+		v = v[:i]
+	}
+	if i := strings.IndexByte(v, '-'); i >= 0 {
+		return v[:i], v[i+1:]
+	}
+	return v, ""
+}
+
+// compareParts compares dot separated identifiers. With padZero, missing
+// or empty identifiers are treated as "0"; otherwise the shorter list sorts
+// first, as semver requires for pre-release identifiers.
+func compareParts(a, b []string, padZero bool) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y string
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if padZero {
+			if x == "" {
+				x = "0"
+			}
+			if y == "" {
+				y = "0"
+			}
+		} else if i >= len(a) {
+			return -1
+		} else if i >= len(b) {
+			return 1
+		}
+		if c := compareIdentifier(x, y); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func compareIdentifier(x, y string) int {
+	xn, xerr := strconv.ParseUint(x, 10, 64)
+	yn, yerr := strconv.ParseUint(y, 10, 64)
+	switch {
+	case xerr == nil && yerr == nil:
+		if xn < yn {
+			return -1
+		}
+		if xn > yn {
+			return 1
+		}
+		return 0
+	case xerr == nil:
+		// numeric identifiers sort before alphanumeric ones
+		return -1
+	case yerr == nil:
+		return 1
+	}
+	return strings.Compare(x, y)
+}
+
+// NewerThan reports whether info is a later build than other. Android
+// builds are ordered by versionCode alone, since that is what the platform
+// uses to allow upgrades; other builds by version and then build number.
+func (info *AppInfo) NewerThan(other *AppInfo) bool {
+	if info.Android != nil && other.Android != nil {
+		return CompareVersions(info.Build, other.Build) > 0
+	}
+	if c := CompareVersions(info.Version, other.Version); c != 0 {
+		return c > 0
+	}
+	return CompareVersions(info.Build, other.Build) > 0
+}
@@ -0,0 +1,63 @@
+package appfile
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.2", "1.2.0", 0},
+		{"1.2", "1.10", -1},
+		{"v2.0.0", "1.9.9", 1},
+		{"10", "9", 1},
+		{"1.0.0-beta", "1.0.0", -1},
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+		{"1.0.0-beta.2", "1.0.0-beta.11", -1},
+		{"1.0.0-rc.1", "1.0.0-beta.11", 1},
+		{"1.0.0+build.1", "1.0.0+build.2", 0},
+		{"1.0a", "1.0b", -1},
+		{"2024.10.3", "2024.9.30", 1},
+		{"", "0", 0},
+	}
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("%v vs %v: got %v want %v", tt.a, tt.b, got, tt.want)
+		}
+		if got := CompareVersions(tt.b, tt.a); got != -tt.want {
+			t.Errorf("%v vs %v: got %v want %v", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+func TestNewerThan(t *testing.T) {
+	apk := func(version, code string) *AppInfo {
+		info := newAppInfo(PlatformAndroid)
+		info.Version, info.Build = version, code
+		return info
+	}
+	ipa := func(version, build string) *AppInfo {
+		info := newAppInfo(PlatformIOS)
+		info.Version, info.Build = version, build
+		return info
+	}
+
+	tests := []struct {
+		a, b *AppInfo
+		want bool
+	}{
+		{apk("1.0", "10"), apk("1.1", "9"), true},
+		{apk("1.1", "9"), apk("1.0", "10"), false},
+		{apk("1.0", "10"), apk("1.0", "10"), false},
+		{ipa("1.1", "1"), ipa("1.0", "20"), true},
+		{ipa("1.0", "1.0.10"), ipa("1.0", "1.0.9"), true},
+		{ipa("1.0", "3"), ipa("1.0", "3"), false},
+	}
+	for _, tt := range tests {
+		if got := tt.a.NewerThan(tt.b); got != tt.want {
+			t.Errorf("%v/%v vs %v/%v: got %v want %v", tt.a.Version, tt.a.Build, tt.b.Version, tt.b.Build, got, tt.want)
+		}
+	}
+}
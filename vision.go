@@ -0,0 +1,27 @@
+package appfile
+
+// Scene session roles of UIApplicationSceneManifest that only visionOS
+// has.
+const (
+	sceneRoleImmersiveSpace = "UISceneSessionRoleImmersiveSpaceApplication"
+	sceneRoleVolumetric     = "UIWindowSceneSessionRoleVolumetricApplication"
+)
+
+type iosSceneManifest struct {
+	UIApplicationPreferredDefaultSceneSessionRole string `plist:"UIApplicationPreferredDefaultSceneSessionRole"`
+	// UISceneConfigurations maps scene roles to their configurations.
+	UISceneConfigurations map[string]interface{} `plist:"UISceneConfigurations"`
+}
+
+// checkVision flags visionOS builds by their device families and scene
+// roles; iPhone and iPad apps run on Apple Vision Pro without either.
+func checkVision(i *IosInfo) {
+	for _, f := range i.DeviceFamilies {
+		i.VisionOS = i.VisionOS || f == DeviceVision
+	}
+	switch i.DefaultSceneRole {
+	case sceneRoleImmersiveSpace, sceneRoleVolumetric:
+		i.VisionOS = true
+	}
+	i.VisionOS = i.VisionOS || i.ImmersiveSpace
+}
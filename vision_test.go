@@ -0,0 +1,37 @@
+package appfile
+
+import (
+	"testing"
+
+	"github.com/follyxing/appfile-info/fixture"
+)
+
+func TestVisionOS(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		plist     map[string]interface{}
+		vision    bool
+		immersive bool
+	}{
+		{"ipad app", map[string]interface{}{"UIDeviceFamily": []interface{}{1, 2}}, false, false},
+		{"device family 7", map[string]interface{}{"UIDeviceFamily": []interface{}{7}}, true, false},
+		{"xros", map[string]interface{}{"DTPlatformName": "xros"}, true, false},
+		{"volumetric", map[string]interface{}{"UIApplicationSceneManifest": map[string]interface{}{
+			"UIApplicationPreferredDefaultSceneSessionRole": sceneRoleVolumetric,
+		}}, true, false},
+		{"immersive space", map[string]interface{}{"UIApplicationSceneManifest": map[string]interface{}{
+			"UISceneConfigurations": map[string]interface{}{
+				sceneRoleImmersiveSpace: []interface{}{map[string]interface{}{"UISceneConfigurationName": "Space"}},
+			},
+		}}, true, true},
+	} {
+		info, err := decodeInfoPlist(fixture.Plist(tt.plist))
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkVision(info.Ios)
+		if info.Ios.VisionOS != tt.vision || info.Ios.ImmersiveSpace != tt.immersive {
+			t.Errorf("%s: got %v %v want %v %v", tt.name, info.Ios.VisionOS, info.Ios.ImmersiveSpace, tt.vision, tt.immersive)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+// Package watch parses app files as they are dropped into directories and
+// publishes the results to a sink.
+package watch
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/follyxing/appfile-info"
+	"github.com/follyxing/appfile-info/sink"
+	"github.com/fsnotify/fsnotify"
+)
+
+const defaultSettle = 2 * time.Second
+
+// Watcher watches directories for new artifacts. A file is parsed once no
+// write to it has been seen for Settle, so artifacts still being copied
+// into the directory are not picked up half-written.
+type Watcher struct {
+	Sink    sink.Sink
+	Options []appfile.Option
+	Settle  time.Duration
+	// Existing also ingests artifacts already present when Run starts.
+	Existing bool
+	Logger   *log.Logger
+}
+
+// isCandidate reports whether name may be an artifact. Containers may
+// still be written to, so they are looked into once settled.
+func isCandidate(name string) bool {
+	return (appfile.IsSupported(name) || appfile.IsContainer(name)) && !strings.HasPrefix(filepath.Base(name), ".")
+}
+
+// isAppFile reports whether the settled file name is an artifact.
+func isAppFile(name string) bool {
+	return isCandidate(name) && (!appfile.IsContainer(name) || appfile.ContainsApp(name))
+}
+
+// Run watches dirs until ctx is done.
+func (w *Watcher) Run(ctx context.Context, dirs ...string) error {
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer fw.Close()
+
+	for _, dir := range dirs {
+		if err := fw.Add(dir); err != nil {
+			return err
+		}
+	}
+
+	settle := w.Settle
+	if settle <= 0 {
+		settle = defaultSettle
+	}
+
+	d := newDebouncer(settle, ctx.Done())
+	defer d.stop()
+
+	if w.Existing {
+		for _, dir := range dirs {
+			entries, err := ioutil.ReadDir(dir)
+			if err != nil {
+				return err
+			}
+			for _, e := range entries {
+				if !e.IsDir() && isCandidate(e.Name()) {
+					d.schedule(filepath.Join(dir, e.Name()))
+				}
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ev, ok := <-fw.Events:
+			if !ok {
+				return nil
+			}
+			if !isCandidate(ev.Name) {
+				continue
+			}
+			if ev.Op&(fsnotify.Create|fsnotify.Write) != 0 {
+				d.schedule(ev.Name)
+			} else if ev.Op&(fsnotify.Remove|fsnotify.Rename) != 0 {
+				d.cancel(ev.Name)
+			}
+		case err, ok := <-fw.Errors:
+			if !ok {
+				return nil
+			}
+			w.logf("watch: %v", err)
+		case f := <-d.ready:
+			if d.take(f) && isAppFile(f.name) {
+				w.ingest(ctx, f.name)
+			}
+		}
+	}
+}
+
+// debouncer fires a path once it has been quiet for settle. It is used
+// from the Run loop only. A timer may have fired while its path was
+// scheduled again or removed, so each fire carries the generation it was
+// armed with and only the latest one of a pending path is taken.
+type debouncer struct {
+	settle  time.Duration
+	done    <-chan struct{}
+	ready   chan fire
+	gen     uint64
+	pending map[string]pendingFire
+}
+
+type fire struct {
+	name string
+	gen  uint64
+}
+
+type pendingFire struct {
+	timer *time.Timer
+	gen   uint64
+}
+
+func newDebouncer(settle time.Duration, done <-chan struct{}) *debouncer {
+	return &debouncer{
+		settle:  settle,
+		done:    done,
+		ready:   make(chan fire),
+		pending: make(map[string]pendingFire),
+	}
+}
+
+func (d *debouncer) schedule(name string) {
+	d.cancel(name)
+	d.gen++
+	f := fire{name, d.gen}
+	d.pending[name] = pendingFire{time.AfterFunc(d.settle, func() {
+		select {
+		case d.ready <- f:
+		case <-d.done:
+		}
+	}), f.gen}
+}
+
+func (d *debouncer) cancel(name string) {
+	if p, ok := d.pending[name]; ok {
+		p.timer.Stop()
+		delete(d.pending, name)
+	}
+}
+
+// take reports whether f is the latest fire of a pending path, which it
+// then no longer is.
+func (d *debouncer) take(f fire) bool {
+	p, ok := d.pending[f.name]
+	if !ok || p.gen != f.gen {
+		return false
+	}
+	delete(d.pending, f.name)
+	return true
+}
+
+func (d *debouncer) stop() {
+	for _, p := range d.pending {
+		p.timer.Stop()
+	}
+}
+
+func (w *Watcher) ingest(ctx context.Context, name string) {
+	opts := append([]appfile.Option{appfile.WithContext(ctx)}, w.Options...)
+	info, err := appfile.NewAppParser(name, opts...)
+	if info == nil {
+		w.logf("watch: %s: %v", name, err)
+		return
+	}
+	if err != nil {
+		w.logf("watch: %s: %v", name, err)
+	}
+	if err := w.Sink.Publish(ctx, sink.Event{Path: name, Info: info}); err != nil {
+		w.logf("watch: publish %s: %v", name, err)
+	}
+}
+
+func (w *Watcher) logf(format string, v ...interface{}) {
+	if w.Logger != nil {
+		w.Logger.Printf(format, v...)
+		return
+	}
+	log.Printf(format, v...)
+}
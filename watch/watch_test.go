@@ -0,0 +1,51 @@
+package watch
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIsAppFile(t *testing.T) {
+	tests := map[string]bool{
+		"drop/app.apk":         true,
+		"drop/App.IPA":         true,
+		"drop/.app.apk":        false,
+		"drop/app.apk.partial": false,
+		"drop/notes.txt":       false,
+		"drop/logs.zip":        false,
+	}
+	for name, want := range tests {
+		if got := isAppFile(name); got != want {
+			t.Errorf("%v: got %v want %v", name, got, want)
+		}
+	}
+	if !isCandidate("drop/build.zip") {
+		t.Errorf("got %v want %v", false, true)
+	}
+}
+
+func TestDebouncer(t *testing.T) {
+	done := make(chan struct{})
+	defer close(done)
+	d := newDebouncer(time.Millisecond, done)
+	defer d.stop()
+
+	// A fire blocked on ready while the path is written again is stale.
+	d.schedule("drop/app.apk")
+	stale := <-d.ready
+	d.schedule("drop/app.apk")
+	if d.take(stale) {
+		t.Errorf("got %v want a stale fire", stale)
+	}
+	if f := <-d.ready; !d.take(f) || d.take(f) {
+		t.Errorf("got %v want it taken once", f)
+	}
+
+	// A removed file is not ingested by a timer that fired before.
+	d.schedule("drop/gone.apk")
+	time.Sleep(10 * time.Millisecond)
+	d.cancel("drop/gone.apk")
+	if f := <-d.ready; d.take(f) {
+		t.Errorf("got %v want the fire of a removed file dropped", f)
+	}
+}
@@ -0,0 +1,43 @@
+package appfile
+
+const (
+	metaWearStandalone = "com.google.android.wearable.standalone"
+	libraryWearable    = "com.google.android.wearable"
+)
+
+type androidUsesLibrary struct {
+	Name     string `xml:"name,attr"`
+	Required string `xml:"required,attr"`
+}
+
+// newWearInfo returns the Wear OS requirements of m, or nil for apps that
+// are not for watches. Google Play treats watch apps without the
+// standalone meta-data as needing a phone app.
+func newWearInfo(m *androidManifest, info *AppInfo) *WearInfo {
+	w := new(WearInfo)
+	for _, f := range m.UsesFeatures {
+		if f.Name == featureWatch {
+			w.WatchFeature = true
+			w.WatchOnly = f.Required != "false"
+		}
+	}
+	for _, l := range m.Application.UsesLibraries {
+		if l.Name == libraryWearable {
+			w.WearableLibrary = l.Required != "false"
+		}
+	}
+	meta := metaDataNamed(m.Application.MetaData, metaWearStandalone)
+	if !w.WatchFeature && !w.WearableLibrary && meta == nil {
+		return nil
+	}
+
+	w.Standalone = meta != nil && meta.Value == "true"
+	w.RequiresCompanion = !w.Standalone
+	switch {
+	case meta == nil:
+		info.warn("the watch app lacks %s meta-data; Google Play requires a phone app to install it", metaWearStandalone)
+	case !w.WatchFeature:
+		info.warn("%s is set but the app does not declare %s, so it is not offered to watches", metaWearStandalone, featureWatch)
+	}
+	return w
+}
@@ -0,0 +1,28 @@
+package appfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewWearInfo(t *testing.T) {
+	m := new(androidManifest)
+	m.UsesFeatures = []androidUsesFeature{{Name: featureWatch}}
+	m.Application.MetaData = []androidMetaData{{Name: metaWearStandalone, Value: "true"}}
+	m.Application.UsesLibraries = []androidUsesLibrary{{Name: libraryWearable, Required: "false"}}
+	info := newAppInfo(PlatformAndroid)
+	want := &WearInfo{Standalone: true, WatchFeature: true, WatchOnly: true}
+	if got := newWearInfo(m, info); !reflect.DeepEqual(got, want) || len(info.Warnings) != 0 {
+		t.Errorf("got %+v %v want %+v", got, info.Warnings, want)
+	}
+
+	m.Application.MetaData = nil
+	got := newWearInfo(m, info)
+	if got.Standalone || !got.RequiresCompanion || len(info.Warnings) != 1 {
+		t.Errorf("got %+v %v want a companion warning", got, info.Warnings)
+	}
+
+	if got := newWearInfo(new(androidManifest), info); got != nil {
+		t.Errorf("got %+v want nil", got)
+	}
+}
@@ -0,0 +1,292 @@
+package appfile
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"image"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	crxExt         = ".crx"
+	webManifestExt = ".webmanifest"
+)
+
+var errCrx = errors.New("malformed crx")
+
+// extensionManifest is the manifest.json of a Chrome or Edge extension.
+type extensionManifest struct {
+	ManifestVersion int               `json:"manifest_version"`
+	Name            string            `json:"name"`
+	Version         string            `json:"version"`
+	VersionName     string            `json:"version_name"`
+	DefaultLocale   string            `json:"default_locale"`
+	Icons           map[string]string `json:"icons"`
+	Permissions     []interface{}     `json:"permissions"`
+	HostPermissions []string          `json:"host_permissions"`
+}
+
+// webAppManifest is the manifest of a progressive web app.
+type webAppManifest struct {
+	Id         string `json:"id"`
+	Name       string `json:"name"`
+	ShortName  string `json:"short_name"`
+	StartURL   string `json:"start_url"`
+	Scope      string `json:"scope"`
+	Display    string `json:"display"`
+	ThemeColor string `json:"theme_color"`
+	Icons      []struct {
+		Src   string `json:"src"`
+		Sizes string `json:"sizes"`
+		Type  string `json:"type"`
+	} `json:"icons"`
+}
+
+func parseCrx(_ string, r io.ReaderAt, size int64, o *options) (*AppInfo, error) {
+	var errs stageErrors
+	end := o.startStage(StageZipRead)
+	id, offset, err := readCrxHeader(r, size)
+	var reader *zip.Reader
+	if err == nil {
+		reader, err = zip.NewReader(io.NewSectionReader(r, offset, size-offset), size-offset)
+	}
+	end(err)
+	if err != nil {
+		errs.add(StageZipRead, err)
+		return nil, errs.err()
+	}
+
+	end = o.startStage(StageManifest)
+	m := new(extensionManifest)
+	err = decodeZipJSON(reader, "manifest.json", m)
+	end(err)
+	if err != nil {
+		errs.add(StageManifest, err)
+		return nil, errs.err()
+	}
+
+	info := newAppInfo(PlatformWeb)
+	info.Name = extensionMessage(reader, m.DefaultLocale, m.Name)
+	info.BundleId = id
+	info.Version = m.Version
+	if m.VersionName != "" {
+		info.Version = m.VersionName
+	}
+	info.Build = m.Version
+	info.Web.Extension = true
+	info.Web.ManifestVersion = m.ManifestVersion
+	for _, p := range m.Permissions {
+		// Manifest V2 allows objects such as {"fileSystem": [...]}.
+		if s, ok := p.(string); ok {
+			info.Web.Permissions = append(info.Web.Permissions, s)
+		}
+	}
+	info.Web.Permissions = append(info.Web.Permissions, m.HostPermissions...)
+
+	var icon string
+	best := 0
+	for s, src := range m.Icons {
+		if n, _ := strconv.Atoi(s); n > best {
+			icon, best = src, n
+		}
+	}
+	end = o.startStage(StageIcon)
+	img, err := decodeZipIcon(reader, icon)
+	end(err)
+	errs.add(StageIcon, err)
+	info.setIcon(img)
+	return info, errs.err()
+}
+
+// readCrxHeader returns the extension id and the offset of the zip archive
+// of a CRX2 or CRX3 file. CRX2 ids are derived from the public key, CRX3
+// files carry theirs in the signed header data.
+func readCrxHeader(r io.ReaderAt, size int64) (string, int64, error) {
+	var h [16]byte
+	if _, err := r.ReadAt(h[:], 0); err != nil || string(h[:4]) != "Cr24" {
+		return "", 0, errCrx
+	}
+
+	switch binary.LittleEndian.Uint32(h[4:]) {
+	case 2:
+		keyLen := int64(binary.LittleEndian.Uint32(h[8:]))
+		sigLen := int64(binary.LittleEndian.Uint32(h[12:]))
+		if 16+keyLen+sigLen > size {
+			return "", 0, errCrx
+		}
+		key := make([]byte, keyLen)
+		if _, err := r.ReadAt(key, 16); err != nil {
+			return "", 0, errCrx
+		}
+		sum := sha256.Sum256(key)
+		return extensionId(sum[:16]), 16 + keyLen + sigLen, nil
+	case 3:
+		headerLen := int64(binary.LittleEndian.Uint32(h[8:]))
+		if 12+headerLen > size {
+			return "", 0, errCrx
+		}
+		header := make([]byte, headerLen)
+		if _, err := r.ReadAt(header, 12); err != nil {
+			return "", 0, errCrx
+		}
+		// CrxFileHeader: signed_header_data = 10000
+		// SignedData: crx_id = 1
+		var id string
+		err := protoFields(header, func(num int, v []byte) error {
+			if num != 10000 {
+				return nil
+			}
+			return protoFields(v, func(num int, v []byte) error {
+				if num == 1 {
+					id = extensionId(v)
+				}
+				return nil
+			})
+		})
+		if err != nil {
+			return "", 0, err
+		}
+		return id, 12 + headerLen, nil
+	}
+	return "", 0, errCrx
+}
+
+// extensionId writes the hex digits of b as the letters a to p, the way
+// Chrome spells extension ids.
+func extensionId(b []byte) string {
+	id := make([]byte, 0, 2*len(b))
+	for _, c := range b {
+		id = append(id, 'a'+c>>4, 'a'+c&0xf)
+	}
+	return string(id)
+}
+
+// extensionMessage resolves __MSG_name__ placeholders from the messages of
+// the default locale, whose names are case-insensitive.
+func extensionMessage(reader *zip.Reader, locale, s string) string {
+	if !strings.HasPrefix(s, "__MSG_") || !strings.HasSuffix(s, "__") || locale == "" {
+		return s
+	}
+	var messages map[string]struct {
+		Message string `json:"message"`
+	}
+	if err := decodeZipJSON(reader, "_locales/"+locale+"/messages.json", &messages); err != nil {
+		return s
+	}
+	key := strings.TrimSuffix(strings.TrimPrefix(s, "__MSG_"), "__")
+	for k, m := range messages {
+		if strings.EqualFold(k, key) {
+			return m.Message
+		}
+	}
+	return s
+}
+
+func parseWebManifest(name string, r io.ReaderAt, size int64, o *options) (*AppInfo, error) {
+	var errs stageErrors
+	end := o.startStage(StageManifest)
+	m := new(webAppManifest)
+	err := json.NewDecoder(io.NewSectionReader(r, 0, size)).Decode(m)
+	end(err)
+	if err != nil {
+		errs.add(StageManifest, err)
+		return nil, errs.err()
+	}
+
+	info := newAppInfo(PlatformWeb)
+	info.Name = m.Name
+	if info.Name == "" {
+		info.Name = m.ShortName
+	}
+	info.BundleId = m.Id
+	if info.BundleId == "" {
+		info.BundleId = m.StartURL
+	}
+	info.Web.StartURL = m.StartURL
+	info.Web.Scope = m.Scope
+	info.Web.Display = m.Display
+	info.Web.ThemeColor = m.ThemeColor
+
+	// Icons are looked up next to the manifest; remote and SVG icons are
+	// not loaded.
+	var icon string
+	best := 0
+	for _, i := range m.Icons {
+		if strings.Contains(i.Src, "://") || i.Type == "image/svg+xml" || path.Ext(i.Src) == ".svg" {
+			continue
+		}
+		if n := iconSize(i.Sizes); icon == "" || n > best {
+			icon, best = i.Src, n
+		}
+	}
+	end = o.startStage(StageIcon)
+	img, err := decodeFileIcon(filepath.Dir(name), icon)
+	end(err)
+	errs.add(StageIcon, err)
+	info.setIcon(img)
+	return info, errs.err()
+}
+
+// iconSize returns the largest width of a sizes list such as "192x192
+// 512x512".
+func iconSize(sizes string) int {
+	best := 0
+	for _, s := range strings.Fields(sizes) {
+		w := strings.SplitN(strings.ToLower(s), "x", 2)[0]
+		if n, _ := strconv.Atoi(w); n > best {
+			best = n
+		}
+	}
+	return best
+}
+
+func decodeZipJSON(reader *zip.Reader, name string, v interface{}) error {
+	f := findZipFile(reader.File, name)
+	if f == nil {
+		return errors.New(name + " not found")
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return json.NewDecoder(rc).Decode(v)
+}
+
+func decodeZipIcon(reader *zip.Reader, name string) (image.Image, error) {
+	f := findZipFile(reader.File, path.Clean(strings.TrimPrefix(name, "/")))
+	if name == "" || f == nil {
+		return nil, ErrNoIcon
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	img, _, err := image.Decode(rc)
+	return img, err
+}
+
+func decodeFileIcon(dir, name string) (image.Image, error) {
+	if name == "" {
+		return nil, ErrNoIcon
+	}
+	f, err := os.Open(filepath.Join(dir, filepath.FromSlash(path.Clean("/"+name))))
+	if os.IsNotExist(err) {
+		return nil, ErrNoIcon
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	return img, err
+}
@@ -0,0 +1,123 @@
+package appfile
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeCrx(t *testing.T, name string, id []byte, files map[string][]byte) {
+	var z bytes.Buffer
+	w := zip.NewWriter(&z)
+	for n, b := range files {
+		f, err := w.Create(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write(b)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	header := pbField(10000, pbField(1, id))
+	buf := []byte("Cr24")
+	buf = binary.LittleEndian.AppendUint32(buf, 3)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(header)))
+	buf = append(append(buf, header...), z.Bytes()...)
+	if err := ioutil.WriteFile(name, buf, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseCrx(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "ext.crx")
+	id := []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0, 0, 0, 0, 0, 0, 0, 0xff}
+	writeCrx(t, name, id, map[string][]byte{
+		"manifest.json": []byte(`{
+			"manifest_version": 3,
+			"name": "__MSG_extName__",
+			"version": "1.4.0.2",
+			"version_name": "1.4 beta",
+			"default_locale": "en",
+			"icons": {"16": "icons/16.png", "128": "icons/128.png"},
+			"permissions": ["storage", "tabs"],
+			"host_permissions": ["https://*.example.com/*"]
+		}`),
+		"_locales/en/messages.json": []byte(`{"extname": {"message": "Example Extension"}}`),
+		"icons/128.png":             testIcon(t),
+	})
+
+	info, err := NewAppParser(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Platform != PlatformWeb || info.BundleId != "abcdefghijklmnopaaaaaaaaaaaaaapp" {
+		t.Errorf("got %v %v", info.Platform, info.BundleId)
+	}
+	if info.Name != "Example Extension" || info.Version != "1.4 beta" || info.Build != "1.4.0.2" {
+		t.Errorf("got %v %v %v", info.Name, info.Version, info.Build)
+	}
+	if info.Icon == nil {
+		t.Errorf("got no icon")
+	}
+	want := &WebInfo{
+		Extension:       true,
+		ManifestVersion: 3,
+		Permissions:     []string{"storage", "tabs", "https://*.example.com/*"},
+	}
+	if !reflect.DeepEqual(info.Web, want) {
+		t.Errorf("got %+v want %+v", info.Web, want)
+	}
+}
+
+func TestParseWebManifest(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "app.webmanifest")
+	if err := ioutil.WriteFile(name, []byte(`{
+		"short_name": "Example",
+		"start_url": "/?source=pwa",
+		"scope": "/",
+		"display": "standalone",
+		"theme_color": "#3367D6",
+		"icons": [
+			{"src": "/icons/logo.svg", "type": "image/svg+xml", "sizes": "any"},
+			{"src": "/icons/192.png", "type": "image/png", "sizes": "192x192"},
+			{"src": "https://cdn.example.com/1024.png", "sizes": "1024x1024"}
+		]
+	}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := NewAppParser(name)
+	if !errors.Is(err, ErrNoIcon) {
+		t.Errorf("got %v want %v", err, ErrNoIcon)
+	}
+	if info.Name != "Example" || info.BundleId != "/?source=pwa" {
+		t.Errorf("got %v %v", info.Name, info.BundleId)
+	}
+	want := &WebInfo{StartURL: "/?source=pwa", Scope: "/", Display: "standalone", ThemeColor: "#3367D6"}
+	if !reflect.DeepEqual(info.Web, want) {
+		t.Errorf("got %+v want %+v", info.Web, want)
+	}
+
+	if err := os.Mkdir(filepath.Join(dir, "icons"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "icons", "192.png"), testIcon(t), 0644); err != nil {
+		t.Fatal(err)
+	}
+	info, err = NewAppParser(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Icon == nil {
+		t.Errorf("got no icon")
+	}
+}
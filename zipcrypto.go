@@ -0,0 +1,289 @@
+package appfile
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"os"
+)
+
+var (
+	// ErrEncrypted is returned for archives with encrypted entries when no
+	// password is set with WithArchivePassword.
+	ErrEncrypted = errors.New("zip archive is encrypted")
+	// ErrPassword is returned when the archive password does not decrypt
+	// an entry.
+	ErrPassword = errors.New("wrong zip archive password")
+)
+
+const (
+	zipEncryptedFlag = 0x1
+	zipMethodAES     = 99
+	zipAESExtraID    = 0x9901
+)
+
+// WithArchivePassword decrypts archives whose entries are encrypted with
+// password, as some enterprise IPAs and APK wrappers are distributed.
+// Traditional PKWARE and WinZip AES encryption are supported.
+func WithArchivePassword(password string) Option {
+	return func(o *options) {
+		o.password = password
+	}
+}
+
+// zipEncrypted reports whether reader has an encrypted entry.
+func zipEncrypted(reader *zip.Reader) bool {
+	for _, f := range reader.File {
+		if f.Flags&zipEncryptedFlag != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// decryptZip rebuilds reader, an archive of size bytes, with every entry
+// decrypted with password, keeping their compressed data. The archive is
+// rebuilt in memory, or in a file in the spool directory when one is set,
+// which release removes.
+func decryptZip(reader *zip.Reader, size int64, password string, o *options) (_ *zip.Reader, release func(), err error) {
+	if o.maxSize > 0 && size > o.maxSize {
+		return nil, nil, ErrTooLarge
+	}
+	var buf bytes.Buffer
+	var out io.Writer = &buf
+	release = func() {}
+	if o.spoolDir != "" {
+		tmp, err := os.CreateTemp(o.spoolDir, "appfile-*.zip")
+		if err != nil {
+			return nil, nil, err
+		}
+		release = func() {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+		defer func() {
+			if err != nil {
+				release()
+			}
+		}()
+		out = tmp
+	}
+
+	w := zip.NewWriter(out)
+	for _, f := range reader.File {
+		fh := f.FileHeader
+		raw, err := f.OpenRaw()
+		if err != nil {
+			return nil, nil, err
+		}
+		if f.Flags&zipEncryptedFlag != 0 {
+			fh.Flags &^= zipEncryptedFlag
+			fh.Extra = nil
+			if raw, err = decryptEntry(f, raw, password, &fh); err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", f.Name, err)
+			}
+		}
+		fw, err := w.CreateRaw(&fh)
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, err := io.Copy(fw, raw); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, nil, err
+	}
+	if tmp, ok := out.(*os.File); ok {
+		stat, err := tmp.Stat()
+		if err != nil {
+			return nil, nil, err
+		}
+		plain, err := zip.NewReader(tmp, stat.Size())
+		return plain, release, err
+	}
+	plain, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	return plain, release, err
+}
+
+// decryptEntry returns the compressed data of f read from raw and sets the
+// method and compressed size of fh to those of the plain entry.
+func decryptEntry(f *zip.File, raw io.Reader, password string, fh *zip.FileHeader) (io.Reader, error) {
+	if f.Method != zipMethodAES {
+		var header [12]byte
+		if _, err := io.ReadFull(raw, header[:]); err != nil {
+			return nil, err
+		}
+		s := newZipCrypto(password)
+		s.XORKeyStream(header[:], header[:])
+		check := byte(f.CRC32 >> 24)
+		if f.Flags&0x8 != 0 {
+			check = byte(f.ModifiedTime >> 8)
+		}
+		if header[11] != check {
+			return nil, ErrPassword
+		}
+		fh.CompressedSize64 -= 12
+		return cipher.StreamReader{S: s, R: raw}, nil
+	}
+
+	strength, method, ok := zipAESExtra(f.Extra)
+	if !ok || strength < 1 || strength > 3 {
+		return nil, zip.ErrAlgorithm
+	}
+	keyLen := 8 + 8*int(strength)
+	salt := make([]byte, keyLen/2)
+	var verifier [2]byte
+	if _, err := io.ReadFull(raw, salt); err != nil {
+		return nil, err
+	}
+	if _, err := io.ReadFull(raw, verifier[:]); err != nil {
+		return nil, err
+	}
+	key := pbkdf2SHA1([]byte(password), salt, 1000, 2*keyLen+2)
+	if subtle.ConstantTimeCompare(key[2*keyLen:], verifier[:]) != 1 {
+		return nil, ErrPassword
+	}
+	block, err := aes.NewCipher(key[:keyLen])
+	if err != nil {
+		return nil, err
+	}
+	size := int64(fh.CompressedSize64) - int64(len(salt)) - 2 - 10
+	if size < 0 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	fh.Method, fh.CompressedSize64 = method, uint64(size)
+	mac := hmac.New(sha1.New, key[keyLen:2*keyLen])
+	return &zipAESReader{
+		r:    cipher.StreamReader{S: &zipAESStream{block: block}, R: io.TeeReader(io.LimitReader(raw, size), mac)},
+		raw:  raw,
+		mac:  mac,
+		todo: size,
+	}, nil
+}
+
+// zipAESExtra returns the key strength and the actual compression method
+// from the WinZip AES extra field.
+func zipAESExtra(extra []byte) (strength byte, method uint16, ok bool) {
+	for len(extra) >= 4 {
+		id, n := le.Uint16(extra), int(le.Uint16(extra[2:]))
+		if 4+n > len(extra) {
+			break
+		}
+		if id == zipAESExtraID && n >= 7 {
+			return extra[8], le.Uint16(extra[9:]), true
+		}
+		extra = extra[4+n:]
+	}
+	return 0, 0, false
+}
+
+// zipAESReader decrypts an AES entry and checks its authentication code,
+// which follows the data, at the end.
+type zipAESReader struct {
+	r    io.Reader
+	raw  io.Reader
+	mac  hash.Hash
+	todo int64
+}
+
+func (z *zipAESReader) Read(p []byte) (int, error) {
+	n, err := z.r.Read(p)
+	z.todo -= int64(n)
+	if err == io.EOF {
+		if z.todo > 0 {
+			return n, io.ErrUnexpectedEOF
+		}
+		var code [10]byte
+		if _, err := io.ReadFull(z.raw, code[:]); err != nil {
+			return n, err
+		}
+		if !hmac.Equal(z.mac.Sum(nil)[:10], code[:]) {
+			return n, zip.ErrChecksum
+		}
+	}
+	return n, err
+}
+
+// zipAESStream is AES in the counter mode of WinZip, whose counter starts
+// at 1 and is little-endian.
+type zipAESStream struct {
+	block   cipher.Block
+	counter uint64
+	stream  [aes.BlockSize]byte
+	used    int
+}
+
+func (s *zipAESStream) XORKeyStream(dst, src []byte) {
+	for i := range src {
+		if s.used == 0 || s.used == aes.BlockSize {
+			s.counter++
+			var ctr [aes.BlockSize]byte
+			binary.LittleEndian.PutUint64(ctr[:], s.counter)
+			s.block.Encrypt(s.stream[:], ctr[:])
+			s.used = 0
+		}
+		dst[i] = src[i] ^ s.stream[s.used]
+		s.used++
+	}
+}
+
+// zipCrypto is the traditional PKWARE stream cipher, decrypting.
+type zipCrypto struct {
+	keys [3]uint32
+}
+
+func newZipCrypto(password string) *zipCrypto {
+	z := &zipCrypto{keys: [3]uint32{0x12345678, 0x23456789, 0x34567890}}
+	for i := 0; i < len(password); i++ {
+		z.update(password[i])
+	}
+	return z
+}
+
+func (z *zipCrypto) update(b byte) {
+	z.keys[0] = crc32.IEEETable[byte(z.keys[0])^b] ^ z.keys[0]>>8
+	z.keys[1] = (z.keys[1]+z.keys[0]&0xff)*134775813 + 1
+	z.keys[2] = crc32.IEEETable[byte(z.keys[2])^byte(z.keys[1]>>24)] ^ z.keys[2]>>8
+}
+
+func (z *zipCrypto) XORKeyStream(dst, src []byte) {
+	for i, c := range src {
+		t := z.keys[2] | 2
+		dst[i] = c ^ byte(t*(t^1)>>8)
+		z.update(dst[i])
+	}
+}
+
+// pbkdf2SHA1 derives a key of n bytes as in RFC 8018.
+func pbkdf2SHA1(password, salt []byte, iter, n int) []byte {
+	prf := hmac.New(sha1.New, password)
+	var key []byte
+	for block := uint32(1); len(key) < n; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write(binary.BigEndian.AppendUint32(nil, block))
+		u := prf.Sum(nil)
+		t := append([]byte(nil), u...)
+		for i := 1; i < iter; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:n]
+}
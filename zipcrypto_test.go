@@ -0,0 +1,182 @@
+package appfile
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"crypto/aes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"hash/crc32"
+	"os"
+	"strings"
+	"testing"
+)
+
+// encryptedZip returns an archive with Info.plist encrypted with the
+// traditional PKWARE cipher and classes.dex with AES-256, both with the
+// password "secret".
+func encryptedZip(t *testing.T) []byte {
+	return encryptZip(t, []encryptedEntry{
+		{"Payload/App.app/Info.plist", "plist", false},
+		{"classes.dex", "dex\n035\x00", true},
+	})
+}
+
+type encryptedEntry struct {
+	name, content string
+	aes           bool
+}
+
+// encryptZip returns an archive of entries encrypted with the password
+// "secret", the traditional PKWARE cipher unless aes is set.
+func encryptZip(t *testing.T, entries []encryptedEntry) []byte {
+	const password = "secret"
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, e := range entries {
+		var deflated bytes.Buffer
+		fw, _ := flate.NewWriter(&deflated, flate.DefaultCompression)
+		fw.Write([]byte(e.content))
+		fw.Close()
+		fh := &zip.FileHeader{
+			Name:               e.name,
+			Flags:              zipEncryptedFlag,
+			Method:             zip.Deflate,
+			CRC32:              crc32.ChecksumIEEE([]byte(e.content)),
+			UncompressedSize64: uint64(len(e.content)),
+		}
+
+		var data []byte
+		if e.aes {
+			salt := bytes.Repeat([]byte{7}, 16)
+			key := pbkdf2SHA1([]byte(password), salt, 1000, 66)
+			block, _ := aes.NewCipher(key[:32])
+			enc := make([]byte, deflated.Len())
+			(&zipAESStream{block: block}).XORKeyStream(enc, deflated.Bytes())
+			mac := hmac.New(sha1.New, key[32:64])
+			mac.Write(enc)
+			data = append(append(append(salt, key[64:]...), enc...), mac.Sum(nil)[:10]...)
+			fh.Method, fh.CRC32 = zipMethodAES, 0
+			fh.Extra = []byte{0x01, 0x99, 7, 0, 2, 0, 'A', 'E', 3, byte(zip.Deflate), 0}
+		} else {
+			plain := append(make([]byte, 11), byte(fh.CRC32>>24))
+			plain = append(plain, deflated.Bytes()...)
+			z := newZipCrypto(password)
+			for _, b := range plain {
+				t := z.keys[2] | 2
+				data = append(data, b^byte(t*(t^1)>>8))
+				z.update(b)
+			}
+		}
+		fh.CompressedSize64 = uint64(len(data))
+		f, err := w.CreateRaw(fh)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write(data)
+	}
+	w.Close()
+	return buf.Bytes()
+}
+
+func TestDecryptZip(t *testing.T) {
+	b := encryptedZip(t)
+	reader, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !zipEncrypted(reader) {
+		t.Fatalf("got not encrypted want encrypted")
+	}
+
+	plain, release, err := decryptZip(reader, int64(len(b)), "secret", newOptions(nil))
+	if err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	defer release()
+	for name, want := range map[string]string{"Payload/App.app/Info.plist": "plist", "classes.dex": "dex\n035\x00"} {
+		buf, err := readZipFile(findZipFile(plain.File, name))
+		if err != nil || string(buf) != want {
+			t.Errorf("got %q %v want %q", buf, err, want)
+		}
+	}
+	if zipEncrypted(plain) {
+		t.Errorf("got encrypted want decrypted")
+	}
+
+	if _, _, err := decryptZip(reader, int64(len(b)), "wrong", newOptions(nil)); !errors.Is(err, ErrPassword) {
+		t.Errorf("got %v want %v", err, ErrPassword)
+	}
+	if _, _, err := decryptZip(reader, int64(len(b)), "secret", newOptions([]Option{WithMaxSize(int64(len(b) - 1))})); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("got %v want %v", err, ErrTooLarge)
+	}
+
+	// With a spool directory the decrypted archive is a file there, removed
+	// on release.
+	dir := t.TempDir()
+	spooled, release, err := decryptZip(reader, int64(len(b)), "secret", newOptions([]Option{WithSpoolDir(dir)}))
+	if err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	if buf, err := readZipFile(findZipFile(spooled.File, "classes.dex")); err != nil || string(buf) != "dex\n035\x00" {
+		t.Errorf("got %q %v want %q", buf, err, "dex\n035\x00")
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("got %d files want the spooled archive", len(entries))
+	}
+	release()
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("got %d files want none after release", len(entries))
+	}
+}
+
+func TestZipFormatEncrypted(t *testing.T) {
+	b := encryptedZip(t)
+	f := zipFormat(func(_ string, reader *zip.Reader, _ *options) (*AppInfo, error) {
+		return newAppInfo(PlatformIOS), nil
+	})
+	if _, err := f.parse("app.ipa", bytes.NewReader(b), int64(len(b)), newOptions(nil)); !errors.Is(err, ErrEncrypted) {
+		t.Errorf("got %v want %v", err, ErrEncrypted)
+	}
+	if _, err := f.parse("app.ipa", bytes.NewReader(b), int64(len(b)), newOptions([]Option{WithArchivePassword("secret")})); err != nil {
+		t.Errorf("got %v want no error", err)
+	}
+}
+
+func TestPBKDF2SHA1(t *testing.T) {
+	// RFC 6070
+	got := hex.EncodeToString(pbkdf2SHA1([]byte("password"), []byte("salt"), 4096, 20))
+	if want := "4b007901b765489abead49d926f721d065a429c1"; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestParseEncryptedApk(t *testing.T) {
+	reader, err := getAppZipReader("testdata/helloworld.apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []encryptedEntry
+	for _, f := range reader.File {
+		if strings.HasSuffix(f.Name, "/") {
+			continue
+		}
+		buf, err := readZipFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, encryptedEntry{name: f.Name, content: string(buf)})
+	}
+	name := writeFile(t, "encrypted.apk", encryptZip(t, entries))
+
+	info, err := NewAppParser(name, WithArchivePassword("secret"))
+	if err != nil {
+		t.Fatalf("got %v want no error", err)
+	}
+	if info.Name != "HelloWorld" || info.Icon == nil {
+		t.Errorf("got %v %v want %v and an icon", info.Name, info.Icon, "HelloWorld")
+	}
+}